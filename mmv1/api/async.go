@@ -22,6 +22,9 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// The values allowed for an Async's `type`.
+var AsyncTypes = []string{"OpAsync", "PollAsync"}
+
 // Base class from which other Async classes can inherit.
 type Async struct {
 	// Describes an operation, one of "OpAsync", "PollAsync"
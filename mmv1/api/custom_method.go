@@ -0,0 +1,225 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// The values allowed for a custom method's `verb`.
+var CustomMethodVerbs = []string{"POST", "PUT", "PATCH", "DELETE"}
+
+// CustomMethod describes a custom method of a resource, such as `:restart` or
+// `:failover`, which is generated as a plugin framework Terraform action.
+type CustomMethod struct {
+	// The name of the method in snake case, such as `restart`. The action is
+	// named after the resource and the method, such as
+	// `google_alloydb_instance_restart`.
+	Name string `yaml:"name"`
+
+	// Description of the action. Used in documentation.
+	Description string `yaml:"description"`
+
+	// The suffix appended to the self link of the resource to build the URL of
+	// the method. Defaults to `:` followed by the name of the method in lower
+	// camel case, such as `:restart`.
+	UrlSuffix string `yaml:"url_suffix,omitempty"`
+
+	// The HTTP verb of the method. Defaults to POST.
+	Verb string `yaml:"verb,omitempty"`
+
+	// The fields of the request body. Fields of the self link of the resource
+	// are added to the action and don't need to be declared.
+	Properties []*Type `yaml:"properties,omitempty"`
+
+	// If true, the method returns a long-running operation, which the action
+	// waits for with the operation waiter of the product.
+	ReturnsOperation bool `yaml:"returns_operation,omitempty"`
+
+	// How long the action waits for the method to complete, in minutes.
+	// Defaults to the resource's update timeout.
+	TimeoutMinutes int `yaml:"timeout_minutes,omitempty"`
+
+	// The minimum API version the action is available in.
+	MinVersion string `yaml:"min_version,omitempty"`
+
+	// If true, the action isn't generated.
+	Exclude bool `yaml:"exclude,omitempty"`
+
+	// If true, tests aren't generated for the action. Tests are only
+	// generated for methods without required request body fields, and run
+	// the action after creating the resource of the first test sample.
+	ExcludeTest bool `yaml:"exclude_test,omitempty"`
+
+	ResourceMetadata *Resource `yaml:"-"`
+}
+
+func (m *CustomMethod) SetDefault(r *Resource) {
+	m.ResourceMetadata = r
+	if m.UrlSuffix == "" {
+		m.UrlSuffix = ":" + google.Camelize(m.Name, "lower")
+	}
+	if m.Verb == "" {
+		m.Verb = "POST"
+	}
+	if m.TimeoutMinutes == 0 {
+		m.TimeoutMinutes = r.GetTimeouts().UpdateMinutes
+	}
+	for _, p := range m.Properties {
+		// Request body fields are expanded by functions named after the
+		// action, so they don't clash with those of the resource's fields.
+		p.Prefix = m.ActionName()
+		p.SetDefault(r)
+	}
+}
+
+func (m *CustomMethod) Validate(rName string) (es []error) {
+	if m.Name == "" {
+		es = append(es, fmt.Errorf("missing `name` for custom method in resource %s", rName))
+	}
+	if m.Description == "" {
+		es = append(es, utils.PrefixYamlPath("description")(fmt.Errorf("missing `description` for custom method %s in resource %s", m.Name, rName)))
+	}
+	if !slices.Contains(CustomMethodVerbs, m.Verb) {
+		es = append(es, utils.PrefixYamlPath("verb")(fmt.Errorf("value on `verb` should be one of %#v", CustomMethodVerbs)))
+	}
+
+	identifiers := m.UrlFields()
+	for _, p := range m.Properties {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("properties", p.Name), p.Validate(rName))...)
+		if slices.Contains(identifiers, google.Underscore(p.Name)) {
+			es = append(es, utils.PrefixYamlPath("properties", p.Name)(fmt.Errorf("property %s of custom method %s is already a field of the URL in resource %s", p.Name, m.Name, rName)))
+		}
+		if p.Output || p.UrlParamOnly || p.DefaultFromApi {
+			es = append(es, utils.PrefixYamlPath("properties", p.Name)(fmt.Errorf("property %s of custom method %s must be a request field, and can't be `output`, `url_param_only` or `default_from_api` in resource %s", p.Name, m.Name, rName)))
+		}
+		if p.IsA("Map") {
+			es = append(es, utils.PrefixYamlPath("properties", p.Name)(fmt.Errorf("property %s of custom method %s can't be a Map in resource %s", p.Name, m.Name, rName)))
+		}
+	}
+	return es
+}
+
+// ActionName returns the name of the action in upper camel case, used to name
+// its Go types and functions.
+func (m CustomMethod) ActionName() string {
+	return m.ResourceMetadata.ResourceName() + google.Camelize(m.Name, "upper")
+}
+
+// TerraformName returns the name of the action in Terraform configurations.
+func (m CustomMethod) TerraformName() string {
+	return fmt.Sprintf("%s_%s", m.ResourceMetadata.TerraformName(), google.Underscore(m.Name))
+}
+
+// Url returns the URL of the method, relative to the product's base URL.
+func (m CustomMethod) Url() string {
+	return m.ResourceMetadata.SelfLinkUri() + m.UrlSuffix
+}
+
+// UrlFields returns the fields of the method's URL, which are arguments of
+// the action.
+func (m CustomMethod) UrlFields() []string {
+	return m.ResourceMetadata.ExtractIdentifiers(m.Url())
+}
+
+// RequiredUrlFields returns the fields of the method's URL that should be
+// marked as "Required" on the action.
+func (m CustomMethod) RequiredUrlFields() []string {
+	requiredFields := []string{}
+
+	for _, field := range m.UrlFields() {
+		if field != "region" && field != "project" && field != "zone" {
+			requiredFields = append(requiredFields, field)
+		}
+	}
+	return requiredFields
+}
+
+// OptionalUrlFields returns the fields of the method's URL that should be
+// marked as "Optional" on the action, as they default to the provider's values.
+func (m CustomMethod) OptionalUrlFields() []string {
+	optionalFields := []string{}
+
+	for _, field := range m.UrlFields() {
+		if field == "region" || field == "project" || field == "zone" {
+			optionalFields = append(optionalFields, field)
+		}
+	}
+	return optionalFields
+}
+
+// NotInVersion returns true if the action isn't available in the version.
+func (m CustomMethod) NotInVersion(version *product.Version) bool {
+	if m.MinVersion == "" {
+		return m.ResourceMetadata.NotInVersion(version)
+	}
+	return version.CompareTo(m.ResourceMetadata.ProductMetadata.versionObj(m.MinVersion)) < 0
+}
+
+// ExcludeIfNotInVersion excludes the method, and the request body fields of
+// the method, that aren't available in the version.
+func (m *CustomMethod) ExcludeIfNotInVersion(version *product.Version) {
+	if !m.Exclude {
+		m.Exclude = m.NotInVersion(version)
+	}
+	for _, p := range m.Properties {
+		p.ExcludeIfNotInVersion(version)
+	}
+}
+
+// ShouldGenerateTests returns whether tests are generated for the action.
+func (m CustomMethod) ShouldGenerateTests() bool {
+	return !m.ExcludeTest && len(m.RequiredProperties()) == 0 && len(m.ResourceMetadata.TestSamples()) > 0
+}
+
+// UserProperties returns the request body fields of the method available in
+// the version being generated.
+func (m CustomMethod) UserProperties() []*Type {
+	return google.Reject(m.Properties, func(p *Type) bool { return p.Exclude })
+}
+
+// RequiredProperties returns the required request body fields of the method.
+func (m CustomMethod) RequiredProperties() []*Type {
+	return google.Select(m.UserProperties(), func(p *Type) bool { return p.Required })
+}
+
+// OptionalProperties returns the optional request body fields of the method.
+func (m CustomMethod) OptionalProperties() []*Type {
+	return google.Reject(m.UserProperties(), func(p *Type) bool { return p.Required })
+}
+
+// RequestLogRedactedFields returns the dotted API paths of the sensitive and
+// write-only request body fields of the method, whose values are redacted from
+// the request log.
+func (m CustomMethod) RequestLogRedactedFields() []string {
+	var paths []string
+	for _, prop := range m.ResourceMetadata.AllNestedProperties(m.UserProperties()) {
+		if !prop.Sensitive && !prop.WriteOnly && !prop.WriteOnlyLegacy {
+			continue
+		}
+		path := strings.Join(prop.RequestLogPath(), ".")
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,221 @@
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+)
+
+func TestCustomMethodSetDefault(t *testing.T) {
+	t.Parallel()
+
+	version := &product.Version{Name: "ga", BaseUrl: "https://alloydb.googleapis.com/v1/"}
+	p := &api.Product{Name: "Alloydb", Versions: []*product.Version{version}, Version: version}
+	r := &api.Resource{
+		Name:            "Instance",
+		BaseUrl:         "projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/instances",
+		ProductMetadata: p,
+	}
+
+	cases := []struct {
+		description      string
+		obj              api.CustomMethod
+		wantActionName   string
+		wantTerraform    string
+		wantUrl          string
+		wantVerb         string
+		wantRequired     []string
+		wantOptional     []string
+		wantPropExpander string
+	}{
+		{
+			description:    "defaults",
+			obj:            api.CustomMethod{Name: "restart"},
+			wantActionName: "AlloydbInstanceRestart",
+			wantTerraform:  "google_alloydb_instance_restart",
+			wantUrl:        "projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/instances/{{name}}:restart",
+			wantVerb:       "POST",
+			wantRequired:   []string{"location", "cluster", "name"},
+			wantOptional:   []string{"project"},
+		},
+		{
+			description:      "snake case name with overrides",
+			obj:              api.CustomMethod{Name: "inject_fault", UrlSuffix: ":fault", Verb: "PUT", Properties: []*api.Type{{Name: "faultType", Type: "String"}}},
+			wantActionName:   "AlloydbInstanceInjectFault",
+			wantTerraform:    "google_alloydb_instance_inject_fault",
+			wantUrl:          "projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/instances/{{name}}:fault",
+			wantVerb:         "PUT",
+			wantRequired:     []string{"location", "cluster", "name"},
+			wantOptional:     []string{"project"},
+			wantPropExpander: "AlloydbInstanceInjectFault",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			m := tc.obj
+			m.SetDefault(r)
+
+			if got := m.ActionName(); got != tc.wantActionName {
+				t.Errorf("ActionName() = %q, want %q", got, tc.wantActionName)
+			}
+			if got := m.TerraformName(); got != tc.wantTerraform {
+				t.Errorf("TerraformName() = %q, want %q", got, tc.wantTerraform)
+			}
+			if got := m.Url(); got != tc.wantUrl {
+				t.Errorf("Url() = %q, want %q", got, tc.wantUrl)
+			}
+			if m.Verb != tc.wantVerb {
+				t.Errorf("Verb = %q, want %q", m.Verb, tc.wantVerb)
+			}
+			if diff := cmp.Diff(tc.wantRequired, m.RequiredUrlFields()); diff != "" {
+				t.Errorf("RequiredUrlFields() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOptional, m.OptionalUrlFields()); diff != "" {
+				t.Errorf("OptionalUrlFields() mismatch (-want +got):\n%s", diff)
+			}
+			for _, prop := range m.Properties {
+				if got := prop.GetPrefix(); got != tc.wantPropExpander {
+					t.Errorf("GetPrefix() of %s = %q, want %q", prop.Name, got, tc.wantPropExpander)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomMethodValidate(t *testing.T) {
+	t.Parallel()
+
+	version := &product.Version{Name: "ga", BaseUrl: "https://alloydb.googleapis.com/v1/"}
+	p := &api.Product{Name: "Alloydb", Versions: []*product.Version{version}, Version: version}
+	r := &api.Resource{
+		Name:            "Instance",
+		BaseUrl:         "projects/{{project}}/locations/{{location}}/instances",
+		ProductMetadata: p,
+	}
+
+	cases := []struct {
+		description string
+		obj         api.CustomMethod
+		wantErr     string
+	}{
+		{
+			description: "valid",
+			obj:         api.CustomMethod{Name: "restart", Description: "Restarts the instance.", Properties: []*api.Type{{Name: "validateOnly", Type: "Boolean", Description: "d"}}},
+		},
+		{
+			description: "missing description",
+			obj:         api.CustomMethod{Name: "restart"},
+			wantErr:     "missing `description`",
+		},
+		{
+			description: "unsupported verb",
+			obj:         api.CustomMethod{Name: "restart", Description: "d", Verb: "GET"},
+			wantErr:     "value on `verb` should be one of",
+		},
+		{
+			description: "property clashing with the URL",
+			obj:         api.CustomMethod{Name: "restart", Description: "d", Properties: []*api.Type{{Name: "location", Type: "String", Description: "d"}}},
+			wantErr:     "already a field of the URL",
+		},
+		{
+			description: "output property",
+			obj:         api.CustomMethod{Name: "restart", Description: "d", Properties: []*api.Type{{Name: "state", Type: "String", Description: "d", Output: true}}},
+			wantErr:     "must be a request field",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			m := tc.obj
+			m.SetDefault(r)
+			errs := m.Validate(r.Name)
+			if tc.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("Validate() = %v, want no errors", errs)
+				}
+				return
+			}
+			found := false
+			for _, err := range errs {
+				if strings.Contains(err.Error(), tc.wantErr) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Validate() = %v, want an error containing %q", errs, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomMethodExcludeIfNotInVersion(t *testing.T) {
+	t.Parallel()
+
+	ga := &product.Version{Name: "ga", BaseUrl: "https://alloydb.googleapis.com/v1/"}
+	beta := &product.Version{Name: "beta", BaseUrl: "https://alloydb.googleapis.com/v1beta/"}
+	p := &api.Product{Name: "Alloydb", Versions: []*product.Version{ga, beta}, Version: ga}
+	r := &api.Resource{
+		Name:            "Instance",
+		BaseUrl:         "projects/{{project}}/locations/{{location}}/instances",
+		ProductMetadata: p,
+		CustomMethods: []*api.CustomMethod{
+			{Name: "restart", Description: "d"},
+			{Name: "failover", Description: "d", MinVersion: "beta"},
+		},
+	}
+	for _, m := range r.CustomMethods {
+		m.SetDefault(r)
+	}
+
+	r.ExcludeIfNotInVersion(ga)
+
+	var got []string
+	for _, m := range r.UserCustomMethods() {
+		got = append(got, m.Name)
+	}
+	if diff := cmp.Diff([]string{"restart"}, got); diff != "" {
+		t.Errorf("UserCustomMethods() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCustomMethodRequestLogRedactedFields(t *testing.T) {
+	t.Parallel()
+
+	version := &product.Version{Name: "ga", BaseUrl: "https://sqladmin.googleapis.com/v1/"}
+	p := &api.Product{Name: "Sql", Versions: []*product.Version{version}, Version: version}
+	r := &api.Resource{Name: "User", BaseUrl: "projects/{{project}}/users", ProductMetadata: p}
+	m := api.CustomMethod{
+		Name: "reset_password",
+		Properties: []*api.Type{
+			{Name: "password", Type: "String", Sensitive: true},
+			{
+				Name: "options",
+				Type: "NestedObject",
+				Properties: []*api.Type{
+					{Name: "token", Type: "String", WriteOnly: true},
+					{Name: "reason", Type: "String"},
+				},
+			},
+			{Name: "validateOnly", Type: "Boolean"},
+		},
+	}
+	m.SetDefault(r)
+
+	want := []string{"options.token", "password"}
+	if diff := cmp.Diff(want, m.RequestLogRedactedFields()); diff != "" {
+		t.Errorf("RequestLogRedactedFields() mismatch (-want +got):\n%s", diff)
+	}
+}
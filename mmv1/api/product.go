@@ -76,6 +76,10 @@ type Product struct {
 	// available. Changing this requires REP to be supported in *ALL* regions
 	RepByDefault bool `yaml:"rep_by_default,omitempty"`
 
+	// If true, provider functions parsing and building the ids of every
+	// resource of the product are generated.
+	GenerateIdFunctions bool `yaml:"generate_id_functions,omitempty"`
+
 	// The version of the product which is currently being generated.
 	Version *product.Version `yaml:"-"`
 
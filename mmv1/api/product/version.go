@@ -26,10 +26,10 @@ var ORDER = []string{"ga", "beta", "nightly", "alpha", "private", "internal"}
 // a superset of beta, and beta a superset of GA. Each version will have a
 // different version url.
 type Version struct {
+	Name             string
 	CaiBaseUrl       string `yaml:"cai_base_url,omitempty"`
 	CaiLegacyBaseUrl string `yaml:"cai_legacy_base_url,omitempty"`
 	BaseUrl          string `yaml:"base_url"`
-	Name             string
 	RepUrl           string `yaml:"rep_url,omitempty"`
 
 	// EXPERIMENTAL: RPC settings are not fully implemented, and should not be
@@ -251,6 +251,23 @@ type Resource struct {
 	// EXPERIMENTAL: If true, resource should be autogenerated as a data source
 	Datasource *resource.Datasource `yaml:"datasource_experimental,omitempty"`
 
+	// If set, a plural data source listing every instance of the resource in
+	// the scope of its collection URL is generated.
+	PluralDatasource *resource.PluralDatasource `yaml:"plural_datasource,omitempty"`
+
+	// If set, the resource is generated as a plugin framework ephemeral
+	// resource instead of a managed resource.
+	Ephemeral *resource.Ephemeral `yaml:"ephemeral,omitempty"`
+
+	// Custom methods of the resource, such as `:restart` or `:failover`, each
+	// generated as a plugin framework action.
+	CustomMethods []*CustomMethod `yaml:"custom_methods,omitempty"`
+
+	// If true, provider functions parsing and building the ids of the
+	// resource, such as `provider::google::parse_compute_instance_id`, are
+	// generated. Products can enable them for all of their resources.
+	GenerateIdFunctions bool `yaml:"generate_id_functions,omitempty"`
+
 	GenerateListResource bool `yaml:"generate_list_resource,omitempty"`
 
 	// [Optional] A static filter string appended as a ?filter= query parameter when
@@ -261,6 +278,10 @@ type Resource struct {
 	// If true, skip sweeper generation for this resource
 	ExcludeSweeper bool `yaml:"exclude_sweeper,omitempty"`
 
+	// If true, skip generating the unit test flattening API values of the
+	// fields of this resource and expanding them back.
+	ExcludeRoundTripTests bool `yaml:"exclude_round_trip_tests,omitempty"`
+
 	// If true, skip identity generation for this resource
 	ExcludeIdentityGeneration bool `yaml:"exclude_identity_generation,omitempty"`
 
@@ -359,8 +380,9 @@ type Resource struct {
 	// If true, generates product operation handling logic.
 	AutogenAsync bool `yaml:"autogen_async,omitempty"`
 
-	// EXPERIMENTAL: this is an incomplete feature and may have several build errors.
-	// If true, this resource generates with the new plugin framework resource template
+	// If true, this resource generates with the plugin framework resource
+	// template instead of the SDK one. Features that only exist in the SDK,
+	// such as custom diffs and state upgraders, can't be used with it.
 	FrameworkResource bool `yaml:"plugin_framework_experimental,omitempty"`
 
 	ProductMetadata *Product `yaml:"-"`
@@ -541,25 +563,39 @@ func (r *Resource) SetDefault(product *Product) {
 	r.setShallowDefaults() // Set defaults for the current level.
 
 	r.ProductMetadata = product
+	if r.Ephemeral != nil {
+		r.Ephemeral.SetDefault(r.CreateUri(), r.CreateVerb)
+	}
 	for _, property := range r.AllProperties() {
 		property.SetDefault(r)
 	}
 	for _, vf := range r.VirtualFields {
 		vf.SetDefault(r)
 	}
+	for _, m := range r.CustomMethods {
+		m.SetDefault(r)
+	}
 
 	if r.IamPolicy != nil && r.DeprecationMessage != "" && r.IamPolicy.DeprecationMessage == "" {
 		r.IamPolicy.DeprecationMessage = fmt.Sprintf("The parent resource has been deprecated: %v", r.DeprecationMessage)
 	}
 }
 
+// The values allowed for each of a resource's HTTP verbs.
+var (
+	CreateVerbs = []string{"POST", "PUT", "PATCH"}
+	ReadVerbs   = []string{"GET", "POST"}
+	DeleteVerbs = []string{"POST", "PUT", "PATCH", "DELETE"}
+	UpdateVerbs = []string{"POST", "PUT", "PATCH"}
+)
+
 func (r *Resource) Validate() (es []error) {
 	if r.Name == "" {
 		es = append(es, fmt.Errorf("missing `name` for resource"))
 	}
 
 	if r.NestedQuery != nil && r.NestedQuery.IsListOfIds && len(r.Identity) != 1 {
-		es = append(es, fmt.Errorf("`is_list_of_ids: true` implies resource has exactly one `identity` property"))
+		es = append(es, utils.PrefixYamlPath("nested_query", "is_list_of_ids")(fmt.Errorf("`is_list_of_ids: true` implies resource has exactly one `identity` property")))
 	}
 
 	// Ensures we have all properties defined
@@ -568,7 +604,7 @@ func (r *Resource) Validate() (es []error) {
 			return p.Name == i
 		})
 		if !hasIdentify {
-			es = append(es, fmt.Errorf("missing property/parameter for identity %s", i))
+			es = append(es, utils.PrefixYamlPath("identity")(fmt.Errorf("missing property/parameter for identity %s", i)))
 		}
 	}
 
@@ -582,36 +618,36 @@ func (r *Resource) Validate() (es []error) {
 		}
 	}
 
-	allowed := []string{"POST", "PUT", "PATCH"}
-	if !slices.Contains(allowed, r.CreateVerb) {
-		es = append(es, fmt.Errorf("value on `create_verb` should be one of %#v", allowed))
+	if !slices.Contains(CreateVerbs, r.CreateVerb) {
+		es = append(es, utils.PrefixYamlPath("create_verb")(fmt.Errorf("value on `create_verb` should be one of %#v", CreateVerbs)))
 	}
 
-	allowed = []string{"GET", "POST"}
-	if !slices.Contains(allowed, r.ReadVerb) {
-		es = append(es, fmt.Errorf("value on `read_verb` should be one of %#v", allowed))
+	if !slices.Contains(ReadVerbs, r.ReadVerb) {
+		es = append(es, utils.PrefixYamlPath("read_verb")(fmt.Errorf("value on `read_verb` should be one of %#v", ReadVerbs)))
 	}
 
-	allowed = []string{"POST", "PUT", "PATCH", "DELETE"}
-	if !slices.Contains(allowed, r.DeleteVerb) {
-		es = append(es, fmt.Errorf("value on `delete_verb` should be one of %#v", allowed))
+	if !slices.Contains(DeleteVerbs, r.DeleteVerb) {
+		es = append(es, utils.PrefixYamlPath("delete_verb")(fmt.Errorf("value on `delete_verb` should be one of %#v", DeleteVerbs)))
 	}
 
-	allowed = []string{"POST", "PUT", "PATCH"}
-	if !slices.Contains(allowed, r.UpdateVerb) {
-		es = append(es, fmt.Errorf("value on `update_verb` should be one of %#v", allowed))
+	if !slices.Contains(UpdateVerbs, r.UpdateVerb) {
+		es = append(es, utils.PrefixYamlPath("update_verb")(fmt.Errorf("value on `update_verb` should be one of %#v", UpdateVerbs)))
 	}
 
-	for _, property := range r.AllProperties() {
-		es = append(es, property.Validate(r.Name)...)
+	for _, property := range r.Properties {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("properties", property.Name), property.Validate(r.Name))...)
+	}
+
+	for _, parameter := range r.Parameters {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("parameters", parameter.Name), parameter.Validate(r.Name))...)
 	}
 
 	if r.IamPolicy != nil {
-		es = append(es, r.IamPolicy.Validate(r.Name)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("iam_policy"), r.IamPolicy.Validate(r.Name))...)
 	}
 
 	if r.NestedQuery != nil {
-		es = append(es, r.NestedQuery.Validate(r.Name)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("nested_query"), r.NestedQuery.Validate(r.Name))...)
 	}
 
 	if r.Examples != nil {
@@ -619,12 +655,123 @@ func (r *Resource) Validate() (es []error) {
 	}
 
 	for _, sample := range r.Samples {
-		es = append(es, sample.Validate(r.Name)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("samples", sample.Name), sample.Validate(r.Name))...)
+	}
+
+	if r.FrameworkResource {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("plugin_framework_experimental"), r.validateFrameworkResource())...)
+	}
+
+	if r.Ephemeral != nil {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("ephemeral"), r.Ephemeral.Validate(r.Name))...)
+		es = append(es, r.validateEphemeral()...)
+	}
+
+	for _, m := range r.CustomMethods {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("custom_methods", m.Name), m.Validate(r.Name))...)
+		if m.ReturnsOperation && (r.GetAsync() == nil || !r.GetAsync().IsA("OpAsync")) {
+			es = append(es, utils.PrefixYamlPath("custom_methods", m.Name, "returns_operation")(fmt.Errorf("`returns_operation` requires an operation-based `async`, which resource %s doesn't have", r.Name)))
+		}
+	}
+
+	if r.ShouldGeneratePluralDataSource() {
+		if r.ExcludeRead {
+			es = append(es, utils.PrefixYamlPath("plural_datasource")(fmt.Errorf("`plural_datasource` requires read support, but resource %s sets `exclude_read`", r.Name)))
+		}
+		if r.NestedQuery != nil {
+			es = append(es, utils.PrefixYamlPath("plural_datasource")(fmt.Errorf("`plural_datasource` doesn't support `nested_query`, used by %s", r.Name)))
+		}
 	}
 
 	return es
 }
 
+// validateFrameworkResource returns errors for the features of the resource
+// that plugin framework resources don't support.
+func (r *Resource) validateFrameworkResource() (es []error) {
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("plugin framework resources don't support %s, used by %s", feature, r.Name))
+	}
+	if r.NestedQuery != nil {
+		unsupported("`nested_query`")
+	}
+	if r.StateUpgraders || r.MigrateState != "" {
+		unsupported("state migration")
+	}
+	if r.IdentityUpgraders {
+		unsupported("`identity_upgraders`")
+	}
+	var labelsDiff, annotationsDiff, customDiff bool
+	for _, diff := range r.CustomDiff {
+		switch diff {
+		case "tpgresource.SetLabelsDiff", "tpgresource.SetLabelsDiffWithoutAttributionLabel", "tpgresource.SetMetadataLabelsDiff":
+			labelsDiff = true
+		case "tpgresource.SetAnnotationsDiff", "tpgresource.SetMetadataAnnotationsDiff":
+			annotationsDiff = true
+		default:
+			customDiff = true
+		}
+	}
+	if labelsDiff {
+		unsupported("`KeyValueLabels` fields, as their `terraform_labels` and `effective_labels` are set by a custom diff")
+	}
+	if annotationsDiff {
+		unsupported("`KeyValueAnnotations` fields, as their `effective_annotations` are set by a custom diff")
+	}
+	if customDiff {
+		unsupported("`custom_diff`")
+	}
+	if len(r.UnorderedListProperties()) > 0 {
+		unsupported("`unordered_list` fields, as their order is ignored by a custom diff")
+	}
+	if r.GenerateListResource {
+		unsupported("`generate_list_resource`")
+	}
+	if r.Datasource != nil && r.Datasource.Generate {
+		unsupported("`datasource_experimental`")
+	}
+	if r.ShouldGeneratePluralDataSource() {
+		unsupported("`plural_datasource`")
+	}
+	if len(r.WriteOnlyProps()) > 0 {
+		unsupported("write-only fields")
+	}
+	return es
+}
+
+// validateEphemeral returns an error for each feature used by r that only
+// applies to managed resources, as ephemeral resources generate nothing else.
+func (r *Resource) validateEphemeral() (es []error) {
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("ephemeral resources don't support %s, used by %s", feature, r.Name))
+	}
+	if r.FrameworkResource {
+		unsupported("`plugin_framework_experimental`")
+	}
+	if r.Datasource != nil && r.Datasource.Generate {
+		unsupported("`datasource_experimental`")
+	}
+	if r.ShouldGeneratePluralDataSource() {
+		unsupported("`plural_datasource`")
+	}
+	if r.GenerateListResource {
+		unsupported("`generate_list_resource`")
+	}
+	if r.IamPolicy != nil && !r.IamPolicy.Exclude {
+		unsupported("`iam_policy`")
+	}
+	if len(r.CustomMethods) > 0 {
+		unsupported("`custom_methods`")
+	}
+	if r.NestedQuery != nil {
+		unsupported("`nested_query`")
+	}
+	if len(r.WriteOnlyProps()) > 0 {
+		unsupported("write-only fields")
+	}
+	return es
+}
+
 // ====================
 // Custom Getters and Setters
 // ====================
@@ -820,6 +967,21 @@ func (r Resource) WriteOnlyPropsToString() string {
 	return strings.Join(props, ", ")
 }
 
+// RequestLogRedactedFields returns the dotted API paths of the sensitive and
+// write-only fields of the resource, whose values are redacted from the
+// request log. Fields in maps are under a "*" key.
+func (r Resource) RequestLogRedactedFields() []string {
+	var paths []string
+	for _, prop := range google.Concat(r.SensitiveProps(), r.WriteOnlyProps()) {
+		path := strings.Join(prop.RequestLogPath(), ".")
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 // All settable properties in the resource.
 // Fingerprints aren't *really" settable properties, but they behave like one.
 // At Create, they have no value but they can just be read in anyways, and after a Read
@@ -1235,6 +1397,10 @@ func (r *Resource) ExcludeIfNotInVersion(version *product.Version) {
 			p.ExcludeIfNotInVersion(version)
 		}
 	}
+
+	for _, m := range r.CustomMethods {
+		m.ExcludeIfNotInVersion(version)
+	}
 }
 
 // ====================
@@ -1418,6 +1584,20 @@ func (r Resource) TerraformName() string {
 	return fmt.Sprintf("google_%s_%s", r.ProductMetadata.TerraformName(), google.Underscore(r.Name))
 }
 
+// IsEphemeral returns true if the resource generates an ephemeral resource.
+func (r Resource) IsEphemeral() bool {
+	return r.Ephemeral != nil
+}
+
+// ResourceDataType returns the type of the resource data the generated CRUD
+// functions, flatteners and custom code of the resource are passed.
+func (r Resource) ResourceDataType() string {
+	if r.FrameworkResource || r.IsEphemeral() {
+		return "*fwresource.ResourceData"
+	}
+	return "*schema.ResourceData"
+}
+
 func (r Resource) AutogenVersion() int {
 	if r.AutogenStatus == "" {
 		return 0
@@ -1524,6 +1704,112 @@ func ImportIdFormats(importFormat, identity []string, baseUrl string) []string {
 	return uniq
 }
 
+// ShouldGenerateIdFunctions returns whether provider functions parsing and
+// building the ids of the resource are generated.
+func (r Resource) ShouldGenerateIdFunctions() bool {
+	if r.IsEphemeral() {
+		return false
+	}
+	return r.GenerateIdFunctions || r.ProductMetadata.GenerateIdFunctions
+}
+
+// RoundTripProperties returns the top-level fields tested by the generated
+// round-trip test of the resource, which flattens API values of the fields
+// and expands them back.
+func (r Resource) RoundTripProperties() []*Type {
+	return google.Select(r.AllUserProperties(), func(p *Type) bool {
+		return p.RoundTripJson() != ""
+	})
+}
+
+// ShouldGenerateRoundTripTests returns whether the round-trip test of the
+// resource is generated. Framework resources have no SDK schema to set
+// flattened values on.
+func (r Resource) ShouldGenerateRoundTripTests() bool {
+	if r.IsExcluded() || r.IsEphemeral() || r.FrameworkResource || r.ExcludeRoundTripTests {
+		return false
+	}
+	return len(r.RoundTripProperties()) > 0
+}
+
+// ParseIdFunctionName returns the name of the provider function parsing the
+// ids of the resource, such as parse_compute_instance_id.
+func (r Resource) ParseIdFunctionName() string {
+	return fmt.Sprintf("parse_%s_id", strings.TrimPrefix(r.TerraformName(), "google_"))
+}
+
+// BuildIdFunctionName returns the name of the provider function building the
+// id of the resource, such as build_compute_instance_id.
+func (r Resource) BuildIdFunctionName() string {
+	return fmt.Sprintf("build_%s_id", strings.TrimPrefix(r.TerraformName(), "google_"))
+}
+
+// IdFunctionRegexes returns the regexes matching the import id formats of the
+// resource, which the parse function tries in order. The first, longest
+// format also matches the end of self links and full resource names.
+func (r Resource) IdFunctionRegexes() []string {
+	var regexes []string
+	for i, format := range r.ImportIdFormatsFromResource() {
+		if i == 0 {
+			regexes = append(regexes, fmt.Sprintf("(?:^|/)%s$", google.Format2Regex(format)))
+		} else {
+			regexes = append(regexes, fmt.Sprintf("^%s$", google.Format2Regex(format)))
+		}
+	}
+	return regexes
+}
+
+// IdFunctionParseFields returns the fields of the import id formats of the
+// resource, which are the attributes of the object returned by the parse
+// function.
+func (r Resource) IdFunctionParseFields() []string {
+	var fields []string
+	for _, format := range r.ImportIdFormatsFromResource() {
+		for _, field := range r.ExtractIdentifiers(format) {
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// IdFunctionBuildFields returns the fields of the id format of the resource,
+// which are the parameters of the build function.
+func (r Resource) IdFunctionBuildFields() []string {
+	var fields []string
+	for _, field := range r.ExtractIdentifiers(r.GetIdFormat()) {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// IdFunctionExampleValue returns the value of an id field used in the
+// documentation and tests of the id functions.
+func (r Resource) IdFunctionExampleValue(field string) string {
+	return "my-" + strings.ReplaceAll(field, "_", "-")
+}
+
+// IdFunctionExampleId returns format with its fields replaced by their
+// example values.
+func (r Resource) IdFunctionExampleId(format string) string {
+	return regexp.MustCompile(`\{\{%?(\w+)\}\}`).ReplaceAllStringFunc(format, func(match string) string {
+		return r.IdFunctionExampleValue(r.ExtractIdentifiers(match)[0])
+	})
+}
+
+// IdFunctionExampleFields returns the fields of format mapped to their
+// example values.
+func (r Resource) IdFunctionExampleFields(format string) map[string]string {
+	fields := make(map[string]string)
+	for _, field := range r.ExtractIdentifiers(format) {
+		fields[field] = r.IdFunctionExampleValue(field)
+	}
+	return fields
+}
+
 // IgnoreReadProperties returns a sorted slice of property names (snake_case) that should be ignored when reading.
 // This is useful for downstream code that needs to iterate over these properties.
 func (r Resource) IgnoreReadProperties(s *resource.Step) []string {
@@ -2500,27 +2786,79 @@ func (r Resource) ShouldDatasourceSetAnnotations() bool {
 // DatasourceOptionalFields returns a list of fields from the resource's URI
 // that should be marked as "Required".
 func (r Resource) DatasourceRequiredFields() []string {
-	requiredFields := []string{}
-
-	for _, field := range r.ExtractIdentifiers(r.IdFormat) {
-		if field != "region" && field != "project" && field != "zone" {
-			requiredFields = append(requiredFields, field)
-		}
-	}
-	return requiredFields
+	return r.datasourceFields(r.IdFormat, false)
 }
 
 // DatasourceOptionalFields returns a list of fields from the resource's URI
 // that should be marked as "Optional".
 func (r Resource) DatasourceOptionalFields() []string {
-	optionalFields := []string{}
+	return r.datasourceFields(r.IdFormat, true)
+}
+
+// datasourceFields returns the fields of url that are optional data source
+// arguments, as they default to the provider's region, project or zone, or the
+// ones that are required if optional is false.
+func (r Resource) datasourceFields(url string, optional bool) []string {
+	fields := []string{}
 
-	for _, field := range r.ExtractIdentifiers(r.IdFormat) {
-		if field == "region" || field == "project" || field == "zone" {
-			optionalFields = append(optionalFields, field)
+	for _, field := range r.ExtractIdentifiers(url) {
+		if (field == "region" || field == "project" || field == "zone") == optional {
+			fields = append(fields, field)
 		}
 	}
-	return optionalFields
+	return fields
+}
+
+// UserCustomMethods returns the custom methods of the resource that are
+// generated as actions in the version being generated.
+func (r Resource) UserCustomMethods() []*CustomMethod {
+	return google.Reject(r.CustomMethods, func(m *CustomMethod) bool { return m.Exclude })
+}
+
+func (r *Resource) ShouldGeneratePluralDataSource() bool {
+	if r.PluralDatasource == nil {
+		return false
+	}
+	return r.PluralDatasource.Generate
+}
+
+func (r *Resource) ShouldGeneratePluralDataSourceTests() bool {
+	if r.PluralDatasource == nil {
+		return false
+	}
+	return !r.PluralDatasource.ExcludeTest
+}
+
+// PluralName returns the plural of the resource name, used by its plural
+// data source.
+func (r Resource) PluralName() string {
+	if r.PluralDatasource != nil && r.PluralDatasource.Name != "" {
+		return r.PluralDatasource.Name
+	}
+	return google.Plural(r.Name)
+}
+
+// PluralTerraformName returns the name of the plural data source of the
+// resource, such as google_certificate_manager_certificates.
+func (r Resource) PluralTerraformName() string {
+	name := r.TerraformName()
+	suffix := google.Underscore(r.Name)
+	if !strings.HasSuffix(name, suffix) {
+		return google.Plural(name)
+	}
+	return strings.TrimSuffix(name, suffix) + google.Underscore(r.PluralName())
+}
+
+// PluralDatasourceRequiredFields returns a list of fields from the resource's
+// collection URI that should be marked as "Required" on its plural data source.
+func (r Resource) PluralDatasourceRequiredFields() []string {
+	return r.datasourceFields(r.CollectionUrl(), false)
+}
+
+// PluralDatasourceOptionalFields returns a list of fields from the resource's
+// collection URI that should be marked as "Optional" on its plural data source.
+func (r Resource) PluralDatasourceOptionalFields() []string {
+	return r.datasourceFields(r.CollectionUrl(), true)
 }
 
 func (r Resource) ShouldGenerateSweepers() bool {
@@ -2629,6 +2967,24 @@ func (r Resource) TGCTestIgnorePropertiesToStrings() []string {
 	return slices.Compact(props)
 }
 
+// Lists the sample steps whose configs test.RoundTrip converts. Their
+// documentation configs are used, as they have literal values in place of the
+// test variables.
+func (r Resource) TGCRoundTripSteps() []*resource.Step {
+	var steps []*resource.Step
+	seen := make(map[string]bool)
+	for _, s := range r.TestSamples() {
+		for _, step := range s.Steps {
+			if step.DocumentationHCLText == "" || seen[step.Name] {
+				continue
+			}
+			seen[step.Name] = true
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
 // Filters out computed properties during cai2hcl
 func (r Resource) ReadPropertiesForTgc() []*Type {
 	return google.Reject(r.AllUserProperties(), func(v *Type) bool {
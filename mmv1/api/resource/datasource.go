@@ -19,3 +19,23 @@ type Datasource struct {
 	// boolean to determine whether tests should be generated for a datasource
 	ExcludeTest bool `yaml:"exclude_test"`
 }
+
+// PluralDatasource configures a data source that lists every instance of a
+// resource in a scope, such as a project and location, using the collection
+// URL of the resource.
+type PluralDatasource struct {
+	// boolean to determine whether the plural datasource file should be generated
+	Generate bool `yaml:"generate"`
+	// boolean to determine whether tests should be generated for the plural datasource
+	ExcludeTest bool `yaml:"exclude_test,omitempty"`
+	// The plural of the resource name, such as `Policies`. Defaults to the
+	// plural of the resource name. It names the data source and the list
+	// attribute holding the instances.
+	Name string `yaml:"name,omitempty"`
+	// boolean to determine whether a `filter` argument is passed to the list
+	// call, for APIs that support filtering
+	Filter bool `yaml:"filter,omitempty"`
+	// boolean to determine whether an `order_by` argument is passed to the
+	// list call, for APIs that support ordering
+	OrderBy bool `yaml:"order_by,omitempty"`
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
+)
+
+var EphemeralVerbs = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// Ephemeral describes an ephemeral resource, whose values are fetched or
+// created when Terraform opens it and never stored in state. Resources that
+// set it generate only the ephemeral resource.
+//
+// The parameters and properties of the resource are its request fields, sent
+// in the URL and the body of the open call, and its output properties are the
+// result fields, which should be `sensitive` when they hold secrets.
+type Ephemeral struct {
+	// The call that creates or fetches the values of the resource. Its URL
+	// defaults to the create URL of the resource and its verb to the create
+	// verb.
+	Open EphemeralCall `yaml:"open,omitempty"`
+
+	// An optional call that extends the lifetime of the values, made every
+	// `interval` for as long as Terraform uses them.
+	Renew *EphemeralCall `yaml:"renew,omitempty"`
+
+	// An optional call that releases the values once Terraform no longer uses
+	// them, such as deleting a key. Its URL can refer to result fields.
+	Close *EphemeralCall `yaml:"close,omitempty"`
+
+	// If true, tests aren't generated for the ephemeral resource.
+	ExcludeTest bool `yaml:"exclude_test,omitempty"`
+}
+
+// EphemeralCall is a request made by an ephemeral resource.
+type EphemeralCall struct {
+	// The URL of the request, relative to the base URL of the product. Field
+	// names enclosed in double curly braces are replaced with their values.
+	Url string `yaml:"url,omitempty"`
+
+	// The HTTP verb of the request. Requests other than the open call have no
+	// body. Defaults to POST for open and renew calls and DELETE for close
+	// calls.
+	Verb string `yaml:"verb,omitempty"`
+
+	// How often renew calls are made, as a Go duration such as "45m". Only
+	// used by the renew call.
+	Interval string `yaml:"interval,omitempty"`
+}
+
+func (e *Ephemeral) SetDefault(createUri, createVerb string) {
+	if e.Open.Url == "" {
+		e.Open.Url = createUri
+	}
+	if e.Open.Verb == "" {
+		e.Open.Verb = createVerb
+	}
+	if e.Renew != nil && e.Renew.Verb == "" {
+		e.Renew.Verb = "POST"
+	}
+	if e.Close != nil && e.Close.Verb == "" {
+		e.Close.Verb = "DELETE"
+	}
+}
+
+func (e *Ephemeral) Validate(rName string) (es []error) {
+	es = append(es, utils.TransformErrs(utils.PrefixYamlPath("open"), e.Open.validate(rName))...)
+	if e.Renew != nil {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("renew"), e.Renew.validate(rName))...)
+		if _, err := time.ParseDuration(e.Renew.Interval); err != nil {
+			es = append(es, utils.PrefixYamlPath("renew", "interval")(fmt.Errorf("invalid `interval` for `ephemeral.renew` in resource %s: %w", rName, err)))
+		}
+	}
+	if e.Close != nil {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("close"), e.Close.validate(rName))...)
+	}
+	return es
+}
+
+func (c *EphemeralCall) validate(rName string) (es []error) {
+	if c.Url == "" {
+		es = append(es, utils.PrefixYamlPath("url")(fmt.Errorf("missing `url` for ephemeral call in resource %s", rName)))
+	}
+	if c.Verb != "" && !slices.Contains(EphemeralVerbs, c.Verb) {
+		es = append(es, utils.PrefixYamlPath("verb")(fmt.Errorf("value on `verb` should be one of %#v", EphemeralVerbs)))
+	}
+	return es
+}
+
+// RenewInterval returns the interval of renew calls as a Go expression.
+func (c EphemeralCall) RenewInterval() string {
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return "0"
+	}
+	return fmt.Sprintf("%d * time.Second", int64(d.Seconds()))
+}
@@ -0,0 +1,80 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+func TestEphemeral_SetDefault(t *testing.T) {
+	e := resource.Ephemeral{
+		Renew: &resource.EphemeralCall{Url: "keys/{{key_id}}:renew", Interval: "45m"},
+		Close: &resource.EphemeralCall{Url: "keys/{{key_id}}"},
+	}
+	e.SetDefault("projects/{{project}}/keys", "POST")
+
+	want := resource.Ephemeral{
+		Open:  resource.EphemeralCall{Url: "projects/{{project}}/keys", Verb: "POST"},
+		Renew: &resource.EphemeralCall{Url: "keys/{{key_id}}:renew", Verb: "POST", Interval: "45m"},
+		Close: &resource.EphemeralCall{Url: "keys/{{key_id}}", Verb: "DELETE"},
+	}
+	if diff := cmp.Diff(want, e); diff != "" {
+		t.Errorf("SetDefault() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEphemeral_Validate(t *testing.T) {
+	cases := []struct {
+		name      string
+		ephemeral resource.Ephemeral
+		wantErrs  int
+	}{
+		{
+			name: "open only",
+			ephemeral: resource.Ephemeral{
+				Open: resource.EphemeralCall{Url: "projects/{{project}}/keys", Verb: "POST"},
+			},
+		},
+		{
+			name: "renew and close",
+			ephemeral: resource.Ephemeral{
+				Open:  resource.EphemeralCall{Url: "projects/{{project}}/keys", Verb: "POST"},
+				Renew: &resource.EphemeralCall{Url: "keys/{{key_id}}:renew", Verb: "POST", Interval: "1h30m"},
+				Close: &resource.EphemeralCall{Url: "keys/{{key_id}}", Verb: "DELETE"},
+			},
+		},
+		{
+			name: "missing url and bad verb",
+			ephemeral: resource.Ephemeral{
+				Open: resource.EphemeralCall{Verb: "FETCH"},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "invalid renew interval",
+			ephemeral: resource.Ephemeral{
+				Open:  resource.EphemeralCall{Url: "projects/{{project}}/keys", Verb: "POST"},
+				Renew: &resource.EphemeralCall{Url: "keys/{{key_id}}:renew", Verb: "POST"},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.ephemeral.Validate("Key")
+			if len(errs) != tc.wantErrs {
+				t.Errorf("Validate() returned %d errors, want %d: %v", len(errs), tc.wantErrs, errs)
+			}
+		})
+	}
+}
+
+func TestEphemeralCall_RenewInterval(t *testing.T) {
+	c := resource.EphemeralCall{Interval: "45m"}
+	if got, want := c.RenewInterval(), "2700 * time.Second"; got != want {
+		t.Errorf("RenewInterval() = %q, want %q", got, want)
+	}
+}
@@ -187,20 +187,24 @@ func (p *IamPolicy) MarshalYAML() (interface{}, error) {
 	return (*iamPolicyAlias)(clone.(*IamPolicy)), nil
 }
 
+// The values allowed for an IamPolicy's verbs and condition request type.
+var (
+	FetchIamPolicyVerbs       = []string{"GET", "POST"}
+	SetIamPolicyVerbs         = []string{"POST", "PUT"}
+	IamConditionsRequestTypes = []string{"REQUEST_BODY", "QUERY_PARAM", "QUERY_PARAM_NESTED"}
+)
+
 func (p *IamPolicy) Validate(rName string) (es []error) {
-	allowed := []string{"GET", "POST"}
-	if !slices.Contains(allowed, p.FetchIamPolicyVerb) {
-		es = append(es, fmt.Errorf("value on `fetch_iam_policy_verb` should be one of %#v in resource %s", allowed, rName))
+	if !slices.Contains(FetchIamPolicyVerbs, p.FetchIamPolicyVerb) {
+		es = append(es, utils.PrefixYamlPath("fetch_iam_policy_verb")(fmt.Errorf("value on `fetch_iam_policy_verb` should be one of %#v in resource %s", FetchIamPolicyVerbs, rName)))
 	}
 
-	allowed = []string{"POST", "PUT"}
-	if !slices.Contains(allowed, p.SetIamPolicyVerb) {
-		es = append(es, fmt.Errorf("value on `set_iam_policy_verb` should be one of %#v in resource %s", allowed, rName))
+	if !slices.Contains(SetIamPolicyVerbs, p.SetIamPolicyVerb) {
+		es = append(es, utils.PrefixYamlPath("set_iam_policy_verb")(fmt.Errorf("value on `set_iam_policy_verb` should be one of %#v in resource %s", SetIamPolicyVerbs, rName)))
 	}
 
-	allowed = []string{"REQUEST_BODY", "QUERY_PARAM", "QUERY_PARAM_NESTED"}
-	if p.IamConditionsRequestType != "" && !slices.Contains(allowed, p.IamConditionsRequestType) {
-		es = append(es, fmt.Errorf("value on `iam_conditions_request_type` should be one of %#v in resource %s", allowed, rName))
+	if p.IamConditionsRequestType != "" && !slices.Contains(IamConditionsRequestTypes, p.IamConditionsRequestType) {
+		es = append(es, utils.PrefixYamlPath("iam_conditions_request_type")(fmt.Errorf("value on `iam_conditions_request_type` should be one of %#v in resource %s", IamConditionsRequestTypes, rName)))
 	}
 
 	return es
@@ -15,6 +15,8 @@ package resource
 
 import (
 	"fmt"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
 )
 
 // Metadata for resources that are nested within a parent resource, as
@@ -47,7 +49,7 @@ type NestedQuery struct {
 
 func (q *NestedQuery) Validate(rName string) (es []error) {
 	if len(q.Keys) == 0 {
-		es = append(es, fmt.Errorf("missing `keys` for `nested_query` in resource %s", rName))
+		es = append(es, utils.PrefixYamlPath("keys")(fmt.Errorf("missing `keys` for `nested_query` in resource %s", rName)))
 	}
 
 	return es
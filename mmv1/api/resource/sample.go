@@ -19,6 +19,7 @@ import (
 	"slices"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 )
 
@@ -143,10 +144,10 @@ func (s *Sample) Validate(rName string) (es []error) {
 	if s.Name == "" {
 		es = append(es, fmt.Errorf("missing `name` for one sample in resource %s", rName))
 	}
-	es = append(es, s.ValidateExternalProviders()...)
+	es = append(es, utils.TransformErrs(utils.PrefixYamlPath("external_providers"), s.ValidateExternalProviders())...)
 
 	for _, step := range s.Steps {
-		es = append(es, step.Validate(rName, s.Name)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("steps", step.Name), step.Validate(rName, s.Name))...)
 	}
 
 	return es
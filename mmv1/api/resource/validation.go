@@ -13,9 +13,302 @@
 
 package resource
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
+)
+
 // Support for schema ValidateFunc functionality.
+//
+// All of the keys that are set are checked, so for example `min_length` can be
+// combined with `regex`.
 type Validation struct {
 	// Ensures the value matches this regex
-	Regex    string `yaml:"regex,omitempty"`
+	Regex string `yaml:"regex,omitempty"`
+
+	// The name of a handwritten SDK ValidateFunc, usually defined in
+	// `custom_code.constants`. It isn't used by plugin framework resources.
 	Function string `yaml:"function,omitempty"`
+
+	// Ensures an Integer or Double value is at least this.
+	Min *float64 `yaml:"min,omitempty"`
+
+	// Ensures an Integer or Double value is at most this.
+	Max *float64 `yaml:"max,omitempty"`
+
+	// Ensures a String value is at least this many characters long.
+	MinLength *int `yaml:"min_length,omitempty"`
+
+	// Ensures a String value is at most this many characters long.
+	MaxLength *int `yaml:"max_length,omitempty"`
+
+	// Ensures a String value is an IP CIDR range, such as `10.0.0.0/8`.
+	Cidr bool `yaml:"cidr,omitempty"`
+
+	// Ensures a String value is an IPv4 or IPv6 address.
+	IpAddress bool `yaml:"ip_address,omitempty"`
+
+	// Ensures a String value is an RFC1035 name: a lowercase letter followed
+	// by lowercase letters, digits or hyphens, not ending with a hyphen. Its
+	// length is between 1 and 63 characters unless `min_length` or
+	// `max_length` say otherwise.
+	Rfc1035Name bool `yaml:"rfc1035_name,omitempty"`
+
+	// Ensures a String value is one of these. Unlike an Enum property, the
+	// values are sent to the API unchanged.
+	OneOf []string `yaml:"one_of,omitempty"`
+}
+
+const (
+	rfc1035MinLength = 1
+	rfc1035MaxLength = 63
+	rfc1035Regex     = `^[a-z]([-a-z0-9]*[a-z0-9])?$`
+)
+
+// Validate checks that the keys that are set make sense for a value of
+// valueType, the `type` of the property (or item type) being validated.
+func (v Validation) Validate(valueType, fieldPath, rName string) (es []error) {
+	numeric := valueType == "Integer" || valueType == "Double"
+	for _, bound := range []struct {
+		key   string
+		value *float64
+	}{{"min", v.Min}, {"max", v.Max}} {
+		if bound.value == nil {
+			continue
+		}
+		if !numeric {
+			es = append(es, utils.PrefixYamlPath(bound.key)(fmt.Errorf("property %s validation `%s` is only supported for Integer and Double properties in resource %s", fieldPath, bound.key, rName)))
+		} else if valueType == "Integer" && *bound.value != math.Trunc(*bound.value) {
+			es = append(es, utils.PrefixYamlPath(bound.key)(fmt.Errorf("property %s validation `%s` must be a whole number for an Integer property in resource %s", fieldPath, bound.key, rName)))
+		}
+	}
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		es = append(es, utils.PrefixYamlPath("max")(fmt.Errorf("property %s validation `max` must not be less than `min` in resource %s", fieldPath, rName)))
+	}
+
+	stringKeys := []struct {
+		key string
+		set bool
+	}{
+		{"min_length", v.MinLength != nil},
+		{"max_length", v.MaxLength != nil},
+		{"cidr", v.Cidr},
+		{"ip_address", v.IpAddress},
+		{"rfc1035_name", v.Rfc1035Name},
+		{"one_of", len(v.OneOf) > 0},
+	}
+	for _, k := range stringKeys {
+		if k.set && valueType != "String" {
+			es = append(es, utils.PrefixYamlPath(k.key)(fmt.Errorf("property %s validation `%s` is only supported for String properties in resource %s", fieldPath, k.key, rName)))
+		}
+	}
+
+	if v.MinLength != nil && *v.MinLength < 0 {
+		es = append(es, utils.PrefixYamlPath("min_length")(fmt.Errorf("property %s validation `min_length` must not be negative in resource %s", fieldPath, rName)))
+	}
+	if v.MaxLength != nil && *v.MaxLength < 0 {
+		es = append(es, utils.PrefixYamlPath("max_length")(fmt.Errorf("property %s validation `max_length` must not be negative in resource %s", fieldPath, rName)))
+	}
+	if minLength, maxLength := v.lengthBounds(); maxLength >= 0 && minLength > maxLength {
+		es = append(es, utils.PrefixYamlPath("max_length")(fmt.Errorf("property %s validation `max_length` must not be less than `min_length` in resource %s", fieldPath, rName)))
+	}
+	if v.Rfc1035Name && v.MinLength != nil && *v.MinLength < rfc1035MinLength {
+		es = append(es, utils.PrefixYamlPath("min_length")(fmt.Errorf("property %s validation `min_length` must be at least %d for an RFC1035 name in resource %s", fieldPath, rfc1035MinLength, rName)))
+	}
+
+	formats := 0
+	for _, set := range []bool{v.Cidr, v.IpAddress, v.Rfc1035Name} {
+		if set {
+			formats++
+		}
+	}
+	if formats > 1 {
+		es = append(es, fmt.Errorf("property %s validation can only set one of `cidr`, `ip_address` and `rfc1035_name` in resource %s", fieldPath, rName))
+	}
+
+	return es
+}
+
+// lengthBounds returns the allowed length of a string value, with a maximum
+// of -1 when there is no limit.
+func (v Validation) lengthBounds() (int, int) {
+	minLength, maxLength := 0, -1
+	if v.Rfc1035Name {
+		minLength, maxLength = rfc1035MinLength, rfc1035MaxLength
+	}
+	if v.MinLength != nil {
+		minLength = *v.MinLength
+	}
+	if v.MaxLength != nil {
+		maxLength = *v.MaxLength
+	}
+	return minLength, maxLength
+}
+
+// ValidateFunc returns the SDK ValidateFunc for a value of valueType, or ""
+// if no validation is set. Multiple checks are combined with validation.All.
+func (v Validation) ValidateFunc(valueType string) string {
+	var funcs []string
+	if v.Regex != "" {
+		funcs = append(funcs, fmt.Sprintf("verify.ValidateRegexp(`%s`)", v.Regex))
+	}
+	if v.Function != "" {
+		funcs = append(funcs, v.Function)
+	}
+
+	if valueType == "Integer" || valueType == "Double" {
+		kind := "Int"
+		if valueType == "Double" {
+			kind = "Float"
+		}
+		switch {
+		case v.Min != nil && v.Max != nil:
+			funcs = append(funcs, fmt.Sprintf("validation.%sBetween(%s, %s)", kind, formatNumber(*v.Min), formatNumber(*v.Max)))
+		case v.Min != nil:
+			funcs = append(funcs, fmt.Sprintf("validation.%sAtLeast(%s)", kind, formatNumber(*v.Min)))
+		case v.Max != nil:
+			funcs = append(funcs, fmt.Sprintf("validation.%sAtMost(%s)", kind, formatNumber(*v.Max)))
+		}
+	}
+
+	minLength, maxLength := v.lengthBounds()
+	switch {
+	case v.Rfc1035Name:
+		funcs = append(funcs, fmt.Sprintf("verify.ValidateRFC1035Name(%d, %d)", minLength, maxLength))
+	case v.MaxLength != nil:
+		funcs = append(funcs, fmt.Sprintf("validation.StringLenBetween(%d, %d)", minLength, maxLength))
+	case v.MinLength != nil:
+		funcs = append(funcs, fmt.Sprintf("validation.StringLenBetween(%d, %d)", minLength, math.MaxInt32))
+	}
+
+	if v.Cidr {
+		funcs = append(funcs, "verify.ValidateIpCidrRange")
+	}
+	if v.IpAddress {
+		funcs = append(funcs, "verify.ValidateIpAddress")
+	}
+	if len(v.OneOf) > 0 {
+		funcs = append(funcs, fmt.Sprintf("validation.StringInSlice([]string{%s}, false)", quoteAll(v.OneOf)))
+	}
+
+	switch len(funcs) {
+	case 0:
+		return ""
+	case 1:
+		return funcs[0]
+	default:
+		return fmt.Sprintf("validation.All(%s)", strings.Join(funcs, ", "))
+	}
+}
+
+// FWValidators returns the plugin framework validators for a value of
+// valueType. `function` has no framework equivalent and is skipped.
+func (v Validation) FWValidators(valueType string) []string {
+	var validators []string
+	switch valueType {
+	case "Integer", "Double":
+		pkg := "int64validator"
+		if valueType == "Double" {
+			pkg = "float64validator"
+		}
+		switch {
+		case v.Min != nil && v.Max != nil:
+			validators = append(validators, fmt.Sprintf("%s.Between(%s, %s)", pkg, formatNumber(*v.Min), formatNumber(*v.Max)))
+		case v.Min != nil:
+			validators = append(validators, fmt.Sprintf("%s.AtLeast(%s)", pkg, formatNumber(*v.Min)))
+		case v.Max != nil:
+			validators = append(validators, fmt.Sprintf("%s.AtMost(%s)", pkg, formatNumber(*v.Max)))
+		}
+	case "String":
+		if v.Regex != "" {
+			validators = append(validators, fmt.Sprintf("stringvalidator.RegexMatches(regexp.MustCompile(`%s`), \"\")", v.Regex))
+		}
+
+		minLength, maxLength := v.lengthBounds()
+		switch {
+		case maxLength >= 0:
+			validators = append(validators, fmt.Sprintf("stringvalidator.LengthBetween(%d, %d)", minLength, maxLength))
+		case v.MinLength != nil:
+			validators = append(validators, fmt.Sprintf("stringvalidator.LengthAtLeast(%d)", minLength))
+		}
+
+		if v.Rfc1035Name {
+			validators = append(validators, fmt.Sprintf("stringvalidator.RegexMatches(regexp.MustCompile(`%s`), \"must be an RFC1035 name\")", rfc1035Regex))
+		}
+		if v.Cidr {
+			validators = append(validators, "fwvalidators.IpCidrRangeValidator()")
+		}
+		if v.IpAddress {
+			validators = append(validators, "fwvalidators.IpAddressValidator()")
+		}
+		if len(v.OneOf) > 0 {
+			validators = append(validators, fmt.Sprintf("stringvalidator.OneOf(%s)", quoteAll(v.OneOf)))
+		}
+	}
+	return validators
+}
+
+// Documentation describes the allowed values in sentences for the generated
+// docs, or returns "" if there is nothing to describe. Regexes and functions
+// aren't described, as they are better explained by the field description.
+// With item set, the sentences describe each value of an Array.
+func (v Validation) Documentation(valueType string, item bool) string {
+	must, possible := "Must", "Possible values are:"
+	if item {
+		must, possible = "Each value must", "Each value may be one of:"
+	}
+
+	var sentences []string
+	if valueType == "Integer" || valueType == "Double" {
+		switch {
+		case v.Min != nil && v.Max != nil:
+			sentences = append(sentences, fmt.Sprintf("%s be between %s and %s.", must, formatNumber(*v.Min), formatNumber(*v.Max)))
+		case v.Min != nil:
+			sentences = append(sentences, fmt.Sprintf("%s be at least %s.", must, formatNumber(*v.Min)))
+		case v.Max != nil:
+			sentences = append(sentences, fmt.Sprintf("%s be at most %s.", must, formatNumber(*v.Max)))
+		}
+	}
+
+	minLength, maxLength := v.lengthBounds()
+	switch {
+	case maxLength >= 0:
+		sentences = append(sentences, fmt.Sprintf("%s be between %d and %d characters long.", must, minLength, maxLength))
+	case v.MinLength != nil:
+		sentences = append(sentences, fmt.Sprintf("%s be at least %d characters long.", must, minLength))
+	}
+
+	if v.Rfc1035Name {
+		sentences = append(sentences, fmt.Sprintf("%s start with a lowercase letter followed by lowercase letters, digits or hyphens, and must not end with a hyphen.", must))
+	}
+	if v.Cidr {
+		sentences = append(sentences, fmt.Sprintf("%s be an IP CIDR range, such as `10.0.0.0/8`.", must))
+	}
+	if v.IpAddress {
+		sentences = append(sentences, fmt.Sprintf("%s be an IPv4 or IPv6 address.", must))
+	}
+	if len(v.OneOf) > 0 {
+		values := make([]string, len(v.OneOf))
+		for i, value := range v.OneOf {
+			values[i] = "`" + value + "`"
+		}
+		sentences = append(sentences, fmt.Sprintf("%s %s.", possible, strings.Join(values, ", ")))
+	}
+	return strings.Join(sentences, "\n  ")
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
 }
@@ -0,0 +1,258 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestValidation_ValidateFunc(t *testing.T) {
+	cases := []struct {
+		name       string
+		validation resource.Validation
+		valueType  string
+		want       string
+	}{
+		{
+			name:      "empty",
+			valueType: "String",
+			want:      "",
+		},
+		{
+			name:       "regex",
+			validation: resource.Validation{Regex: `^[a-z]+$`},
+			valueType:  "String",
+			want:       "verify.ValidateRegexp(`^[a-z]+$`)",
+		},
+		{
+			name:       "function",
+			validation: resource.Validation{Function: "validateFoo"},
+			valueType:  "String",
+			want:       "validateFoo",
+		},
+		{
+			name:       "integer range",
+			validation: resource.Validation{Min: ptr(1.0), Max: ptr(10.0)},
+			valueType:  "Integer",
+			want:       "validation.IntBetween(1, 10)",
+		},
+		{
+			name:       "double minimum",
+			validation: resource.Validation{Min: ptr(0.5)},
+			valueType:  "Double",
+			want:       "validation.FloatAtLeast(0.5)",
+		},
+		{
+			name:       "integer maximum",
+			validation: resource.Validation{Max: ptr(-1.0)},
+			valueType:  "Integer",
+			want:       "validation.IntAtMost(-1)",
+		},
+		{
+			name:       "length range",
+			validation: resource.Validation{MinLength: ptr(1), MaxLength: ptr(64)},
+			valueType:  "String",
+			want:       "validation.StringLenBetween(1, 64)",
+		},
+		{
+			name:       "maximum length",
+			validation: resource.Validation{MaxLength: ptr(64)},
+			valueType:  "String",
+			want:       "validation.StringLenBetween(0, 64)",
+		},
+		{
+			name:       "minimum length",
+			validation: resource.Validation{MinLength: ptr(3)},
+			valueType:  "String",
+			want:       "validation.StringLenBetween(3, 2147483647)",
+		},
+		{
+			name:       "rfc1035 name",
+			validation: resource.Validation{Rfc1035Name: true},
+			valueType:  "String",
+			want:       "verify.ValidateRFC1035Name(1, 63)",
+		},
+		{
+			name:       "rfc1035 name with maximum length",
+			validation: resource.Validation{Rfc1035Name: true, MaxLength: ptr(40)},
+			valueType:  "String",
+			want:       "verify.ValidateRFC1035Name(1, 40)",
+		},
+		{
+			name:       "one of",
+			validation: resource.Validation{OneOf: []string{"BASIC", "PREMIUM"}},
+			valueType:  "String",
+			want:       `validation.StringInSlice([]string{"BASIC", "PREMIUM"}, false)`,
+		},
+		{
+			name:       "combined",
+			validation: resource.Validation{Regex: `^10\.`, Cidr: true},
+			valueType:  "String",
+			want:       "validation.All(verify.ValidateRegexp(`^10\\.`), verify.ValidateIpCidrRange)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.validation.ValidateFunc(tc.valueType); got != tc.want {
+				t.Errorf("ValidateFunc(%q) = %q, want %q", tc.valueType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidation_FWValidators(t *testing.T) {
+	cases := []struct {
+		name       string
+		validation resource.Validation
+		valueType  string
+		want       []string
+	}{
+		{
+			name:       "function only",
+			validation: resource.Validation{Function: "validateFoo"},
+			valueType:  "String",
+			want:       nil,
+		},
+		{
+			name:       "integer minimum",
+			validation: resource.Validation{Min: ptr(1.0)},
+			valueType:  "Integer",
+			want:       []string{"int64validator.AtLeast(1)"},
+		},
+		{
+			name:       "double range",
+			validation: resource.Validation{Min: ptr(0.0), Max: ptr(1.0)},
+			valueType:  "Double",
+			want:       []string{"float64validator.Between(0, 1)"},
+		},
+		{
+			name:       "rfc1035 name",
+			validation: resource.Validation{Rfc1035Name: true},
+			valueType:  "String",
+			want: []string{
+				"stringvalidator.LengthBetween(1, 63)",
+				"stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`), \"must be an RFC1035 name\")",
+			},
+		},
+		{
+			name:       "string checks",
+			validation: resource.Validation{MinLength: ptr(2), IpAddress: true, OneOf: []string{"a"}},
+			valueType:  "String",
+			want: []string{
+				"stringvalidator.LengthAtLeast(2)",
+				"fwvalidators.IpAddressValidator()",
+				`stringvalidator.OneOf("a")`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.validation.FWValidators(tc.valueType)); diff != "" {
+				t.Errorf("FWValidators(%q) mismatch (-want +got):\n%s", tc.valueType, diff)
+			}
+		})
+	}
+}
+
+func TestValidation_Documentation(t *testing.T) {
+	v := resource.Validation{MinLength: ptr(1), MaxLength: ptr(8), OneOf: []string{"a", "b"}}
+	want := "Must be between 1 and 8 characters long.\n  Possible values are: `a`, `b`."
+	if got := v.Documentation("String", false); got != want {
+		t.Errorf("Documentation() = %q, want %q", got, want)
+	}
+
+	v = resource.Validation{Cidr: true}
+	want = "Each value must be an IP CIDR range, such as `10.0.0.0/8`."
+	if got := v.Documentation("String", true); got != want {
+		t.Errorf("Documentation() = %q, want %q", got, want)
+	}
+
+	v = resource.Validation{Regex: "^a$"}
+	if got := v.Documentation("String", false); got != "" {
+		t.Errorf("Documentation() = %q, want no documentation for a regex", got)
+	}
+}
+
+func TestValidation_Validate(t *testing.T) {
+	cases := []struct {
+		name       string
+		validation resource.Validation
+		valueType  string
+		wantErrs   int
+	}{
+		{
+			name:       "valid string",
+			validation: resource.Validation{MinLength: ptr(1), MaxLength: ptr(10), OneOf: []string{"a"}},
+			valueType:  "String",
+		},
+		{
+			name:       "valid integer",
+			validation: resource.Validation{Min: ptr(0.0), Max: ptr(10.0)},
+			valueType:  "Integer",
+		},
+		{
+			name:       "range on a string",
+			validation: resource.Validation{Min: ptr(1.0)},
+			valueType:  "String",
+			wantErrs:   1,
+		},
+		{
+			name:       "fractional bound on an integer",
+			validation: resource.Validation{Max: ptr(1.5)},
+			valueType:  "Integer",
+			wantErrs:   1,
+		},
+		{
+			name:       "inverted range",
+			validation: resource.Validation{Min: ptr(2.0), Max: ptr(1.0)},
+			valueType:  "Double",
+			wantErrs:   1,
+		},
+		{
+			name:       "length on an integer",
+			validation: resource.Validation{MaxLength: ptr(3)},
+			valueType:  "Integer",
+			wantErrs:   1,
+		},
+		{
+			name:       "inverted length",
+			validation: resource.Validation{MinLength: ptr(5), MaxLength: ptr(3)},
+			valueType:  "String",
+			wantErrs:   1,
+		},
+		{
+			name:       "rfc1035 name with zero minimum length",
+			validation: resource.Validation{Rfc1035Name: true, MinLength: ptr(0)},
+			valueType:  "String",
+			wantErrs:   1,
+		},
+		{
+			name:       "conflicting formats",
+			validation: resource.Validation{Cidr: true, IpAddress: true},
+			valueType:  "String",
+			wantErrs:   1,
+		},
+		{
+			name:       "one of on an enum",
+			validation: resource.Validation{OneOf: []string{"A"}},
+			valueType:  "Enum",
+			wantErrs:   1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.validation.Validate(tc.valueType, "field", "Resource"); len(got) != tc.wantErrs {
+				t.Errorf("Validate(%q) returned %d errors, want %d: %v", tc.valueType, len(got), tc.wantErrs, got)
+			}
+		})
+	}
+}
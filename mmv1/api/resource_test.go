@@ -1115,3 +1115,369 @@ func TestSamplePrimaryResourceId(t *testing.T) {
 		})
 	}
 }
+
+func TestResourcePluralDatasource(t *testing.T) {
+	t.Parallel()
+
+	version := &product.Version{Name: "ga", BaseUrl: "https://example.googleapis.com/v1/"}
+	p := &api.Product{Name: "CertificateManager", Versions: []*product.Version{version}, Version: version}
+
+	cases := []struct {
+		description  string
+		obj          api.Resource
+		wantName     string
+		wantRequired []string
+		wantOptional []string
+	}{
+		{
+			description:  "default plural",
+			obj:          api.Resource{Name: "Certificate", BaseUrl: "projects/{{project}}/locations/{{location}}/certificates", ProductMetadata: p},
+			wantName:     "google_certificate_manager_certificates",
+			wantRequired: []string{"location"},
+			wantOptional: []string{"project"},
+		},
+		{
+			description:  "irregular plural",
+			obj:          api.Resource{Name: "DnsAuthorization", BaseUrl: "projects/{{project}}/regions/{{region}}/dnsAuthorizations", ProductMetadata: p},
+			wantName:     "google_certificate_manager_dns_authorizations",
+			wantRequired: []string{},
+			wantOptional: []string{"project", "region"},
+		},
+		{
+			description: "overridden plural",
+			obj: api.Resource{
+				Name:             "CertificateIssuanceConfig",
+				BaseUrl:          "projects/{{project}}/locations/{{location}}/certificateIssuanceConfigs",
+				PluralDatasource: &resource.PluralDatasource{Generate: true, Name: "CertificateIssuanceConfigList"},
+				ProductMetadata:  p,
+			},
+			wantName:     "google_certificate_manager_certificate_issuance_config_list",
+			wantRequired: []string{"location"},
+			wantOptional: []string{"project"},
+		},
+		{
+			description:  "legacy name",
+			obj:          api.Resource{Name: "Address", LegacyName: "google_compute_address", BaseUrl: "projects/{{project}}/regions/{{region}}/addresses", ProductMetadata: p},
+			wantName:     "google_compute_addresses",
+			wantRequired: []string{},
+			wantOptional: []string{"project", "region"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.PluralTerraformName(); got != tc.wantName {
+				t.Errorf("PluralTerraformName() = %q, want %q", got, tc.wantName)
+			}
+			if diff := cmp.Diff(tc.wantRequired, tc.obj.PluralDatasourceRequiredFields()); diff != "" {
+				t.Errorf("PluralDatasourceRequiredFields() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOptional, tc.obj.PluralDatasourceOptionalFields()); diff != "" {
+				t.Errorf("PluralDatasourceOptionalFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceIdFunctions(t *testing.T) {
+	t.Parallel()
+
+	version := &product.Version{Name: "ga", BaseUrl: "https://compute.googleapis.com/compute/v1/"}
+	p := &api.Product{Name: "Compute", Versions: []*product.Version{version}, Version: version}
+
+	cases := []struct {
+		description     string
+		obj             api.Resource
+		wantGenerate    bool
+		wantParse       string
+		wantBuild       string
+		wantRegexes     []string
+		wantParseFields []string
+		wantBuildFields []string
+		wantExampleId   string
+	}{
+		{
+			description: "default formats",
+			obj: api.Resource{
+				Name:                "Instance",
+				BaseUrl:             "projects/{{project}}/zones/{{zone}}/instances",
+				GenerateIdFunctions: true,
+				ProductMetadata:     p,
+			},
+			wantGenerate: true,
+			wantParse:    "parse_compute_instance_id",
+			wantBuild:    "build_compute_instance_id",
+			wantRegexes: []string{
+				"(?:^|/)projects/(?P<project>[^/]+)/zones/(?P<zone>[^/]+)/instances/(?P<name>[^/]+)$",
+				"^(?P<project>[^/]+)/(?P<zone>[^/]+)/(?P<name>[^/]+)$",
+				"^(?P<zone>[^/]+)/(?P<name>[^/]+)$",
+				"^(?P<name>[^/]+)$",
+			},
+			wantParseFields: []string{"project", "zone", "name"},
+			wantBuildFields: []string{"project", "zone", "name"},
+			wantExampleId:   "projects/my-project/zones/my-zone/instances/my-name",
+		},
+		{
+			description: "custom id format",
+			obj: api.Resource{
+				Name:            "Instance",
+				BaseUrl:         "projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/instances",
+				IdFormat:        "{{cluster}}/instances/{{instance_id}}",
+				ImportFormat:    []string{"projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/instances/{{instance_id}}"},
+				ProductMetadata: p,
+			},
+			wantGenerate: false,
+			wantParse:    "parse_compute_instance_id",
+			wantBuild:    "build_compute_instance_id",
+			wantRegexes: []string{
+				"(?:^|/)projects/(?P<project>[^/]+)/locations/(?P<location>[^/]+)/clusters/(?P<cluster>[^/]+)/instances/(?P<instance_id>[^/]+)$",
+				"^(?P<project>[^/]+)/(?P<location>[^/]+)/(?P<cluster>[^/]+)/(?P<instance_id>[^/]+)$",
+				"^(?P<location>[^/]+)/(?P<cluster>[^/]+)/(?P<instance_id>[^/]+)$",
+			},
+			wantParseFields: []string{"project", "location", "cluster", "instance_id"},
+			wantBuildFields: []string{"cluster", "instance_id"},
+			wantExampleId:   "my-cluster/instances/my-instance-id",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.ShouldGenerateIdFunctions(); got != tc.wantGenerate {
+				t.Errorf("ShouldGenerateIdFunctions() = %v, want %v", got, tc.wantGenerate)
+			}
+			if got := tc.obj.ParseIdFunctionName(); got != tc.wantParse {
+				t.Errorf("ParseIdFunctionName() = %q, want %q", got, tc.wantParse)
+			}
+			if got := tc.obj.BuildIdFunctionName(); got != tc.wantBuild {
+				t.Errorf("BuildIdFunctionName() = %q, want %q", got, tc.wantBuild)
+			}
+			if diff := cmp.Diff(tc.wantRegexes, tc.obj.IdFunctionRegexes()); diff != "" {
+				t.Errorf("IdFunctionRegexes() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantParseFields, tc.obj.IdFunctionParseFields()); diff != "" {
+				t.Errorf("IdFunctionParseFields() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantBuildFields, tc.obj.IdFunctionBuildFields()); diff != "" {
+				t.Errorf("IdFunctionBuildFields() mismatch (-want +got):\n%s", diff)
+			}
+			if got := tc.obj.IdFunctionExampleId(tc.obj.GetIdFormat()); got != tc.wantExampleId {
+				t.Errorf("IdFunctionExampleId() = %q, want %q", got, tc.wantExampleId)
+			}
+		})
+	}
+}
+
+func TestResourceRoundTripProperties(t *testing.T) {
+	t.Parallel()
+
+	props := []*api.Type{
+		{Name: "name", Type: "String", UrlParamOnly: true},
+		{Name: "description", Type: "String"},
+		{Name: "createTime", Type: "Time", Output: true},
+		{Name: "labels", Type: "KeyValueLabels"},
+		{Name: "size", Type: "Integer"},
+	}
+
+	cases := []struct {
+		description  string
+		obj          api.Resource
+		wantProps    []string
+		wantGenerate bool
+	}{
+		{
+			description:  "default",
+			obj:          api.Resource{Name: "Disk", Properties: props},
+			wantProps:    []string{"description", "size"},
+			wantGenerate: true,
+		},
+		{
+			description: "excluded",
+			obj:         api.Resource{Name: "Disk", Properties: props, ExcludeRoundTripTests: true},
+			wantProps:   []string{"description", "size"},
+		},
+		{
+			description: "framework resource",
+			obj:         api.Resource{Name: "Disk", Properties: props, FrameworkResource: true},
+			wantProps:   []string{"description", "size"},
+		},
+		{
+			description: "no field round-trips",
+			obj:         api.Resource{Name: "Disk", Properties: props[2:4]},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, p := range tc.obj.RoundTripProperties() {
+				got = append(got, p.Name)
+			}
+			if diff := cmp.Diff(tc.wantProps, got); diff != "" {
+				t.Errorf("RoundTripProperties() mismatch (-want +got):\n%s", diff)
+			}
+			if got := tc.obj.ShouldGenerateRoundTripTests(); got != tc.wantGenerate {
+				t.Errorf("ShouldGenerateRoundTripTests() = %v, want %v", got, tc.wantGenerate)
+			}
+		})
+	}
+}
+
+func TestResourceTGCRoundTripSteps(t *testing.T) {
+	t.Parallel()
+
+	step := func(name, hcl string) *resource.Step {
+		return &resource.Step{Name: name, DocumentationHCLText: hcl}
+	}
+	obj := api.Resource{
+		TargetVersionName: "ga",
+		Samples: []*resource.Sample{
+			{
+				Name:  "basic",
+				Steps: []*resource.Step{step("basic", "basic config"), step("basic_update", "updated config")},
+			},
+			{
+				Name:        "excluded",
+				ExcludeTest: true,
+				Steps:       []*resource.Step{step("excluded", "excluded config")},
+			},
+			{
+				Name:       "beta",
+				MinVersion: "beta",
+				Steps:      []*resource.Step{step("beta", "beta config")},
+			},
+			{
+				Name: "shared",
+				// Steps without a config are above the target version, and
+				// steps shared with another sample are converted once.
+				Steps: []*resource.Step{step("basic", "basic config"), step("beta_step", ""), step("shared", "shared config")},
+			},
+		},
+	}
+
+	var got []string
+	for _, s := range obj.TGCRoundTripSteps() {
+		got = append(got, s.Name)
+	}
+	want := []string{"basic", "basic_update", "shared"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TGCRoundTripSteps() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResourceRequestLogRedactedFields(t *testing.T) {
+	t.Parallel()
+
+	res := &api.Resource{Name: "User", ProductMetadata: &api.Product{Name: "Sql"}}
+	password := &api.Type{Name: "password", ApiName: "password", Type: "String", Sensitive: true}
+	settings := &api.Type{
+		Name:             "settings",
+		ApiName:          "settings",
+		Type:             "NestedObject",
+		ResourceMetadata: res,
+		Properties: []*api.Type{
+			{Name: "token", ApiName: "token", Type: "String", WriteOnly: true},
+			{Name: "region", ApiName: "region", Type: "String"},
+		},
+	}
+	secrets := &api.Type{
+		Name:    "secrets",
+		ApiName: "secrets",
+		Type:    "Map",
+		ValueType: &api.Type{
+			Name:             "secrets",
+			Type:             "NestedObject",
+			ResourceMetadata: res,
+			Properties: []*api.Type{
+				{Name: "value", ApiName: "value", Type: "String", Sensitive: true},
+			},
+		},
+	}
+	for _, p := range settings.Properties {
+		p.ParentMetadata = settings
+	}
+	secrets.ValueType.ParentMetadata = secrets
+	secrets.ValueType.Properties[0].ParentMetadata = secrets.ValueType
+	res.Properties = []*api.Type{password, settings, secrets, {Name: "description", ApiName: "description", Type: "String"}}
+
+	want := []string{"password", "secrets.*.value", "settings.token"}
+	if diff := cmp.Diff(want, res.RequestLogRedactedFields()); diff != "" {
+		t.Errorf("RequestLogRedactedFields() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResourceValidateFrameworkResource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		properties  []*api.Type
+		customDiff  []string
+		wantErrs    []string
+	}{
+		{
+			description: "supported",
+			properties:  []*api.Type{{Name: "description", Type: "String"}},
+		},
+		{
+			description: "labels",
+			properties:  []*api.Type{{Name: "labels", Type: "KeyValueLabels"}},
+			wantErrs:    []string{"plugin framework resources don't support `KeyValueLabels` fields, as their `terraform_labels` and `effective_labels` are set by a custom diff, used by Widget"},
+		},
+		{
+			description: "annotations",
+			properties:  []*api.Type{{Name: "annotations", Type: "KeyValueAnnotations"}},
+			wantErrs:    []string{"plugin framework resources don't support `KeyValueAnnotations` fields, as their `effective_annotations` are set by a custom diff, used by Widget"},
+		},
+		{
+			description: "custom diff",
+			properties:  []*api.Type{{Name: "description", Type: "String"}},
+			customDiff:  []string{"widgetCustomizeDiff"},
+			wantErrs:    []string{"plugin framework resources don't support `custom_diff`, used by Widget"},
+		},
+		{
+			description: "unordered list",
+			properties:  []*api.Type{{Name: "zones", Type: "Array", ItemType: &api.Type{Name: "zones", Type: "String"}, UnorderedList: true}},
+			wantErrs:    []string{"plugin framework resources don't support `unordered_list` fields, as their order is ignored by a custom diff, used by Widget"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &api.Resource{Name: "Widget", ProductMetadata: &api.Product{Name: "Widgets"}, FrameworkResource: true, CustomDiff: tc.customDiff}
+			r.Properties = r.AddExtraFields(tc.properties, nil)
+			for _, p := range r.Properties {
+				p.ResourceMetadata = r
+				if p.ItemType != nil {
+					p.ItemType.ResourceMetadata = r
+					p.ItemType.ParentMetadata = p
+				}
+			}
+
+			var got []string
+			for _, err := range r.Validate() {
+				if strings.Contains(err.Error(), "plugin framework") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
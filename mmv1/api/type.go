@@ -14,9 +14,15 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"log"
+	"math/rand"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
@@ -294,6 +300,11 @@ type Type struct {
 	// just as they are in the standard flattener template.
 	CustomFlatten string `yaml:"custom_flatten,omitempty"`
 
+	// If true, the custom_expand and custom_flatten of the field turn the
+	// values of the API into Terraform values and back without changing
+	// them, so the generated round-trip test of the resource covers them.
+	RoundTripCustomCode bool `yaml:"round_trip_custom_code,omitempty"`
+
 	ResourceMetadata *Resource `yaml:"-"`
 
 	ParentMetadata *Type `yaml:"-"`
@@ -492,6 +503,9 @@ func (t *Type) SetDefault(r *Resource) {
 	}
 }
 
+// Validate returns every problem found on the property and its nested
+// properties. Errors carry a utils.YamlPathError path relative to the
+// property's own YAML node.
 func (t *Type) Validate(rName string) (es []error) {
 	// Use Lineage to get the full path (e.g. "parent.child.grandchild") for clearer error messages.
 	fullFieldPath := t.Name
@@ -504,16 +518,16 @@ func (t *Type) Validate(rName string) (es []error) {
 	}
 
 	// Check type is valid. Also allow empty as it's currently used in unit tests.
-	if !slices.Contains([]string{"Boolean", "Double", "Integer", "String", "Time", "Enum", "ResourceRef", "NestedObject", "Array", "KeyValuePairs", "KeyValueLabels", "KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations", "Map", "Fingerprint"}, t.Type) {
-		es = append(es, fmt.Errorf("property %s unknown type %q in resource %s", fullFieldPath, t.Type, rName))
+	if !slices.Contains(PropertyTypes, t.Type) {
+		es = append(es, utils.PrefixYamlPath("type")(fmt.Errorf("property %s unknown type %q in resource %s", fullFieldPath, t.Type, rName)))
 	}
 
 	if t.Output && t.Required {
-		es = append(es, fmt.Errorf("property %s cannot be output and required at the same time in resource %s.", fullFieldPath, rName))
+		es = append(es, utils.PrefixYamlPath("required")(fmt.Errorf("property %s cannot be output and required at the same time in resource %s.", fullFieldPath, rName)))
 	}
 
 	if t.DefaultFromApi && t.DefaultValue != nil {
-		es = append(es, fmt.Errorf("property %s 'default_value' and 'default_from_api' cannot be both set in resource %s ", fullFieldPath, rName))
+		es = append(es, utils.PrefixYamlPath("default_value")(fmt.Errorf("property %s 'default_value' and 'default_from_api' cannot be both set in resource %s ", fullFieldPath, rName)))
 	}
 
 	if (t.WriteOnlyLegacy || t.WriteOnly) && (t.DefaultFromApi || t.Output) {
@@ -526,34 +540,44 @@ func (t *Type) Validate(rName string) (es []error) {
 
 	t.validateLabelsField()
 
+	es = append(es, utils.TransformErrs(utils.PrefixYamlPath("validation"), t.Validation.Validate(t.Type, fullFieldPath, rName))...)
+	if t.ItemType != nil {
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("item_validation"), t.ItemValidation.Validate(t.ItemType.Type, fullFieldPath, rName))...)
+	} else if !reflect.DeepEqual(t.ItemValidation, resource.Validation{}) {
+		es = append(es, utils.PrefixYamlPath("item_validation")(fmt.Errorf("property %s cannot set item_validation without item_type in resource %s", fullFieldPath, rName)))
+	}
+
 	switch {
 	case t.IsA("Array"):
-		es = append(es, t.ItemType.Validate(rName)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("item_type"), t.ItemType.Validate(rName))...)
 	case t.IsA("Map"):
 		// ValueType.Name should be empty (because it's unused) but we require types to have names in all other cases.
 		// This logic allows both to be validated.
 		oldName := t.ValueType.Name
 		t.ValueType.Name = "any_value"
-		es = append(es, t.ValueType.Validate(rName)...)
+		es = append(es, utils.TransformErrs(utils.PrefixYamlPath("value_type"), t.ValueType.Validate(rName))...)
 		t.ValueType.Name = oldName
 		if t.ValueType.Name != "" {
-			es = append(es, fmt.Errorf("property %s value_type.name can't be set in resource %s", fullFieldPath, rName))
+			es = append(es, utils.PrefixYamlPath("value_type", "name")(fmt.Errorf("property %s value_type.name can't be set in resource %s", fullFieldPath, rName)))
 		}
 	case t.IsA("NestedObject"):
 		for _, p := range t.Properties {
-			es = append(es, p.Validate(rName)...)
+			es = append(es, utils.TransformErrs(utils.PrefixYamlPath("properties", p.Name), p.Validate(rName))...)
 		}
 	default:
 	}
 
 	// UpdateMask isn't supported on nested fields: https://github.com/hashicorp/terraform-provider-google/issues/26382
 	if t.ParentMetadata != nil && !t.ParentMetadata.FlattenObject && len(t.UpdateMaskFields) > 0 {
-		es = append(es, fmt.Errorf("property %s cannot set update_mask_fields because it is nested in resource %s", fullFieldPath, rName))
+		es = append(es, utils.PrefixYamlPath("update_mask_fields")(fmt.Errorf("property %s cannot set update_mask_fields because it is nested in resource %s", fullFieldPath, rName)))
 	}
 
 	return es
 }
 
+// The values allowed for a property's `type`.
+var PropertyTypes = []string{"Boolean", "Double", "Integer", "String", "Time", "Enum", "ResourceRef", "NestedObject", "Array", "KeyValuePairs", "KeyValueLabels", "KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations", "Map", "Fingerprint"}
+
 // TODO rewrite: add validations
 // check :description, required: true
 // check :update_verb, allowed: %i[POST PUT PATCH NONE],
@@ -615,6 +639,28 @@ func (t Type) ApiLineage() []string {
 	return append(t.ParentMetadata.ApiLineage(), t.ApiName)
 }
 
+// Returns a slice of API field names representing where the field is nested within the
+// request body, where "*" stands for the keys of Map fields. For example,
+// []string{"parentField", "*", "fooBar"}.
+func (t Type) RequestLogPath() []string {
+	if t.ParentMetadata == nil {
+		return []string{t.ApiName}
+	}
+
+	// Skip arrays because items of arrays are at the path of the array
+	if t.ParentMetadata.IsA("Array") {
+		return t.ParentMetadata.RequestLogPath()
+	}
+
+	// Children of Map fields are nested under the key of the entry, and have the
+	// same Name as the parent field.
+	if t.ParentMetadata.IsA("Map") {
+		return append(t.ParentMetadata.RequestLogPath(), "*")
+	}
+
+	return append(t.ParentMetadata.RequestLogPath(), t.ApiName)
+}
+
 func (t Type) EnumValuesToString(quoteSeperator string, addEmpty bool) string {
 	var values []string
 
@@ -629,6 +675,106 @@ func (t Type) EnumValuesToString(quoteSeperator string, addEmpty bool) string {
 	return strings.Join(values, ", ")
 }
 
+// ValidateFunc returns the SDK ValidateFunc for the property, or "" if it
+// has no validation.
+func (t Type) ValidateFunc() string {
+	return t.Validation.ValidateFunc(t.Type)
+}
+
+// ItemValidateFunc returns the SDK ValidateFunc for each item of an Array
+// property, or "" if it has no item validation.
+func (t Type) ItemValidateFunc() string {
+	if t.ItemType == nil {
+		return ""
+	}
+	return t.ItemValidation.ValidateFunc(t.ItemType.Type)
+}
+
+// FWValidators returns the plugin framework validators for the property.
+// Item validation of an Array is applied through the list or set validator
+// matching its item type.
+func (t Type) FWValidators() []string {
+	if t.FWComputedOnly() {
+		return nil
+	}
+	pkg := strings.ToLower(t.FWAttributeType()) + "validator"
+
+	var validators []string
+	switch {
+	case t.IsA("Array") && t.ItemType != nil:
+		var items []string
+		if t.ItemType.IsA("Enum") {
+			items = append(items, fmt.Sprintf("stringvalidator.OneOf(%s)", t.ItemType.EnumValuesToString("\"", false)))
+		}
+		items = append(items, t.ItemValidation.FWValidators(t.ItemType.Type)...)
+		if len(items) > 0 {
+			validators = append(validators, fmt.Sprintf("%s.Value%ssAre(%s)", pkg, t.ItemType.GetFWType(), strings.Join(items, ", ")))
+		}
+		if t.MinSize != nil {
+			validators = append(validators, fmt.Sprintf("%s.SizeAtLeast(%d)", pkg, *t.MinSize))
+		}
+		if t.MaxSize != nil {
+			validators = append(validators, fmt.Sprintf("%s.SizeAtMost(%d)", pkg, *t.MaxSize))
+		}
+	case t.IsA("Enum"):
+		validators = append(validators, fmt.Sprintf("stringvalidator.OneOf(%s)", t.EnumValuesToString("\"", true)))
+	case t.IsA("NestedObject"):
+		// Nested objects are lists of at most one object
+		validators = append(validators, fmt.Sprintf("%s.SizeAtMost(1)", pkg))
+	default:
+		validators = t.Validation.FWValidators(t.Type)
+	}
+	if t.Required && t.IsFWBlock() {
+		validators = append(validators, fmt.Sprintf("%s.IsRequired()", pkg))
+	}
+
+	for _, v := range []struct {
+		name  string
+		paths []string
+	}{
+		{"ConflictsWith", t.Conflicting()},
+		{"AlsoRequires", t.RequiredWithList()},
+		{"AtLeastOneOf", t.AtLeastOneOfList()},
+		{"ExactlyOneOf", t.ExactlyOneOfList()},
+	} {
+		if exprs := t.fwPathExpressions(v.paths); len(exprs) > 0 {
+			validators = append(validators, fmt.Sprintf("%s.%s(%s)", pkg, v.name, strings.Join(exprs, ", ")))
+		}
+	}
+	return validators
+}
+
+// fwPathExpressions returns plugin framework path expressions matching the
+// fields at the given paths, e.g. `path.MatchRoot("a").AtListIndex(0).AtName("b")`
+// for "a.0.b".
+func (t Type) fwPathExpressions(paths []string) []string {
+	var exprs []string
+	for _, p := range t.GetPropertySchemaPathList(paths) {
+		var expr string
+		for i, part := range strings.Split(p, ".") {
+			switch n, err := strconv.Atoi(part); {
+			case i == 0:
+				expr = fmt.Sprintf("path.MatchRoot(%q)", part)
+			case err == nil:
+				expr += fmt.Sprintf(".AtListIndex(%d)", n)
+			default:
+				expr += fmt.Sprintf(".AtName(%q)", part)
+			}
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs
+}
+
+// ValidationDocumentation describes the values allowed by the property's
+// validation for the generated docs.
+func (t Type) ValidationDocumentation() string {
+	if t.IsA("Array") && t.ItemType != nil {
+		return t.ItemValidation.Documentation(t.ItemType.Type, true)
+	}
+	return t.Validation.Documentation(t.Type, false)
+}
+
 func (t Type) TitlelizeProperty() string {
 	return google.Camelize(t.Name, "upper")
 }
@@ -1005,6 +1151,73 @@ func (t Type) GetFWType() string {
 	return "String"
 }
 
+// FWAttributeType returns the kind of plugin framework attribute or block of
+// the property, e.g. "Set" for schema.SetAttribute or schema.SetNestedBlock.
+// Nested objects are lists of at most one object, and maps of nested objects
+// are sets, as they are in SDK resources.
+func (t Type) FWAttributeType() string {
+	switch {
+	case t.IsA("NestedObject"):
+		return "List"
+	case t.IsA("Array") && t.IsSet, t.IsA("Map"):
+		return "Set"
+	}
+	return t.GetFWType()
+}
+
+// IsFWBlock returns whether the property is a nested block in plugin framework
+// resources. Nested objects are blocks as they are in SDK resources, except
+// when they or their parents are computed, which blocks can't be.
+func (t Type) IsFWBlock() bool {
+	if t.FlattenObject {
+		return false
+	}
+	for p := &t; p != nil; p = p.Parent() {
+		if p.Output || p.DefaultFromApi {
+			return false
+		}
+	}
+	return t.IsFWNested()
+}
+
+// IsFWNested returns whether the property is a nested attribute or block in
+// plugin framework resources: a NestedObject, an Array of them or a Map.
+func (t Type) IsFWNested() bool {
+	switch {
+	case t.IsA("NestedObject"), t.IsA("Map"):
+		return true
+	case t.IsA("Array"):
+		return t.ItemType != nil && t.ItemType.IsA("NestedObject")
+	}
+	return false
+}
+
+// FWNestedProperties returns the properties of the objects of a nested
+// attribute or block: those of a NestedObject, of the items of an Array or of
+// the values of a Map.
+func (t Type) FWNestedProperties() []*Type {
+	switch {
+	case t.IsA("NestedObject"):
+		return t.ResourceMetadata.OrderProperties(t.UserProperties())
+	case t.IsA("Map"):
+		return t.ResourceMetadata.OrderProperties(t.ValueType.UserProperties())
+	case t.IsFWNested():
+		return t.ResourceMetadata.OrderProperties(t.ItemType.UserProperties())
+	}
+	return nil
+}
+
+// FWComputedOnly returns whether the property or one of its parents is output
+// only, in which case plugin framework resources only compute it.
+func (t Type) FWComputedOnly() bool {
+	for p := &t; p != nil; p = p.Parent() {
+		if p.Output {
+			return true
+		}
+	}
+	return false
+}
+
 // TODO rewrite: validation
 // // Represents an enum, and store is valid values
 // class Enum < Primitive
@@ -1562,3 +1775,104 @@ func (t Type) HasRequiredProperty() bool {
 	}
 	return false
 }
+
+// RoundTripJson returns a pseudo-random value of the field, as returned by
+// the API, for the generated test flattening it and expanding it back. It's
+// empty if the field can't round-trip, as when it's output-only, isn't read
+// or written, is converted by custom code or its value can't be marshalled.
+// Values are seeded by the lineage of the field, so generated tests don't
+// change between runs.
+func (t *Type) RoundTripJson() string {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(t.Lineage(), ".")))
+	v, ok := t.roundTripValue(rand.New(rand.NewSource(int64(h.Sum64()))))
+	if !ok {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Warning: skipping the round-trip test of %s, as its value can't be marshalled: %v", strings.Join(t.Lineage(), "."), err)
+		return ""
+	}
+	return string(b)
+}
+
+func (t *Type) roundTripValue(rng *rand.Rand) (any, bool) {
+	if t.Output || t.UrlParamOnly || t.IgnoreRead || t.ClientSide || t.WriteOnly || t.WriteOnlyLegacy ||
+		t.FlattenObject || t.IgnoreWrite || t.Removed() || !t.roundTripsCustomCode() {
+		return nil, false
+	}
+	return t.roundTripItem(t, rng)
+}
+
+// roundTripsCustomCode returns whether the custom code of the field, if any,
+// round-trips values.
+func (t *Type) roundTripsCustomCode() bool {
+	return (t.CustomExpand == "" && t.CustomFlatten == "") || t.RoundTripCustomCode
+}
+
+// roundTripItem returns a value of item, which is t or the item type of t.
+func (t *Type) roundTripItem(item *Type, rng *rand.Rand) (any, bool) {
+	name := strings.ReplaceAll(google.Underscore(t.Name), "_", "-")
+	switch {
+	case item.IsA("String") || item.IsA("Fingerprint") || item.IsA("ResourceRef"):
+		return fmt.Sprintf("%s-%04x", name, rng.Intn(0x10000)), true
+	case item.IsA("Time"):
+		return time.Date(2020+rng.Intn(10), time.Month(1+rng.Intn(12)), 1+rng.Intn(28), rng.Intn(24), rng.Intn(60), rng.Intn(60), 0, time.UTC).Format(time.RFC3339), true
+	case item.IsA("Integer"):
+		return rng.Intn(1000) + 1, true
+	case item.IsA("Double"):
+		// Quarters are exact in binary, so they survive float conversions.
+		return float64(rng.Intn(1000)+1) / 4, true
+	case item.IsA("Boolean"):
+		// false is dropped from requests unless send_empty_value is set.
+		return true, true
+	case item.IsA("Enum"):
+		values := google.Reject(item.EnumValues, func(v string) bool { return v == "" })
+		if len(values) == 0 {
+			return nil, false
+		}
+		return values[rng.Intn(len(values))], true
+	case item.IsA("KeyValuePairs"):
+		return map[string]any{fmt.Sprintf("key-%04x", rng.Intn(0x10000)): fmt.Sprintf("value-%04x", rng.Intn(0x10000))}, true
+	case item.IsA("Array") && item == t:
+		if item.ItemType == nil {
+			return nil, false
+		}
+		// Sets don't keep the order of their items.
+		n := 2
+		if t.IsSet {
+			n = 1
+		}
+		var l []any
+		for i := 0; i < n; i++ {
+			v, ok := t.roundTripItem(item.ItemType, rng)
+			if !ok {
+				return nil, false
+			}
+			l = append(l, v)
+		}
+		return l, true
+	case item.IsA("NestedObject"):
+		obj := make(map[string]any)
+		for _, p := range item.Properties {
+			if p.Exclude {
+				continue
+			}
+			if v, ok := p.roundTripValue(rng); ok {
+				obj[p.ApiName] = v
+				continue
+			}
+			// Fields left out of the value must stay out of the expanded
+			// value too.
+			if p.SendEmptyValue || p.WriteOnly || p.WriteOnlyLegacy || p.FlattenObject || !p.roundTripsCustomCode() {
+				return nil, false
+			}
+		}
+		if len(obj) == 0 {
+			return nil, false
+		}
+		return obj, true
+	}
+	return nil, false
+}
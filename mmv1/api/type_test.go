@@ -1,7 +1,10 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -631,3 +634,248 @@ func TestProviderOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeFWBlocks(t *testing.T) {
+	t.Parallel()
+
+	root := Type{
+		Name: "root",
+		Type: "NestedObject",
+		Properties: []*Type{
+			{
+				Name: "name",
+				Type: "String",
+			},
+			{
+				Name: "tags",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "String",
+				},
+			},
+			{
+				Name: "rules",
+				Type: "Array",
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						{
+							Name: "action",
+							Type: "String",
+						},
+					},
+				},
+			},
+			{
+				Name:   "status",
+				Type:   "NestedObject",
+				Output: true,
+				Properties: []*Type{
+					{
+						Name: "state",
+						Type: "String",
+					},
+				},
+			},
+		},
+	}
+	root.SetDefault(&Resource{})
+
+	cases := []struct {
+		description       string
+		obj               *Type
+		wantBlock         bool
+		wantAttributeType string
+		wantComputedOnly  bool
+	}{
+		{
+			description:       "nested object",
+			obj:               &root,
+			wantBlock:         true,
+			wantAttributeType: "List",
+		},
+		{
+			description:       "string",
+			obj:               root.Properties[0],
+			wantAttributeType: "String",
+		},
+		{
+			description:       "array of strings",
+			obj:               root.Properties[1],
+			wantAttributeType: "List",
+		},
+		{
+			description:       "array of nested objects",
+			obj:               root.Properties[2],
+			wantBlock:         true,
+			wantAttributeType: "List",
+		},
+		{
+			description:       "output-only nested object",
+			obj:               root.Properties[3],
+			wantAttributeType: "List",
+			wantComputedOnly:  true,
+		},
+		{
+			description:       "field of an output-only nested object",
+			obj:               root.Properties[3].Properties[0],
+			wantAttributeType: "String",
+			wantComputedOnly:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.obj.IsFWBlock(); got != tc.wantBlock {
+				t.Errorf("IsFWBlock() = %v, want %v", got, tc.wantBlock)
+			}
+			if got := tc.obj.FWAttributeType(); got != tc.wantAttributeType {
+				t.Errorf("FWAttributeType() = %q, want %q", got, tc.wantAttributeType)
+			}
+			if got := tc.obj.FWComputedOnly(); got != tc.wantComputedOnly {
+				t.Errorf("FWComputedOnly() = %v, want %v", got, tc.wantComputedOnly)
+			}
+		})
+	}
+}
+
+func TestTypeRoundTripJson(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         Type
+		// expected is the shape of the value, or empty if there's none.
+		expected string
+	}{
+		{
+			description: "string",
+			obj:         Type{Name: "displayName", Type: "String"},
+			expected:    "string",
+		},
+		{
+			description: "output",
+			obj:         Type{Name: "createTime", Type: "Time", Output: true},
+		},
+		{
+			description: "custom code",
+			obj:         Type{Name: "network", Type: "String", CustomExpand: "templates/terraform/custom_expand/resourceref_with_validation.go.tmpl"},
+		},
+		{
+			description: "custom code round-tripping",
+			obj:         Type{Name: "network", Type: "String", CustomFlatten: "templates/terraform/custom_flatten/name_from_self_link.tmpl", RoundTripCustomCode: true},
+			expected:    "string",
+		},
+		{
+			description: "list",
+			obj:         Type{Name: "sizes", Type: "Array", ItemType: &Type{Type: "Integer"}},
+			expected:    "[number,number]",
+		},
+		{
+			description: "set",
+			obj:         Type{Name: "regions", Type: "Array", IsSet: true, ItemType: &Type{Type: "String"}},
+			expected:    "[string]",
+		},
+		{
+			description: "map",
+			obj:         Type{Name: "users", Type: "Map", KeyName: "user", ValueType: &Type{Type: "NestedObject", Properties: []*Type{{Name: "role", Type: "String"}}}},
+		},
+		{
+			description: "nested object",
+			obj: Type{
+				Name: "settings",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "enabled", Type: "Boolean"},
+					{Name: "tier", Type: "Enum", EnumValues: []string{"BASIC", "PREMIUM"}},
+					{Name: "labels", Type: "KeyValuePairs"},
+					{Name: "state", Type: "String", Output: true},
+					{Name: "ratio", Type: "Double", ApiName: "ratioValue"},
+				},
+			},
+			expected: "{enabled:bool,labels:{string},ratioValue:number,tier:string}",
+		},
+		{
+			description: "nested object with a field sent empty",
+			obj: Type{
+				Name: "settings",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{Name: "enabled", Type: "Boolean"},
+					{Name: "password", Type: "String", IgnoreRead: true, SendEmptyValue: true},
+				},
+			},
+		},
+		{
+			description: "nested object without fields",
+			obj: Type{
+				Name:       "settings",
+				Type:       "NestedObject",
+				Properties: []*Type{{Name: "state", Type: "String", Output: true}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.obj.SetDefault(&Resource{})
+			got := tc.obj.RoundTripJson()
+			if got != tc.obj.RoundTripJson() {
+				t.Errorf("RoundTripJson() = %s, then %s, want the same value", got, tc.obj.RoundTripJson())
+			}
+			if got == "" || tc.expected == "" {
+				if got != tc.expected {
+					t.Errorf("RoundTripJson() = %q, want %q", got, tc.expected)
+				}
+				return
+			}
+			var v any
+			if err := json.Unmarshal([]byte(got), &v); err != nil {
+				t.Fatalf("RoundTripJson() = %s, which isn't JSON: %s", got, err)
+			}
+			if shape := jsonShape(v); shape != tc.expected {
+				t.Errorf("RoundTripJson() = %s, with shape %s, want %s", got, shape, tc.expected)
+			}
+		})
+	}
+}
+
+// jsonShape describes the types of a decoded JSON value, such as
+// {enabled:bool,sizes:[number]}.
+func jsonShape(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		var fields []string
+		for k, e := range v {
+			if strings.HasPrefix(k, "key-") {
+				k = ""
+			} else {
+				k += ":"
+			}
+			fields = append(fields, k+jsonShape(e))
+		}
+		sort.Strings(fields)
+		return "{" + strings.Join(fields, ",") + "}"
+	case []any:
+		var items []string
+		for _, e := range v {
+			items = append(items, jsonShape(e))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"reflect"
+	"slices"
 )
 
 // IsEmpty checks if a value is meaningfully empty in a recursive way
@@ -86,3 +88,41 @@ func TransformErrs(fn ErrTransformer, errs []error) (prefixedErrs []error) {
 
 	return prefixedErrs
 }
+
+// YamlPathError is a validation error tied to a location in the source YAML
+// file. Path is a list of keys from the document root; a key that follows a
+// list-valued key selects the list entry whose `name` equals it, e.g.
+// ["properties", "network", "properties", "subnetwork"].
+type YamlPathError struct {
+	Path []string
+	Err  error
+}
+
+func (e *YamlPathError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *YamlPathError) Unwrap() error {
+	return e.Err
+}
+
+// PrefixYamlPath returns an ErrTransformer that nests errors under the given
+// YAML path. Errors that don't carry a path yet are attached to the prefix.
+func PrefixYamlPath(prefix ...string) ErrTransformer {
+	return func(err error) error {
+		var pathErr *YamlPathError
+		if errors.As(err, &pathErr) {
+			return &YamlPathError{Path: append(slices.Clone(prefix), pathErr.Path...), Err: pathErr.Err}
+		}
+		return &YamlPathError{Path: slices.Clone(prefix), Err: err}
+	}
+}
+
+// YamlPath returns the YAML path attached to err, if any.
+func YamlPath(err error) []string {
+	var pathErr *YamlPathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Path
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -119,3 +121,25 @@ func TestIsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestPrefixYamlPath(t *testing.T) {
+	base := errors.New("bad type")
+
+	err := PrefixYamlPath("type")(base)
+	err = PrefixYamlPath("properties", "child")(err)
+	err = TransformErrs(PrefixYamlPath("properties", "parent"), []error{err})[0]
+
+	want := []string{"properties", "parent", "properties", "child", "type"}
+	if got := YamlPath(err); !slices.Equal(got, want) {
+		t.Errorf("YamlPath() = %q, expected %q", got, want)
+	}
+	if err.Error() != base.Error() {
+		t.Errorf("Error() = %q, expected %q", err.Error(), base.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Errorf("expected prefixed error to wrap the original error")
+	}
+	if got := YamlPath(base); got != nil {
+		t.Errorf("YamlPath() on a plain error = %q, expected nil", got)
+	}
+}
@@ -112,14 +112,17 @@ func main() {
 
 	switch *typeFlag {
 	case "product":
-		generator.GenerateProductFile(*outputPathFlag)
+		err = generator.GenerateProductFile(*outputPathFlag)
 	case "resource":
-		generator.GenerateResourceFile(*product.Objects[0], *outputPathFlag)
+		err = generator.GenerateResourceFile(*product.Objects[0], *outputPathFlag)
 	case "metadata":
-		generator.GenerateResourceMetadataFile(*product.Objects[0], *outputPathFlag)
+		err = generator.GenerateResourceMetadataFile(*product.Objects[0], *outputPathFlag)
 	case "operation":
-		generator.GenerateOperationFile(*product.Objects[0], *outputPathFlag)
+		err = generator.GenerateOperationFile(*product.Objects[0], *outputPathFlag)
 	case "sweeper":
-		generator.GenerateResourceSweeperFile(*product.Objects[0], *outputPathFlag)
+		err = generator.GenerateResourceSweeperFile(*product.Objects[0], *outputPathFlag)
+	}
+	if err != nil {
+		log.Fatalf("error generating %s: %v", *typeFlag, err)
 	}
 }
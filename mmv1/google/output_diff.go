@@ -0,0 +1,326 @@
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	OutputChangeAdded    = "added"
+	OutputChangeModified = "modified"
+	OutputChangeDeleted  = "deleted"
+
+	DiffFormatUnified = "unified"
+	DiffFormatJSON    = "json"
+)
+
+// Marker present in every file written by the generator, handwritten copies
+// included. Only files carrying it are ever reported as deleted.
+const generatedFileMarker = "***     AUTO GENERATED CODE    ***"
+
+// Upper bound on the edit distance computed per file. Files that differ more
+// than this are shown as fully replaced.
+const maxDiffEditDistance = 2000
+
+// OutputChange describes how a dry run would change a single output file.
+// Path is relative to the output folder.
+type OutputChange struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+
+	old, new []byte
+}
+
+// ComputeOutputChanges compares the files written to and removed from mem
+// against the existing tree at outputFolder. If detectDeletions is set, generated files that sit
+// next to written files but weren't written themselves are reported as
+// deleted; this is only meaningful when every product was generated.
+func ComputeOutputChanges(mem *MemoryOutputFS, outputFolder string, detectDeletions bool) ([]OutputChange, error) {
+	var changes []OutputChange
+	written := make(map[string]bool)
+	dirs := make(map[string]bool)
+
+	for _, p := range mem.Paths() {
+		written[p] = true
+		dirs[filepath.Dir(p)] = true
+
+		rel, err := filepath.Rel(outputFolder, p)
+		if err != nil {
+			return nil, err
+		}
+		newContent, _ := mem.Written(p)
+		oldContent, err := os.ReadFile(p)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			changes = append(changes, OutputChange{Path: rel, Status: OutputChangeAdded, new: newContent})
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(oldContent, newContent):
+			changes = append(changes, OutputChange{Path: rel, Status: OutputChangeModified, old: oldContent, new: newContent})
+		}
+	}
+
+	for _, p := range mem.RemovedPaths() {
+		content, err := os.ReadFile(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(outputFolder, p)
+		if err != nil {
+			return nil, err
+		}
+		written[p] = true
+		changes = append(changes, OutputChange{Path: rel, Status: OutputChangeDeleted, old: content})
+	}
+
+	if detectDeletions {
+		for dir := range dirs {
+			entries, err := os.ReadDir(dir)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				p := filepath.Join(dir, e.Name())
+				if e.IsDir() || written[p] {
+					continue
+				}
+				content, err := os.ReadFile(p)
+				if err != nil {
+					return nil, err
+				}
+				if !bytes.Contains(content, []byte(generatedFileMarker)) {
+					continue
+				}
+				rel, err := filepath.Rel(outputFolder, p)
+				if err != nil {
+					return nil, err
+				}
+				changes = append(changes, OutputChange{Path: rel, Status: OutputChangeDeleted, old: content})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b OutputChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return changes, nil
+}
+
+// WriteOutputChanges writes changes to w, either as a unified diff or as a
+// JSON list of paths and statuses.
+func WriteOutputChanges(w io.Writer, format string, changes []OutputChange) error {
+	switch format {
+	case "", DiffFormatUnified:
+		for _, c := range changes {
+			if _, err := io.WriteString(w, UnifiedDiff(c.Path, c.old, c.new, c.Status)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case DiffFormatJSON:
+		if changes == nil {
+			changes = []OutputChange{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	default:
+		return fmt.Errorf("unknown diff format %q, expected one of %q", format, []string{DiffFormatUnified, DiffFormatJSON})
+	}
+}
+
+// UnifiedDiff renders a git-style unified diff with three lines of context
+// between the old and new contents of path.
+func UnifiedDiff(path string, old, new []byte, status string) string {
+	oldName, newName := "a/"+path, "b/"+path
+	switch status {
+	case OutputChangeAdded:
+		oldName = "/dev/null"
+	case OutputChangeDeleted:
+		newName = "/dev/null"
+	}
+
+	ops := diffLines(splitLines(old), splitLines(new))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- %s\n+++ %s\n", path, path, oldName, newName)
+	for _, h := range diffHunks(ops, 3) {
+		sb.WriteString(h)
+	}
+	return sb.String()
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffLines computes a shortest edit script between a and b using Myers'
+// algorithm.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceLines(a, b)
+	}
+
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		if d > maxDiffEditDistance {
+			return replaceLines(a, b)
+		}
+		// Only v[-d-1 .. d+1] can be read while backtracking from step d.
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b)
+			}
+		}
+	}
+	return replaceLines(a, b)
+}
+
+func backtrackDiff(trace [][]int, a, b []string) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+			x--
+		}
+	}
+	slices.Reverse(ops)
+	return ops
+}
+
+func replaceLines(a, b []string) []diffOp {
+	var ops []diffOp
+	for _, l := range a {
+		ops = append(ops, diffOp{'-', l})
+	}
+	for _, l := range b {
+		ops = append(ops, diffOp{'+', l})
+	}
+	return ops
+}
+
+// diffHunks groups ops into hunks, keeping context unchanged lines around
+// each change and merging hunks whose context overlaps.
+func diffHunks(ops []diffOp, context int) []string {
+	var hunks []string
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := max(0, i-context)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(run, end+context)
+				break
+			}
+			end = run
+		}
+
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		var body strings.Builder
+		oldLen, newLen := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldLen++
+			}
+			if op.kind != '-' {
+				newLen++
+			}
+			body.WriteByte(op.kind)
+			body.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		if oldLen == 0 {
+			oldLine--
+		}
+		if newLen == 0 {
+			newLine--
+		}
+		hunks = append(hunks, fmt.Sprintf("@@ -%d,%d +%d,%d @@\n%s", oldLine, oldLen, newLine, newLen, body.String()))
+		i = end
+	}
+	return hunks
+}
@@ -0,0 +1,107 @@
+package google
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	old := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+	new := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn")
+
+	want := strings.Join([]string{
+		"diff --git a/x.go b/x.go",
+		"--- a/x.go",
+		"+++ b/x.go",
+		"@@ -1,5 +1,5 @@",
+		" a",
+		"-b",
+		"+B",
+		" c",
+		" d",
+		" e",
+		"@@ -11,3 +11,4 @@",
+		" k",
+		" l",
+		" m",
+		"+n",
+		"\\ No newline at end of file",
+		"",
+	}, "\n")
+	if got := UnifiedDiff("x.go", old, new, OutputChangeModified); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nexpected\n%s", got, want)
+	}
+
+	want = "diff --git a/y.go b/y.go\n--- /dev/null\n+++ b/y.go\n@@ -0,0 +1,2 @@\n+one\n+two\n"
+	if got := UnifiedDiff("y.go", nil, []byte("one\ntwo\n"), OutputChangeAdded); got != want {
+		t.Errorf("UnifiedDiff() for an added file =\n%s\nexpected\n%s", got, want)
+	}
+}
+
+func TestComputeOutputChanges(t *testing.T) {
+	out := t.TempDir()
+	dir := filepath.Join(out, "services", "foo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"unchanged.go": "// " + generatedFileMarker + "\nsame\n",
+		"changed.go":   "// " + generatedFileMarker + "\nold\n",
+		"stale.go":     "// " + generatedFileMarker + "\nstale\n",
+		"handmade.go":  "package foo\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mem := NewMemoryOutputFS()
+	mem.WriteFile(filepath.Join(dir, "unchanged.go"), []byte(files["unchanged.go"]), 0644)
+	mem.WriteFile(filepath.Join(dir, "changed.go"), []byte("// "+generatedFileMarker+"\nnew\n"), 0644)
+	mem.WriteFile(filepath.Join(dir, "added.go"), []byte("package foo\n"), 0644)
+
+	if b, err := mem.ReadFile(filepath.Join(dir, "handmade.go")); err != nil || string(b) != files["handmade.go"] {
+		t.Errorf("ReadFile() of an unwritten file should fall through to disk, got %q, %v", b, err)
+	}
+
+	changes, err := ComputeOutputChanges(mem, out, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Status+" "+c.Path)
+	}
+	want := []string{
+		"added services/foo/added.go",
+		"modified services/foo/changed.go",
+		"deleted services/foo/stale.go",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ComputeOutputChanges() = %q, expected %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOutputChanges(&buf, DiffFormatJSON, changes); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"status": "deleted"`) {
+		t.Errorf("JSON output missing deleted entry:\n%s", buf.String())
+	}
+
+	changes, err = ComputeOutputChanges(mem, out, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Errorf("expected deletions to be ignored when detectDeletions is false, got %d changes", len(changes))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "added.go")); !os.IsNotExist(err) {
+		t.Errorf("MemoryOutputFS must not write to disk")
+	}
+}
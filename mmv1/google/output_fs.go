@@ -0,0 +1,310 @@
+package google
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OutputFS is the filesystem generators write their output to. Paths are
+// regular OS paths rather than fs.FS-style slash paths, since output folders
+// are usually absolute.
+type OutputFS interface {
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	ReadFile(name string) ([]byte, error)
+	Stat(name string) (fs.FileInfo, error)
+	Remove(name string) error
+}
+
+// OSOutputFS writes directly to disk.
+type OSOutputFS struct{}
+
+func (OSOutputFS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OSOutputFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OSOutputFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OSOutputFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSOutputFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// MemoryOutputFS keeps every write in memory and leaves the disk untouched.
+// Reads and stats of files that haven't been written fall through to disk, so
+// generators that post-process existing files behave as they would for real.
+//
+// It is safe for concurrent use.
+type MemoryOutputFS struct {
+	mu      sync.Mutex
+	files   map[string]*memoryFile
+	dirs    map[string]fs.FileMode
+	removed map[string]bool
+}
+
+type memoryFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func NewMemoryOutputFS() *MemoryOutputFS {
+	return &MemoryOutputFS{files: make(map[string]*memoryFile), dirs: make(map[string]fs.FileMode), removed: make(map[string]bool)}
+}
+
+func (m *MemoryOutputFS) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs[filepath.Clean(path)] = perm
+	return nil
+}
+
+func (m *MemoryOutputFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filepath.Clean(name)] = &memoryFile{data: append([]byte(nil), data...), mode: perm, modTime: time.Now()}
+	delete(m.removed, filepath.Clean(name))
+	return nil
+}
+
+func (m *MemoryOutputFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	f, ok := m.files[filepath.Clean(name)]
+	m.mu.Unlock()
+	if ok {
+		return append([]byte(nil), f.data...), nil
+	}
+	if m.isRemoved(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return os.ReadFile(name)
+}
+
+func (m *MemoryOutputFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	f, ok := m.files[filepath.Clean(name)]
+	m.mu.Unlock()
+	if ok {
+		return memoryFileInfo{name: filepath.Base(name), file: f}, nil
+	}
+	if m.isRemoved(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return os.Stat(name)
+}
+
+// Remove drops a written file, and hides a file on disk from later reads.
+func (m *MemoryOutputFS) Remove(name string) error {
+	if _, err := m.Stat(name); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, filepath.Clean(name))
+	m.removed[filepath.Clean(name)] = true
+	return nil
+}
+
+func (m *MemoryOutputFS) isRemoved(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removed[filepath.Clean(name)]
+}
+
+// Paths returns the sorted paths of every file written so far.
+func (m *MemoryOutputFS) Paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var paths []string
+	for p := range m.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// RemovedPaths returns the sorted paths of every file removed so far that
+// wasn't written again afterwards.
+func (m *MemoryOutputFS) RemovedPaths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var paths []string
+	for p := range m.removed {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Written returns the in-memory contents of a file written to m.
+func (m *MemoryOutputFS) Written(name string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, false
+	}
+	return f.data, true
+}
+
+// Commit writes every file written to m to dst, creating the directories
+// they're in along with any created through MkdirAll, and then removes the
+// files removed from m. If a write or removal fails, the changes already made
+// to dst are rolled back: overwritten and removed files get their previous
+// contents back, and the files and directories Commit created are removed.
+func (m *MemoryOutputFS) Commit(dst OutputFS) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var undo []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](); undoErr != nil {
+				err = errors.Join(err, fmt.Errorf("rolling back: %w", undoErr))
+			}
+		}
+	}()
+
+	mkdirAll := func(dir string, perm fs.FileMode) error {
+		created, err := missingDirs(dst, dir)
+		if err != nil {
+			return err
+		}
+		if err := dst.MkdirAll(dir, perm); err != nil {
+			return err
+		}
+		undo = append(undo, func() error {
+			// created is ordered from the deepest directory up.
+			for _, d := range created {
+				if err := dst.Remove(d); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+			return nil
+		})
+		return nil
+	}
+
+	var dirs []string
+	for d := range m.dirs {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+	for _, d := range dirs {
+		if err := mkdirAll(d, m.dirs[d]); err != nil {
+			return err
+		}
+	}
+
+	var paths []string
+	for p := range m.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := mkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			return err
+		}
+		restore, err := restoreFunc(dst, p)
+		if err != nil {
+			return err
+		}
+		if err := dst.WriteFile(p, m.files[p].data, m.files[p].mode); err != nil {
+			return err
+		}
+		undo = append(undo, restore)
+	}
+
+	var removed []string
+	for p := range m.removed {
+		removed = append(removed, p)
+	}
+	sort.Strings(removed)
+	for _, p := range removed {
+		restore, err := restoreFunc(dst, p)
+		if err != nil {
+			return err
+		}
+		if err := dst.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		undo = append(undo, restore)
+	}
+	return nil
+}
+
+// missingDirs returns dir and those of its parents that don't exist in fsys,
+// from the deepest up.
+func missingDirs(fsys OutputFS, dir string) ([]string, error) {
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := fsys.Stat(d); err == nil {
+			break
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	return missing, nil
+}
+
+// restoreFunc returns a function restoring the file at name in fsys to its
+// current state: its current contents, or no file if there is none.
+func restoreFunc(fsys OutputFS, name string) (func() error, error) {
+	info, err := fsys.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return func() error {
+			if err := fsys.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			return nil
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := fsys.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		return fsys.WriteFile(name, data, info.Mode().Perm())
+	}, nil
+}
+
+type memoryFileInfo struct {
+	name string
+	file *memoryFile
+}
+
+func (i memoryFileInfo) Name() string       { return i.name }
+func (i memoryFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memoryFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memoryFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memoryFileInfo) IsDir() bool        { return false }
+func (i memoryFileInfo) Sys() any           { return nil }
+
+// Verifying interface implementations
+var _ OutputFS = OSOutputFS{}
+var _ OutputFS = (*MemoryOutputFS)(nil)
@@ -0,0 +1,108 @@
+package google
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryOutputFSCommit(t *testing.T) {
+	out := t.TempDir()
+	if err := os.WriteFile(filepath.Join(out, "stale.go"), []byte("stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "kept.go"), []byte("kept\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mem := NewMemoryOutputFS()
+	if err := mem.MkdirAll(filepath.Join(out, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mem.WriteFile(filepath.Join(out, "services", "foo", "added.go"), []byte("added\n"), 0644)
+	mem.WriteFile(filepath.Join(out, "kept.go"), []byte("changed\n"), 0644)
+	if err := mem.Remove(filepath.Join(out, "stale.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := os.ReadFile(filepath.Join(out, "kept.go")); err != nil || string(b) != "kept\n" {
+		t.Fatalf("disk was written before Commit(): %q, %v", b, err)
+	}
+
+	if err := mem.Commit(OSOutputFS{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"services/foo/added.go": "added\n",
+		"kept.go":               "changed\n",
+	} {
+		b, err := os.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			t.Errorf("%s wasn't written: %v", name, err)
+			continue
+		}
+		if string(b) != want {
+			t.Errorf("%s = %q, want %q", name, b, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "stale.go")); !os.IsNotExist(err) {
+		t.Errorf("stale.go wasn't removed: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(out, "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty directory wasn't created: %v", err)
+	}
+}
+
+// failingOutputFS writes to disk, except for the file it fails to write.
+type failingOutputFS struct {
+	OSOutputFS
+	failing string
+}
+
+func (f failingOutputFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if name == f.failing {
+		return errors.New("disk full")
+	}
+	return f.OSOutputFS.WriteFile(name, data, perm)
+}
+
+func TestMemoryOutputFSCommitRollback(t *testing.T) {
+	out := t.TempDir()
+	if err := os.WriteFile(filepath.Join(out, "kept.go"), []byte("kept\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "stale.go"), []byte("stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mem := NewMemoryOutputFS()
+	// Files are written in sorted order, so the failing file is written last.
+	mem.WriteFile(filepath.Join(out, "a", "added.go"), []byte("added\n"), 0644)
+	mem.WriteFile(filepath.Join(out, "kept.go"), []byte("changed\n"), 0644)
+	mem.WriteFile(filepath.Join(out, "z", "failing.go"), []byte("failing\n"), 0644)
+	if err := mem.Remove(filepath.Join(out, "stale.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := mem.Commit(failingOutputFS{failing: filepath.Join(out, "z", "failing.go")})
+	if err == nil {
+		t.Fatal("Commit() succeeded, want an error")
+	}
+
+	for name, want := range map[string]string{
+		"kept.go":  "kept\n",
+		"stale.go": "stale\n",
+	} {
+		if b, err := os.ReadFile(filepath.Join(out, name)); err != nil || string(b) != want {
+			t.Errorf("%s = %q, %v; want %q", name, b, err, want)
+		}
+	}
+	for _, name := range []string{"a", "z"} {
+		if _, err := os.Stat(filepath.Join(out, name)); !os.IsNotExist(err) {
+			t.Errorf("%s wasn't rolled back: %v", name, err)
+		}
+	}
+}
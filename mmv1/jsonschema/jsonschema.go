@@ -0,0 +1,287 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema builds JSON Schemas for the product and resource YAML
+// files under products/ by reflecting over the api structs, so that editors
+// can offer autocompletion and validation while they are being written.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+const (
+	ProductSchemaFile  = "product.schema.json"
+	ResourceSchemaFile = "resource.schema.json"
+)
+
+// Directories, relative to the mmv1 root, holding the structs that YAML files
+// are decoded into. Their doc comments become schema descriptions.
+var sourceDirs = []string{"api", "api/product", "api/resource"}
+
+// enums lists the allowed values of string fields, keyed by
+// "<package>.<struct>.<yaml key>".
+var enums = map[string][]string{
+	"api.Type.type":                                  api.PropertyTypes,
+	"api.Resource.create_verb":                       api.CreateVerbs,
+	"api.Resource.read_verb":                         api.ReadVerbs,
+	"api.Resource.update_verb":                       api.UpdateVerbs,
+	"api.Resource.delete_verb":                       api.DeleteVerbs,
+	"api.Async.type":                                 api.AsyncTypes,
+	"resource.IamPolicy.fetch_iam_policy_verb":       resource.FetchIamPolicyVerbs,
+	"resource.IamPolicy.set_iam_policy_verb":         resource.SetIamPolicyVerbs,
+	"resource.IamPolicy.iam_conditions_request_type": resource.IamConditionsRequestTypes,
+}
+
+// Schema is a JSON Schema (draft-07) document or subschema.
+type Schema map[string]any
+
+// Generator builds schemas for api structs, using the doc comments parsed
+// from the mmv1 sources for descriptions.
+type Generator struct {
+	// Doc comments keyed by "<package>.<struct>" and
+	// "<package>.<struct>.<field>".
+	docs        map[string]string
+	definitions map[string]Schema
+}
+
+// NewGenerator parses the api sources under baseDirectory (the mmv1 root).
+func NewGenerator(baseDirectory string) (*Generator, error) {
+	g := &Generator{docs: map[string]string{}}
+	for _, dir := range sourceDirs {
+		if err := g.parseDocs(filepath.Join(baseDirectory, dir)); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (g *Generator) parseDocs(dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %w", dir, err)
+	}
+
+	for pkgName, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					key := pkgName + "." + ts.Name.Name
+					doc := ts.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					g.docs[key] = docText(doc)
+					for _, field := range st.Fields.List {
+						text := docText(field.Doc)
+						if text == "" {
+							text = docText(field.Comment)
+						}
+						for _, name := range field.Names {
+							g.docs[key+"."+name.Name] = text
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// docText returns the text of a doc comment without its TODO paragraphs.
+func docText(cg *ast.CommentGroup) string {
+	if cg == nil {
+		return ""
+	}
+	var paragraphs []string
+	for _, p := range strings.Split(cg.Text(), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" && !strings.HasPrefix(p, "TODO") {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// Schema returns a standalone schema for YAML documents decoded into values
+// of v's type.
+func (g *Generator) Schema(v any, title string) Schema {
+	g.definitions = map[string]Schema{}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	g.typeSchema(t)
+
+	root := Schema{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   title,
+	}
+	for k, val := range g.definitions[definitionName(t)] {
+		if k != "title" {
+			root[k] = val
+		}
+	}
+	root["definitions"] = g.definitions
+	return root
+}
+
+func (g *Generator) typeSchema(t reflect.Type) Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		// yaml.v3 decodes any scalar into a string.
+		return Schema{"type": []string{"string", "boolean", "integer", "number"}}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": []string{"object", "null"}, "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		name := definitionName(t)
+		if _, ok := g.definitions[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			g.definitions[name] = Schema{}
+			g.definitions[name] = g.structSchema(t)
+		}
+		return Schema{"$ref": "#/definitions/" + name}
+	default:
+		// Fields such as default_value accept any YAML value.
+		return Schema{}
+	}
+}
+
+func (g *Generator) structSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	g.addFields(t, definitionName(t), properties)
+
+	s := Schema{
+		"type":                 []string{"object", "null"},
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if doc := g.docs[definitionName(t)]; doc != "" {
+		s["description"] = doc
+	}
+	return s
+}
+
+// addFields adds the YAML keys of t's fields to properties, following the
+// same rules as gopkg.in/yaml.v3: untagged fields use their lowercased name
+// and ",inline" fields contribute their own keys.
+func (g *Generator) addFields(t reflect.Type, defName string, properties Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",inline,") {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			g.addFields(ft, definitionName(ft), properties)
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+
+		prop := Schema{}
+		for k, v := range g.typeSchema(f.Type) {
+			prop[k] = v
+		}
+		if values, ok := enums[defName+"."+key]; ok {
+			prop["enum"] = values
+		}
+		if doc := g.docs[defName+"."+f.Name]; doc != "" {
+			prop["description"] = doc
+		}
+		// draft-07 ignores keywords next to $ref, so wrap it.
+		if ref, ok := prop["$ref"]; ok && len(prop) > 1 {
+			delete(prop, "$ref")
+			prop["allOf"] = []Schema{{"$ref": ref}}
+		}
+		properties[key] = prop
+	}
+}
+
+// definitionName names a struct type after its package and type name, such
+// as "api.Resource".
+func definitionName(t reflect.Type) string {
+	return filepath.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// WriteSchemas writes the product and resource schemas to outputDirectory.
+func WriteSchemas(baseDirectory, outputDirectory string) error {
+	g, err := NewGenerator(baseDirectory)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDirectory, os.ModePerm); err != nil {
+		return err
+	}
+
+	schemas := []struct {
+		file   string
+		schema Schema
+	}{
+		{ProductSchemaFile, g.Schema(api.Product{}, "MMv1 product")},
+		{ResourceSchemaFile, g.Schema(api.Resource{}, "MMv1 resource")},
+	}
+	for _, s := range schemas {
+		b, err := json.MarshalIndent(s.schema, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(outputDirectory, s.file), append(b, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
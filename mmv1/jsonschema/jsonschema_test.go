@@ -0,0 +1,62 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceSchema(t *testing.T) {
+	g, err := NewGenerator("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := g.Schema(api.Resource{}, "MMv1 resource")
+	properties := s["properties"].(Schema)
+	definitions := s["definitions"].(map[string]Schema)
+
+	if _, ok := properties["source_yaml_file"]; ok {
+		t.Errorf("fields tagged yaml:\"-\" should be omitted")
+	}
+	if _, ok := properties["exclude_tgc"]; !ok {
+		t.Errorf("fields of inline structs should be included")
+	}
+	if got, want := properties["create_verb"].(Schema)["enum"], api.CreateVerbs; !cmp.Equal(got, want) {
+		t.Errorf("create_verb enum = %v, want %v", got, want)
+	}
+	if got := properties["exclude_tgc"].(Schema)["description"]; got == nil || got == "" {
+		t.Errorf("exclude_tgc has no description")
+	}
+
+	typeProperties := definitions["api.Type"]["properties"].(Schema)
+	if got, want := typeProperties["type"].(Schema)["enum"], api.PropertyTypes; !cmp.Equal(got, want) {
+		t.Errorf("type enum = %v, want %v", got, want)
+	}
+	if got, want := typeProperties["properties"].(Schema)["items"], (Schema{"$ref": "#/definitions/api.Type"}); !cmp.Equal(got, want) {
+		t.Errorf("properties items = %v, want %v", got, want)
+	}
+}
+
+func TestDocText(t *testing.T) {
+	g, err := NewGenerator("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.docs["api.Async.Type"], `Describes an operation, one of "OpAsync", "PollAsync"`; got != want {
+		t.Errorf("api.Async.Type doc = %q, want %q", got, want)
+	}
+}
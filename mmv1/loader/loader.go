@@ -460,20 +460,20 @@ func (l *Loader) AddExtraFields() error {
 	return nil
 }
 
+// Validate checks every loaded resource and exits after reporting all errors
+// found, rather than stopping at the first invalid resource.
 func (l *Loader) Validate() {
-	if l.Products == nil {
-		log.Fatalln("products have not been loaded into memory")
+	issues, err := l.ValidationIssues()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(issues) == 0 {
+		return
 	}
 
-	for _, product := range l.Products {
-		for _, resource := range product.Objects {
-			es := resource.Validate()
-			if len(es) > 0 {
-				es = utils.TransformErrs(func(e error) error {
-					return fmt.Errorf("%s%s%s: %w", utils.ColorRed, resource.SourceYamlFile, utils.ColorReset, e)
-				}, es)
-				log.Fatalf("%v", errors.Join(es...))
-			}
-		}
+	var es []error
+	for _, issue := range issues {
+		es = append(es, fmt.Errorf("%s%s:%d:%d%s: %s", utils.ColorRed, issue.File, issue.Line, issue.Column, utils.ColorReset, issue.Message))
 	}
+	log.Fatalf("%v\n%d validation error(s) found", errors.Join(es...), len(issues))
 }
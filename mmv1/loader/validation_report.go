@@ -0,0 +1,228 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ReportFormatText  = "text"
+	ReportFormatJSON  = "json"
+	ReportFormatSARIF = "sarif"
+)
+
+// ValidationIssue is a single validation error found in a resource YAML file.
+// File is relative to the base or override directory the resource was loaded
+// from; Line and Column are 1-based and point at the most specific YAML node
+// that could be matched to the error.
+type ValidationIssue struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Product  string   `json:"product"`
+	Resource string   `json:"resource"`
+	Path     []string `json:"path,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// Some keys are rewritten while loading, so errors reported against the
+// in-memory key may belong to a different key in the source file.
+var yamlKeyAliases = map[string][]string{
+	"samples": {"examples"},
+}
+
+// ValidationIssues validates every loaded resource and returns all errors
+// found, sorted by file and position.
+func (l *Loader) ValidationIssues() ([]ValidationIssue, error) {
+	if l.Products == nil {
+		return nil, fmt.Errorf("products have not been loaded into memory")
+	}
+
+	var issues []ValidationIssue
+	for _, product := range l.Products {
+		for _, resource := range product.Objects {
+			es := resource.Validate()
+			if len(es) == 0 {
+				continue
+			}
+
+			root, err := l.parseSourceYaml(resource.SourceYamlFile)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range es {
+				path := utils.YamlPath(e)
+				line, column := locateYamlPath(root, path)
+				issues = append(issues, ValidationIssue{
+					File:     resource.SourceYamlFile,
+					Line:     line,
+					Column:   column,
+					Product:  product.Name,
+					Resource: resource.Name,
+					Path:     path,
+					Message:  e.Error(),
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b ValidationIssue) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return issues, nil
+}
+
+// parseSourceYaml reads a resource file, preferring the override directory
+// when the file only exists there.
+func (l *Loader) parseSourceYaml(relPath string) (*yaml.Node, error) {
+	path := filepath.Join(l.baseDirectory, relPath)
+	if !Exists(path) && l.overrideDirectory != "" {
+		path = filepath.Join(l.overrideDirectory, relPath)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return &doc, nil
+}
+
+// locateYamlPath walks path from the document root and returns the position
+// of the deepest node it could reach.
+func locateYamlPath(doc *yaml.Node, path []string) (int, int) {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line, column := node.Line, node.Column
+
+	for _, segment := range path {
+		next := findYamlChild(node, segment)
+		if next == nil {
+			break
+		}
+		node = next
+		line, column = node.Line, node.Column
+	}
+	return line, column
+}
+
+// findYamlChild returns the value for key in a mapping node (returning the key
+// node itself for scalar values, so the position points at the key), or the
+// entry whose `name` equals key in a sequence node.
+func findYamlChild(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for _, k := range append([]string{key}, yamlKeyAliases[key]...) {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value != k {
+					continue
+				}
+				if node.Content[i+1].Kind == yaml.ScalarNode {
+					return node.Content[i]
+				}
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		for _, entry := range node.Content {
+			if entry.Kind != yaml.MappingNode {
+				if entry.Value == key {
+					return entry
+				}
+				continue
+			}
+			for i := 0; i+1 < len(entry.Content); i += 2 {
+				if entry.Content[i].Value == "name" && entry.Content[i+1].Value == key {
+					return entry
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteValidationReport writes issues to w in the given format.
+func WriteValidationReport(w io.Writer, format string, issues []ValidationIssue) error {
+	switch format {
+	case "", ReportFormatText:
+		for _, issue := range issues {
+			if _, err := fmt.Fprintln(w, issue.String()); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%d validation error(s) found\n", len(issues))
+		return err
+	case ReportFormatJSON:
+		if issues == nil {
+			issues = []ValidationIssue{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issues)
+	case ReportFormatSARIF:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sarifReport(issues))
+	default:
+		return fmt.Errorf("unknown report format %q, expected one of %q", format, []string{ReportFormatText, ReportFormatJSON, ReportFormatSARIF})
+	}
+}
+
+// sarifReport builds a minimal SARIF 2.1.0 log for issues.
+func sarifReport(issues []ValidationIssue) map[string]any {
+	results := []map[string]any{}
+	for _, issue := range issues {
+		results = append(results, map[string]any{
+			"ruleId":  "mmv1-validate",
+			"level":   "error",
+			"message": map[string]any{"text": issue.Message},
+			"locations": []map[string]any{{
+				"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": filepath.ToSlash(issue.File)},
+					"region":           map[string]any{"startLine": issue.Line, "startColumn": issue.Column},
+				},
+			}},
+		})
+	}
+
+	return map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{{
+			"tool": map[string]any{
+				"driver": map[string]any{
+					"name":           "mmv1",
+					"informationUri": "https://github.com/GoogleCloudPlatform/magic-modules",
+					"rules": []map[string]any{{
+						"id":               "mmv1-validate",
+						"shortDescription": map[string]any{"text": "MMv1 resource YAML validation"},
+					}},
+				},
+			},
+			"results": results,
+		}},
+	}
+}
@@ -0,0 +1,108 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+const testResourceYaml = `name: 'Widget'
+create_verb: 'GET'
+examples:
+  - name: 'widget_basic'
+properties:
+  - name: 'config'
+    type: NestedObject
+    properties:
+      - name: 'size'
+        type: Integr
+  - name: 'tags'
+    type: Array
+    item_type:
+      type: Strng
+`
+
+func TestLocateYamlPath(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(testResourceYaml), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		path        []string
+		line        int
+		column      int
+	}{
+		{"no path points at the document", nil, 1, 1},
+		{"top-level scalar points at the key", []string{"create_verb"}, 2, 1},
+		{"nested property", []string{"properties", "config", "properties", "size", "type"}, 10, 9},
+		{"item type", []string{"properties", "tags", "item_type", "type"}, 14, 7},
+		{"samples fall back to examples", []string{"samples", "widget_basic"}, 4, 5},
+		{"unknown segments stop at the deepest match", []string{"properties", "config", "properties", "missing"}, 9, 7},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			line, column := locateYamlPath(&doc, tc.path)
+			if line != tc.line || column != tc.column {
+				t.Errorf("locateYamlPath(%q) = %d:%d, want %d:%d", tc.path, line, column, tc.line, tc.column)
+			}
+		})
+	}
+}
+
+func TestWriteValidationReport(t *testing.T) {
+	issues := []ValidationIssue{{File: "products/foo/Widget.yaml", Line: 2, Column: 1, Product: "Foo", Resource: "Widget", Message: "bad verb"}}
+
+	var text bytes.Buffer
+	if err := WriteValidationReport(&text, ReportFormatText, issues); err != nil {
+		t.Fatal(err)
+	}
+	if want := "products/foo/Widget.yaml:2:1: bad verb\n1 validation error(s) found\n"; text.String() != want {
+		t.Errorf("text report = %q, want %q", text.String(), want)
+	}
+
+	var js bytes.Buffer
+	if err := WriteValidationReport(&js, ReportFormatJSON, issues); err != nil {
+		t.Fatal(err)
+	}
+	var got []ValidationIssue
+	if err := json.Unmarshal(js.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(issues, got); diff != "" {
+		t.Errorf("json report round trip mismatch (-want +got):\n%s", diff)
+	}
+
+	var sarif bytes.Buffer
+	if err := WriteValidationReport(&sarif, ReportFormatSARIF, issues); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ Uri string }
+						Region           struct{ StartLine, StartColumn int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	loc := log.Runs[0].Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.Uri != "products/foo/Widget.yaml" || loc.Region.StartLine != 2 || loc.Region.StartColumn != 1 {
+		t.Errorf("unexpected sarif location %+v", loc)
+	}
+
+	if err := WriteValidationReport(&bytes.Buffer{}, "xml", issues); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -14,13 +17,13 @@ import (
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/jsonschema"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/loader"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/openapi_generate"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/provider"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/yamlfmt"
 )
 
-var wg sync.WaitGroup
-
 // TODO rewrite: additional flags
 
 // Example usage: --output $GOPATH/src/github.com/terraform-providers/terraform-provider-google-beta
@@ -37,6 +40,9 @@ var productFlag = flag.String("product", "", "optional product name. If specifie
 
 var resourceFlag = flag.String("resource", "", "optional resource name. Limits generation to the specified resource within a particular product.")
 
+// Example usage: --jobs 4
+var jobsFlag = flag.Int("jobs", runtime.NumCPU(), "maximum number of products to generate concurrently")
+
 var doNotGenerateCode = flag.Bool("no-code", false, "do not generate code")
 
 var doNotGenerateDocs = flag.Bool("no-docs", false, "do not generate docs")
@@ -44,6 +50,22 @@ var doNotGenerateDocs = flag.Bool("no-docs", false, "do not generate docs")
 var providerFlag = flag.String("provider", "", "optional provider name. If specified, a non-default provider will be used.")
 
 var openapiGenerate = flag.Bool("openapi-generate", false, "Generate MMv1 YAML from openapi directory (Experimental)")
+var discoveryGenerate = flag.String("discovery-generate", "", "Generate MMv1 YAML from the Google Discovery document at this path (Experimental)")
+
+var dryRunFlag = flag.Bool("dry-run", false, "generate into memory and print how the files under --output would change instead of writing them")
+
+// Example usage: --dry-run --diff-format json
+var diffFormatFlag = flag.String("diff-format", google.DiffFormatUnified, "output format for --dry-run: unified (a patch) or json (a list of added, modified and deleted files)")
+
+var incrementalFlag = flag.Bool("incremental", false, "skip resources whose inputs haven't changed since the last incremental run into --output, and remove files of resources that no longer exist")
+
+// Example usage: --json-schema ../.vscode/schemas
+var jsonSchemaFlag = flag.String("json-schema", "", "write JSON Schemas for product and resource YAML files to this directory and exit")
+
+var validateFlag = flag.Bool("validate", false, "validate all products and resources, report every error found and exit without generating")
+
+// Example usage: --validate --validate-format sarif > mmv1.sarif
+var validateFormatFlag = flag.String("validate-format", loader.ReportFormatText, "output format for --validate: text, json or sarif")
 
 func main() {
 
@@ -56,15 +78,113 @@ func main() {
 		return
 	}
 
+	if *discoveryGenerate != "" {
+		parser := openapi_generate.NewOpenapiParser("openapi_generate/openapi", "products")
+		parser.WriteDiscoveryYaml(*discoveryGenerate)
+		return
+	}
+
+	// Example usage: mmv1 coverage --spec openapi_generate/openapi/firebaseremoteconfig_prod_public_openapi3_0_v1.json products/firebaseremoteconfig
+	if flag.Arg(0) == "coverage" {
+		if !ReportCoverage(flag.Args()[1:]) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Example usage: mmv1 fmt --check products/pubsub
+	if flag.Arg(0) == "fmt" {
+		if !FormatYaml(flag.Args()[1:]) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *jsonSchemaFlag != "" {
+		baseDirectory := *baseDirectoryFlag
+		if baseDirectory == "" {
+			var err error
+			if baseDirectory, err = os.Getwd(); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := jsonschema.WriteSchemas(baseDirectory, *jsonSchemaFlag); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Wrote JSON Schemas to %q", *jsonSchemaFlag)
+		return
+	}
+
+	if *validateFlag {
+		if !ValidateProducts(*providerFlag, *versionFlag, *baseDirectoryFlag, *overrideDirectoryFlag, *validateFormatFlag) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *outputPathFlag == "" {
 		log.Printf("No output path specified, exiting")
 		return
 	}
 
-	GenerateProducts(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag, *baseDirectoryFlag, *overrideDirectoryFlag, !*doNotGenerateCode, !*doNotGenerateDocs)
+	// Everything is generated into memory first, and only written to
+	// --output once every product succeeded, so that a failure never leaves
+	// the output half-written.
+	stagedFS := google.NewMemoryOutputFS()
+	provider.SetOutputFS(stagedFS)
+
+	if *incrementalFlag {
+		if err := EnableIncrementalGeneration(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	failures := GenerateProducts(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag, *baseDirectoryFlag, *overrideDirectoryFlag, *jobsFlag, !*doNotGenerateCode, !*doNotGenerateDocs)
+	if len(failures) > 0 {
+		log.Printf("Generation failed for %d product(s), nothing was written to %q:", len(failures), *outputPathFlag)
+		for _, f := range failures {
+			log.Printf("  %s: %v", f.Product, f.Err)
+		}
+		os.Exit(1)
+	}
+
+	if *incrementalFlag {
+		if err := provider.FinishIncrementalGeneration(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *dryRunFlag {
+		// Deletions can only be detected when every generated file was regenerated.
+		// Incremental runs report them through the manifest instead.
+		detectDeletions := *productFlag == "" && *resourceFlag == "" && !*incrementalFlag
+		changes, err := google.ComputeOutputChanges(stagedFS, *outputPathFlag, detectDeletions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := google.WriteOutputChanges(os.Stdout, *diffFormatFlag, changes); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Dry run: %d file(s) would change in %q", len(changes), *outputPathFlag)
+		return
+	}
+
+	if err := stagedFS.Commit(google.OSOutputFS{}); err != nil {
+		log.Fatalf("error writing generated files to %q: %v", *outputPathFlag, err)
+	}
+}
+
+// ProductFailure is the error that stopped the generation of a product, or of
+// the files shared by every product.
+type ProductFailure struct {
+	Product string
+	Err     error
 }
 
-func GenerateProducts(product, resource, providerName, version, outputPath, baseDirectory, overrideDirectory string, generateCode, generateDocs bool) {
+// GenerateProducts generates every product, at most jobs of them at a time,
+// followed by the files shared by every product. It returns the products that
+// failed, sorted by name.
+func GenerateProducts(product, resource, providerName, version, outputPath, baseDirectory, overrideDirectory string, jobs int, generateCode, generateDocs bool) []ProductFailure {
 	if version == "" {
 		log.Printf("No version specified, assuming ga")
 		version = "ga"
@@ -108,10 +228,33 @@ func GenerateProducts(product, resource, providerName, version, outputPath, base
 		}
 	}
 
-	for _, productApi := range loadedProducts {
+	var (
+		mu       sync.Mutex
+		failures []ProductFailure
+	)
+	fail := func(product string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, ProductFailure{Product: product, Err: err})
+	}
+
+	queue := make(chan *api.Product)
+	var wg sync.WaitGroup
+	for range max(jobs, 1) {
 		wg.Add(1)
-		go GenerateProduct(version, providerName, productApi, outputPath, startTime, wrappedFS, productsToGenerate, resource, generateCode, generateDocs)
+		go func() {
+			defer wg.Done()
+			for productApi := range queue {
+				if err := GenerateProduct(version, providerName, productApi, outputPath, startTime, wrappedFS, productsToGenerate, resource, generateCode, generateDocs); err != nil {
+					fail(productApi.PackagePath, err)
+				}
+			}
+		}()
+	}
+	for _, productApi := range loadedProducts {
+		queue <- productApi
 	}
+	close(queue)
 	wg.Wait()
 
 	var productsForVersion []*api.Product
@@ -125,35 +268,218 @@ func GenerateProducts(product, resource, providerName, version, outputPath, base
 	// In order to only copy/compile files once per provider this must be called outside
 	// of the products loop. Create an MMv1 provider with a nil product to trigger shared file behavior.
 	providerToGenerate := newProvider(providerName, version, nil, startTime, wrappedFS)
-	providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs)
-
-	if generateCode {
-		providerToGenerate.CompileCommonFiles(outputPath, productsForVersion, "")
+	if err := providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs); err != nil {
+		fail("common files", err)
+	} else if generateCode {
+		if err := providerToGenerate.CompileCommonFiles(outputPath, productsForVersion, ""); err != nil {
+			fail("common files", err)
+		}
 	}
 
+	slices.SortFunc(failures, func(f1, f2 ProductFailure) int {
+		return strings.Compare(f1.Product, f2.Product)
+	})
+
 	log.Printf("Done MM generation.")
+	return failures
+}
+
+// FormatYaml implements the fmt subcommand, rewriting the product and
+// resource YAML files under the given paths (products/ by default) into their
+// canonical form. With --check it only lists the files that aren't formatted.
+// It returns false if any file couldn't be formatted, or isn't formatted in
+// check mode.
+func FormatYaml(args []string) bool {
+	fmtFlags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	check := fmtFlags.Bool("check", false, "list files that aren't formatted instead of rewriting them, and fail if there are any")
+	fmtFlags.Parse(args)
+
+	paths := fmtFlags.Args()
+	if len(paths) == 0 {
+		paths = []string{"products"}
+	}
+
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".yaml" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Cannot list %s: %v", p, err)
+			return false
+		}
+	}
+
+	ok := true
+	unformatted := 0
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			log.Printf("Cannot read %s: %v", file, err)
+			ok = false
+			continue
+		}
+		formatted, err := yamlfmt.Format(file, content)
+		if err != nil {
+			log.Printf("Cannot format %s: %v", file, err)
+			ok = false
+			continue
+		}
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+
+		unformatted++
+		fmt.Println(file)
+		if *check {
+			continue
+		}
+		if err := os.WriteFile(file, formatted, 0644); err != nil {
+			log.Printf("Cannot write %s: %v", file, err)
+			ok = false
+		}
+	}
+
+	if *check && unformatted > 0 {
+		log.Printf("%d of %d file(s) aren't formatted, run `mmv1 fmt` to fix them", unformatted, len(files))
+		return false
+	}
+	return ok
+}
+
+// ReportCoverage implements the coverage subcommand, comparing the fields of
+// the resources of a product with the OpenAPI spec or Discovery document given
+// by --spec. It returns false if the spec or the product couldn't be loaded.
+func ReportCoverage(args []string) bool {
+	coverageFlags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	spec := coverageFlags.String("spec", "", "path to the OpenAPI spec or Google Discovery document of the product's API")
+	version := coverageFlags.String("version", "beta", "version of the product and its fields to compare")
+	format := coverageFlags.String("format", openapi_generate.CoverageFormatText, "output format: text or json")
+	coverageFlags.Parse(args)
+
+	if *spec == "" || coverageFlags.NArg() != 1 {
+		log.Printf("Usage: mmv1 coverage --spec <path> [--version beta] [--format text|json] <product directory>")
+		return false
+	}
+
+	doc, err := openapi_generate.LoadDocument(*spec)
+	if err != nil {
+		log.Printf("Cannot load %s: %v", *spec, err)
+		return false
+	}
+
+	baseDirectory, err := os.Getwd()
+	if err != nil {
+		log.Printf("Cannot find the base directory: %v", err)
+		return false
+	}
+	ofs, err := google.NewOverlayFS("", baseDirectory)
+	if err != nil {
+		log.Printf("Cannot read %s: %v", baseDirectory, err)
+		return false
+	}
+	l := loader.NewLoader(loader.Config{Version: *version, BaseDirectory: baseDirectory, Sysfs: loader.NewVarsReplacingFS(ofs)})
+	productApi, err := l.LoadProduct(filepath.Clean(coverageFlags.Arg(0)))
+	if err != nil {
+		log.Printf("Cannot load %s: %v", coverageFlags.Arg(0), err)
+		return false
+	}
+
+	if err := openapi_generate.WriteCoverageReport(os.Stdout, *format, openapi_generate.ProductCoverage(productApi, doc)); err != nil {
+		log.Printf("Cannot write the coverage report: %v", err)
+		return false
+	}
+	return true
+}
+
+// EnableIncrementalGeneration loads the manifest of the previous incremental
+// run into outputPath. Resources that aren't seen during this run are only
+// treated as removed if their whole product is being generated.
+func EnableIncrementalGeneration(product, resource, providerName, version, outputPath string) error {
+	if providerName == "" {
+		providerName = "terraform"
+	}
+	if version == "" {
+		version = "ga"
+	}
+
+	var products []string
+	for _, prod := range strings.Split(product, ",") {
+		if prod = strings.TrimSpace(prod); prod != "" {
+			products = append(products, fmt.Sprintf("products/%s", prod))
+		}
+	}
+	inScope := func(productPath string) bool {
+		if resource != "" {
+			return false
+		}
+		return len(products) == 0 || slices.Contains(products, productPath)
+	}
+
+	return provider.EnableIncrementalGeneration(outputPath, providerName, version, inScope)
+}
+
+// ValidateProducts loads every product and writes a report of all validation
+// errors to stdout. It returns false if any error was found.
+func ValidateProducts(providerName, version, baseDirectory, overrideDirectory, format string) bool {
+	if version == "" {
+		version = "ga"
+	}
+	if baseDirectory == "" {
+		var err error
+		if baseDirectory, err = os.Getwd(); err != nil {
+			panic(err)
+		}
+	}
+
+	ofs, err := google.NewOverlayFS(overrideDirectory, baseDirectory)
+	if err != nil {
+		panic(err)
+	}
+
+	l := loader.NewLoader(loader.Config{Version: version, BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Sysfs: loader.NewVarsReplacingFS(ofs), CompilerTarget: providerName})
+	l.LoadProducts()
+	l.AddExtraFields()
+
+	issues, err := l.ValidationIssues()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := loader.WriteValidationReport(os.Stdout, format, issues); err != nil {
+		log.Fatal(err)
+	}
+	return len(issues) == 0
 }
 
 // GenerateProduct generates code and documentation for a product
 // This now uses the CompileProduct method to separate compilation from generation
 func GenerateProduct(version, providerName string, productApi *api.Product, outputPath string,
 	startTime time.Time, fsys fs.FS, productsToGenerate []string, resourceToGenerate string,
-	generateCode, generateDocs bool) {
-	defer wg.Done()
-
+	generateCode, generateDocs bool) error {
 	if !slices.Contains(productsToGenerate, productApi.PackagePath) {
 		log.Printf("%s not specified, skipping generation", productApi.PackagePath)
-		return
+		return nil
 	}
 
 	log.Printf("%s: Generating files", productApi.PackagePath)
 	providerToGenerate := newProvider(providerName, version, productApi, startTime, fsys)
-	providerToGenerate.Generate(outputPath, resourceToGenerate, generateCode, generateDocs)
+	if err := providerToGenerate.Generate(outputPath, resourceToGenerate, generateCode, generateDocs); err != nil {
+		return err
+	}
 
-	providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs)
+	if err := providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs); err != nil {
+		return err
+	}
 	if generateCode {
-		providerToGenerate.CompileCommonFiles(outputPath, []*api.Product{productApi}, "")
+		return providerToGenerate.CompileCommonFiles(outputPath, []*api.Product{productApi}, "")
 	}
+	return nil
 }
 
 func newProvider(providerName, version string, productApi *api.Product, startTime time.Time, fsys fs.FS) provider.Provider {
@@ -166,6 +492,8 @@ func newProvider(providerName, version string, productApi *api.Product, startTim
 		return provider.NewTerraformGoogleConversionNext(productApi, version, startTime, fsys)
 	case "oics":
 		return provider.NewTerraformOiCS(productApi, version, startTime, fsys)
+	case "fake":
+		return provider.NewTerraformFake(productApi, version, startTime, fsys)
 	default:
 		return provider.NewTerraform(productApi, version, startTime, fsys)
 	}
@@ -0,0 +1,349 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_generate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	CoverageFormatText = "text"
+	CoverageFormatJSON = "json"
+)
+
+// ResourceCoverage compares the fields of a resource YAML file with the
+// fields of the matching resource in an API spec. Fields are identified by
+// their dot separated API names, e.g. "config.nodeCount".
+type ResourceCoverage struct {
+	Product  string `json:"product"`
+	Resource string `json:"resource"`
+	// The name of the resource in the API spec, empty if none matched.
+	ApiResource string `json:"api_resource"`
+	// The number of fields in the API spec, and how many of them are in the
+	// YAML file.
+	ApiFields     int `json:"api_fields"`
+	CoveredFields int `json:"covered_fields"`
+	// Fields in the API spec that aren't in the YAML file.
+	Missing []string `json:"missing,omitempty"`
+	// Fields in the YAML file that aren't in the API spec.
+	Removed    []string        `json:"removed,omitempty"`
+	Mismatches []FieldMismatch `json:"mismatches,omitempty"`
+}
+
+// FieldMismatch is a field whose type or enum values differ between the YAML
+// file and the API spec.
+type FieldMismatch struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Percent returns the share of API fields covered by the YAML file.
+func (c ResourceCoverage) Percent() float64 {
+	if c.ApiFields == 0 {
+		return 100
+	}
+	return 100 * float64(c.CoveredFields) / float64(c.ApiFields)
+}
+
+// LoadDocument reads an OpenAPI spec or a Google Discovery document.
+func LoadDocument(filePath string) (*openapi3.T, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe struct {
+		DiscoveryVersion string `json:"discoveryVersion"`
+	}
+	if json.Unmarshal(b, &probe) == nil && probe.DiscoveryVersion != "" {
+		var discovery discoveryDocument
+		if err := json.Unmarshal(b, &discovery); err != nil {
+			return nil, fmt.Errorf("error parsing discovery document %s: %w", filePath, err)
+		}
+		return discoveryToOpenapi(&discovery), nil
+	}
+
+	ctx := context.Background()
+	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+	doc, err := loader.LoadFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI spec %s: %w", filePath, err)
+	}
+	return doc, nil
+}
+
+// ProductCoverage reports, for every resource of product, how its fields
+// compare to the resource managed by the same URL in doc.
+func ProductCoverage(product *api.Product, doc *openapi3.T) []ResourceCoverage {
+	type apiResource struct {
+		name   string
+		schema *openapi3.Schema
+	}
+	byUrl := map[string]apiResource{}
+	byName := map[string]apiResource{}
+	resources := findResources(doc)
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		in := resources[name]
+		var path string
+		var op *openapi3.Operation
+		switch {
+		case in.create != nil:
+			path = in.create.path
+			op = doc.Paths.Find(path).Post
+		case in.update != nil:
+			path = in.update.path
+			op = doc.Paths.Find(path).Patch
+			if in.update.verb == "PUT" {
+				op = doc.Paths.Find(path).Put
+			}
+		default:
+			continue
+		}
+		if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+			continue
+		}
+		media := op.RequestBody.Value.Content.Get("application/json")
+		if media == nil || media.Schema == nil || media.Schema.Value == nil {
+			continue
+		}
+		r := apiResource{name: name, schema: media.Schema.Value}
+		byUrl[normalizeUrl(baseUrl(path))] = r
+		byName[name] = r
+	}
+
+	var report []ResourceCoverage
+	for _, res := range product.Objects {
+		c := ResourceCoverage{
+			Product:  product.Name,
+			Resource: res.Name,
+		}
+		// Singletons are created at their self_link rather than their base_url
+		match, ok := byUrl[normalizeUrl(res.BaseUrl)]
+		if !ok {
+			match, ok = byUrl[normalizeUrl(res.SelfLink)]
+		}
+		if !ok {
+			match, ok = byName[res.Name]
+		}
+		if !ok && res.ApiResourceTypeKind != "" {
+			match, ok = byName[res.ApiResourceTypeKind]
+		}
+		if ok {
+			c.ApiResource = match.name
+			compareFields(&c, "", res.Properties, match.schema, map[*openapi3.Schema]bool{})
+		}
+		report = append(report, c)
+	}
+	return report
+}
+
+var urlParam = regexp.MustCompile(`\{\{\w+\}\}`)
+
+// normalizeUrl drops parameter names and any leading version from url, so that
+// URLs match however their parameters are named.
+func normalizeUrl(url string) string {
+	url = stripVersion("/" + strings.TrimPrefix(url, "/"))
+	url = strings.Split(url, "?")[0]
+	return strings.Trim(urlParam.ReplaceAllString(url, "{}"), "/")
+}
+
+// compareFields records how the YAML fields props compare to the properties of
+// schema, recursing into nested objects present in both.
+func compareFields(c *ResourceCoverage, prefix string, props []*api.Type, schema *openapi3.Schema, seen map[*openapi3.Schema]bool) {
+	if len(schema.AllOf) > 0 && schema.AllOf[0].Value != nil {
+		schema = schema.AllOf[0].Value
+	}
+	if seen[schema] {
+		return
+	}
+	seen[schema] = true
+	defer delete(seen, schema)
+
+	yamlFields := map[string]*api.Type{}
+	for _, p := range props {
+		if p.UrlParamOnly || p.ClientSide {
+			continue
+		}
+		yamlFields[yamlApiName(p)] = p
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		ref := schema.Properties[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		field := prefix + name
+		c.ApiFields++
+		p, ok := yamlFields[name]
+		if !ok {
+			c.Missing = append(c.Missing, field)
+			continue
+		}
+		delete(yamlFields, name)
+		c.CoveredFields++
+		if p.Exclude {
+			continue
+		}
+
+		apiType := WriteObject(name, ref, propType(ref), false, map[string]bool{}, map[*openapi3.Schema]bool{})
+		if apiType.Type == "" || apiType.CustomExpand != "" {
+			// Recursive fields are represented as JSON strings
+			continue
+		}
+		// Strings holding 64 bit integers or times may be left as Strings in YAML
+		yamlType, specType := coverageType(p.Type), coverageType(apiType.Type)
+		if typ := propType(ref); yamlType == "String" && typ.Is("string") {
+			specType = yamlType
+		}
+		if yamlType != specType {
+			c.Mismatches = append(c.Mismatches, FieldMismatch{
+				Field:   field,
+				Message: fmt.Sprintf("type is %s in YAML and %s in the API", p.Type, apiType.Type),
+			})
+			continue
+		}
+		if p.Type == "Enum" {
+			compareEnums(c, field, p.EnumValues, ref.Value.Enum)
+		}
+
+		value := ref.Value
+		switch apiType.Type {
+		case "NestedObject":
+			compareFields(c, field+".", p.Properties, value, seen)
+		case "Array":
+			if p.ItemType != nil && p.ItemType.Type == "NestedObject" && value.Items != nil && value.Items.Value != nil {
+				compareFields(c, field+".", p.ItemType.Properties, value.Items.Value, seen)
+			}
+		case "Map":
+			if p.ValueType != nil && value.AdditionalProperties.Schema != nil && value.AdditionalProperties.Schema.Value != nil {
+				compareFields(c, field+".", p.ValueType.Properties, value.AdditionalProperties.Schema.Value, seen)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(yamlFields)) {
+		if yamlFields[name].Exclude {
+			continue
+		}
+		c.Removed = append(c.Removed, prefix+name)
+	}
+}
+
+func compareEnums(c *ResourceCoverage, field string, yamlValues []string, specValues []any) {
+	var apiValues []string
+	for _, v := range specValues {
+		value := fmt.Sprintf("%v", v)
+		if !strings.HasSuffix(value, "_UNSPECIFIED") {
+			apiValues = append(apiValues, value)
+		}
+	}
+	if len(apiValues) == 0 {
+		return
+	}
+
+	var missing, removed []string
+	for _, v := range apiValues {
+		if !slices.Contains(yamlValues, v) {
+			missing = append(missing, v)
+		}
+	}
+	for _, v := range yamlValues {
+		if !slices.Contains(apiValues, v) {
+			removed = append(removed, v)
+		}
+	}
+	if len(missing) > 0 {
+		c.Mismatches = append(c.Mismatches, FieldMismatch{
+			Field:   field,
+			Message: fmt.Sprintf("enum values %s are missing from YAML", strings.Join(missing, ", ")),
+		})
+	}
+	if len(removed) > 0 {
+		c.Mismatches = append(c.Mismatches, FieldMismatch{
+			Field:   field,
+			Message: fmt.Sprintf("enum values %s aren't in the API", strings.Join(removed, ", ")),
+		})
+	}
+}
+
+func yamlApiName(t *api.Type) string {
+	if t.ApiName != "" {
+		return t.ApiName
+	}
+	return t.Name
+}
+
+// coverageType groups MMv1 types that are sent to the API the same way, so that
+// e.g. a String in the API matches an Enum or a ResourceRef in YAML.
+func coverageType(t string) string {
+	switch t {
+	case "Enum", "ResourceRef", "Fingerprint", "Time":
+		return "String"
+	case "KeyValuePairs", "KeyValueLabels", "KeyValueAnnotations", "KeyValueTerraformLabels", "KeyValueEffectiveLabels":
+		return "KeyValuePairs"
+	}
+	return t
+}
+
+// WriteCoverageReport writes report to w in the given format.
+func WriteCoverageReport(w io.Writer, format string, report []ResourceCoverage) error {
+	switch format {
+	case "", CoverageFormatText:
+		var b bytes.Buffer
+		apiFields, covered := 0, 0
+		for _, c := range report {
+			if c.ApiResource == "" {
+				fmt.Fprintf(&b, "%s/%s: no matching resource in the API spec\n", c.Product, c.Resource)
+				continue
+			}
+			apiFields += c.ApiFields
+			covered += c.CoveredFields
+			fmt.Fprintf(&b, "%s/%s: %d of %d API fields (%.1f%%)\n", c.Product, c.Resource, c.CoveredFields, c.ApiFields, c.Percent())
+			for _, f := range c.Missing {
+				fmt.Fprintf(&b, "  missing: %s\n", f)
+			}
+			for _, f := range c.Removed {
+				fmt.Fprintf(&b, "  not in API: %s\n", f)
+			}
+			for _, m := range c.Mismatches {
+				fmt.Fprintf(&b, "  mismatch: %s: %s\n", m.Field, m.Message)
+			}
+		}
+		total := ResourceCoverage{ApiFields: apiFields, CoveredFields: covered}
+		fmt.Fprintf(&b, "%d of %d API fields covered (%.1f%%)\n", covered, apiFields, total.Percent())
+		_, err := w.Write(b.Bytes())
+		return err
+	case CoverageFormatJSON:
+		if report == nil {
+			report = []ResourceCoverage{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		return fmt.Errorf("unknown report format %q, expected one of %q", format, []string{CoverageFormatText, CoverageFormatJSON})
+	}
+}
@@ -0,0 +1,93 @@
+package openapi_generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProductCoverage(t *testing.T) {
+	var discovery discoveryDocument
+	if err := json.Unmarshal(testDiscoveryData, &discovery); err != nil {
+		t.Fatalf("Could not load data %s", err)
+	}
+	doc := discoveryToOpenapi(&discovery)
+
+	product := &api.Product{
+		Name: "PetStore",
+		Objects: []*api.Resource{
+			{
+				Name:    "Shelter",
+				BaseUrl: "projects/{{project}}/locations/{{location}}/shelters",
+				Properties: []*api.Type{
+					{Name: "location", Type: "String", UrlParamOnly: true},
+					{Name: "displayName", Type: "String"},
+					{Name: "capacity", Type: "String"},
+					{Name: "region", Type: "Integer"},
+					{Name: "state", Type: "Enum", EnumValues: []string{"OPEN", "DELETED"}},
+					{Name: "labels", Type: "KeyValueLabels"},
+					{Name: "createTime", Type: "Time", Exclude: true},
+					{Name: "oldField", Type: "String"},
+					{Name: "shelter", ApiName: "parentShelter", Type: "NestedObject", Properties: []*api.Type{
+						{Name: "displayName", Type: "String"},
+					}},
+				},
+			},
+			{
+				Name:    "Kennel",
+				BaseUrl: "projects/{{project}}/kennels",
+			},
+		},
+	}
+
+	got := ProductCoverage(product, doc)
+	want := []ResourceCoverage{
+		{
+			Product:       "PetStore",
+			Resource:      "Shelter",
+			ApiResource:   "Shelter",
+			ApiFields:     8,
+			CoveredFields: 7,
+			// parentShelter is recursive, so its fields aren't compared
+			Missing: []string{"name"},
+			Removed: []string{"oldField"},
+			Mismatches: []FieldMismatch{
+				{Field: "region", Message: "type is Integer in YAML and String in the API"},
+				{Field: "state", Message: "enum values CLOSED are missing from YAML"},
+				{Field: "state", Message: "enum values DELETED aren't in the API"},
+			},
+		},
+		{
+			Product:  "PetStore",
+			Resource: "Kennel",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProductCoverage() returned unexpected report (-want +got):\n%s", diff)
+	}
+
+	var text bytes.Buffer
+	if err := WriteCoverageReport(&text, CoverageFormatText, got); err != nil {
+		t.Fatalf("WriteCoverageReport() returned error: %v", err)
+	}
+	for _, line := range []string{
+		"PetStore/Shelter: 7 of 8 API fields (87.5%)",
+		"  missing: name",
+		"  not in API: oldField",
+		"  mismatch: region: type is Integer in YAML and String in the API",
+		"PetStore/Kennel: no matching resource in the API spec",
+		"7 of 8 API fields covered (87.5%)",
+	} {
+		if !strings.Contains(text.String(), line+"\n") {
+			t.Errorf("Expected text report to contain %q, found:\n%s", line, text.String())
+		}
+	}
+
+	if err := WriteCoverageReport(&text, "xml", got); err == nil {
+		t.Error("Expected WriteCoverageReport() to reject unknown formats")
+	}
+}
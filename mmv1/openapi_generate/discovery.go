@@ -0,0 +1,288 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_generate
+
+import (
+	"encoding/json"
+	"log"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// A Google API Discovery document, limited to the fields used to generate
+// MMv1 YAML. See https://developers.google.com/discovery/v1/reference/apis.
+type discoveryDocument struct {
+	Name        string                        `json:"name"`
+	Version     string                        `json:"version"`
+	Title       string                        `json:"title"`
+	RootUrl     string                        `json:"rootUrl"`
+	ServicePath string                        `json:"servicePath"`
+	Schemas     map[string]*discoverySchema   `json:"schemas"`
+	Resources   map[string]*discoveryResource `json:"resources"`
+}
+
+type discoveryResource struct {
+	Methods   map[string]*discoveryMethod   `json:"methods"`
+	Resources map[string]*discoveryResource `json:"resources"`
+}
+
+type discoveryMethod struct {
+	Path       string                      `json:"path"`
+	FlatPath   string                      `json:"flatPath"`
+	HttpMethod string                      `json:"httpMethod"`
+	Parameters map[string]*discoverySchema `json:"parameters"`
+	Request    *discoverySchema            `json:"request"`
+	Response   *discoverySchema            `json:"response"`
+}
+
+// discoverySchema describes schemas, their properties and method parameters.
+type discoverySchema struct {
+	Ref                  string                      `json:"$ref"`
+	Type                 string                      `json:"type"`
+	Format               string                      `json:"format"`
+	Description          string                      `json:"description"`
+	Enum                 []string                    `json:"enum"`
+	EnumDescriptions     []string                    `json:"enumDescriptions"`
+	ReadOnly             bool                        `json:"readOnly"`
+	Required             bool                        `json:"required"`
+	Location             string                      `json:"location"`
+	Items                *discoverySchema            `json:"items"`
+	Properties           map[string]*discoverySchema `json:"properties"`
+	AdditionalProperties *discoverySchema            `json:"additionalProperties"`
+}
+
+// WriteDiscoveryYaml generates a product and its resources from the Discovery
+// document at filePath, the same way WriteYaml does for OpenAPI specs.
+func (parser Parser) WriteDiscoveryYaml(filePath string) {
+	log.Printf("Reading from file path %s", filePath)
+
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Fatalf("error reading discovery document %s: %v", filePath, err)
+	}
+	var discovery discoveryDocument
+	if err := json.Unmarshal(b, &discovery); err != nil {
+		log.Fatalf("error parsing discovery document %s: %v", filePath, err)
+	}
+
+	parser.writeDocument(discovery.Name, discoveryToOpenapi(&discovery))
+}
+
+// discoveryToOpenapi converts a Discovery document into the OpenAPI document
+// that describes the same API, so that resources and their fields are built
+// by the same code for both formats.
+//
+// Only the create, update, patch and delete methods are converted. Their operation IDs follow the "<Method><Resource>" convention
+// findResources relies on.
+func discoveryToOpenapi(discovery *discoveryDocument) *openapi3.T {
+	server := discovery.RootUrl + strings.TrimSuffix(discovery.ServicePath, discovery.Version+"/")
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   discovery.Title,
+			Version: discovery.Version,
+		},
+		Servers: openapi3.Servers{{URL: strings.TrimSuffix(server, "/")}},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{},
+		},
+	}
+
+	// Allocate every schema first so that references, including recursive
+	// ones, share the same value.
+	for name := range discovery.Schemas {
+		doc.Components.Schemas[name] = &openapi3.SchemaRef{Value: &openapi3.Schema{}}
+	}
+	c := discoveryConverter{doc: doc}
+	for name, s := range discovery.Schemas {
+		*doc.Components.Schemas[name].Value = *c.schema(s).Value
+	}
+
+	c.addResources(discovery.Resources, discovery.ServicePath)
+	return doc
+}
+
+type discoveryConverter struct {
+	doc *openapi3.T
+}
+
+// discoveryStandardMethods maps the methods that manage the lifecycle of a
+// resource to the prefix findResources expects of their operation IDs.
+var discoveryStandardMethods = map[string]string{
+	"create": "Create",
+	"update": "Update",
+	"patch":  "Update",
+	"delete": "Delete",
+}
+
+func (c discoveryConverter) addResources(resources map[string]*discoveryResource, servicePath string) {
+	for _, key := range slices.Sorted(maps.Keys(resources)) {
+		resource := resources[key]
+		name := discoveryResourceName(key, resource)
+		for _, methodName := range slices.Sorted(maps.Keys(resource.Methods)) {
+			if prefix, ok := discoveryStandardMethods[methodName]; ok {
+				c.addMethod(resource.Methods[methodName], prefix+name, servicePath)
+			}
+		}
+		c.addResources(resource.Resources, servicePath)
+	}
+}
+
+// discoveryResourceName names the resource a Discovery resource manages after
+// the schema its methods send or return, falling back to the singular of its
+// collection name.
+func discoveryResourceName(key string, resource *discoveryResource) string {
+	for _, m := range []struct {
+		name     string
+		response bool
+	}{{"create", false}, {"patch", false}, {"update", false}, {"get", true}} {
+		method, ok := resource.Methods[m.name]
+		if !ok {
+			continue
+		}
+		s := method.Request
+		if m.response {
+			s = method.Response
+		}
+		if s != nil && s.Ref != "" {
+			return s.Ref
+		}
+	}
+	return google.Camelize(strings.TrimSuffix(key, "s"), "upper")
+}
+
+// discoveryPathParam matches the parameters of a method's flat path, such as
+// {projectsId}.
+var discoveryPathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+func (c discoveryConverter) addMethod(method *discoveryMethod, operationId, servicePath string) {
+	methodPath := method.FlatPath
+	if methodPath == "" {
+		methodPath = method.Path
+	}
+	key := "/" + servicePath + methodPath
+
+	op := &openapi3.Operation{
+		OperationID: operationId,
+		Responses:   openapi3.NewResponses(),
+	}
+
+	// Flat paths spell out the segments of {+name} and {+parent}, so their
+	// parameters replace the path parameters of the method.
+	for _, match := range discoveryPathParam.FindAllStringSubmatch(methodPath, -1) {
+		param := &openapi3.Parameter{
+			Name:     match[1],
+			In:       openapi3.ParameterInPath,
+			Required: true,
+			Schema:   openapi3.NewStringSchema().NewRef(),
+		}
+		if p, ok := method.Parameters[match[1]]; ok {
+			param.Description = p.Description
+		}
+		op.AddParameter(param)
+	}
+	for _, name := range slices.Sorted(maps.Keys(method.Parameters)) {
+		p := method.Parameters[name]
+		if p.Location != "query" {
+			continue
+		}
+		op.AddParameter(&openapi3.Parameter{
+			Name:        name,
+			In:          openapi3.ParameterInQuery,
+			Required:    p.Required,
+			Description: p.Description,
+			Schema:      c.schema(p),
+		})
+	}
+
+	if method.Request != nil {
+		op.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchemaRef(c.schema(method.Request))}
+	}
+	response := openapi3.NewResponse().WithDescription("Successful response")
+	if method.Response != nil {
+		response = response.WithJSONSchemaRef(c.schema(method.Response))
+	}
+	op.Responses.Set("200", &openapi3.ResponseRef{Value: response})
+
+	pathItem := c.doc.Paths.Value(key)
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+		c.doc.Paths.Set(key, pathItem)
+	}
+	pathItem.SetOperation(method.HttpMethod, op)
+}
+
+// schema converts a Discovery schema into its OpenAPI equivalent. Discovery
+// specific details are carried over as the extensions WriteObject reads:
+// enum descriptions as x-google-enum-descriptions, and the "Identifier." and
+// "Immutable." description prefixes as x-google-identifier and
+// x-google-immutable.
+func (c discoveryConverter) schema(s *discoverySchema) *openapi3.SchemaRef {
+	if s.Ref != "" {
+		ref, ok := c.doc.Components.Schemas[s.Ref]
+		if !ok {
+			log.Fatalf("discovery document references unknown schema %q", s.Ref)
+		}
+		return &openapi3.SchemaRef{Ref: "#/components/schemas/" + s.Ref, Value: ref.Value}
+	}
+
+	schemaType := s.Type
+	if schemaType == "any" || schemaType == "" {
+		schemaType = "object"
+	}
+	schema := &openapi3.Schema{
+		Type:        &openapi3.Types{schemaType},
+		Format:      s.Format,
+		Description: s.Description,
+		ReadOnly:    s.ReadOnly,
+		Extensions:  map[string]any{},
+	}
+	for _, value := range s.Enum {
+		schema.Enum = append(schema.Enum, value)
+	}
+	if len(s.EnumDescriptions) > 0 {
+		schema.Extensions["x-google-enum-descriptions"] = s.EnumDescriptions
+	}
+	if strings.HasPrefix(s.Description, "Identifier.") {
+		schema.Extensions["x-google-identifier"] = true
+	}
+	if strings.HasPrefix(s.Description, "Immutable.") {
+		schema.Extensions["x-google-immutable"] = true
+	}
+
+	if s.Items != nil {
+		schema.Items = c.schema(s.Items)
+	}
+	if s.AdditionalProperties != nil {
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: c.schema(s.AdditionalProperties)}
+	}
+	if len(s.Properties) > 0 {
+		schema.Properties = openapi3.Schemas{}
+		for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+			prop := s.Properties[name]
+			schema.Properties[name] = c.schema(prop)
+			if prop.Required || strings.HasPrefix(prop.Description, "Required.") {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	return &openapi3.SchemaRef{Value: schema}
+}
@@ -0,0 +1,103 @@
+package openapi_generate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+)
+
+//go:embed test_data/test_discovery.json
+var testDiscoveryData []byte
+
+func TestDiscoveryToOpenapi(t *testing.T) {
+	var discovery discoveryDocument
+	if err := json.Unmarshal(testDiscoveryData, &discovery); err != nil {
+		t.Fatalf("Could not load data %s", err)
+	}
+	doc := discoveryToOpenapi(&discovery)
+	if err := doc.Validate(t.Context()); err != nil {
+		t.Fatalf("Could not validate converted document %s", err)
+	}
+
+	if got, want := doc.Servers[0].URL, "https://petstore.googleapis.com"; got != want {
+		t.Errorf("Expected server %q, found %q", want, got)
+	}
+
+	res := findResources(doc)
+	if len(res) != 1 {
+		t.Fatalf("Expected 1 resource, found: %d", len(res))
+	}
+	shelter, ok := res["Shelter"]
+	if !ok {
+		t.Fatal("Expected the Shelter resource")
+	}
+	if shelter.create == nil || shelter.update == nil || shelter.delete == nil {
+		t.Fatalf("Expected Shelter to have create, update and delete operations, found %+v", shelter)
+	}
+	if !shelter.create.async || !shelter.update.async || shelter.delete.async {
+		t.Error("Expected Shelter create and update to be async, and delete not to be")
+	}
+	if !shelter.update.updateMask || shelter.update.verb != "PATCH" {
+		t.Errorf("Expected Shelter to be updated with PATCH and an updateMask, found %+v", shelter.update)
+	}
+
+	resource := buildResource("Shelter", shelter, doc)
+	if got, want := resource.BaseUrl, "projects/{{project}}/locations/{{location}}/shelters"; got != want {
+		t.Errorf("Expected base_url %q, found %q", want, got)
+	}
+	if got, want := resource.CreateUrl, "projects/{{project}}/locations/{{location}}/shelters?shelterId={{shelter_id}}"; got != want {
+		t.Errorf("Expected create_url %q, found %q", want, got)
+	}
+
+	properties := map[string]*api.Type{}
+	for _, p := range resource.Properties {
+		properties[p.Name] = p
+	}
+	cases := []struct {
+		name      string
+		typ       string
+		output    bool
+		required  bool
+		immutable bool
+	}{
+		{name: "name", typ: "String", output: true},
+		{name: "displayName", typ: "String", required: true},
+		{name: "region", typ: "String", immutable: true},
+		{name: "capacity", typ: "Integer"},
+		{name: "createTime", typ: "Time", output: true},
+		{name: "state", typ: "String", output: true},
+		{name: "labels", typ: "KeyValueLabels"},
+		{name: "parentShelter", typ: "NestedObject"},
+	}
+	for _, tc := range cases {
+		p, ok := properties[tc.name]
+		if !ok {
+			t.Errorf("Expected property %s", tc.name)
+			continue
+		}
+		if p.Type != tc.typ || p.Output != tc.output || p.Required != tc.required || p.Immutable != tc.immutable {
+			t.Errorf("Expected %s to be {type: %s, output: %t, required: %t, immutable: %t}, found {type: %s, output: %t, required: %t, immutable: %t}",
+				tc.name, tc.typ, tc.output, tc.required, tc.immutable, p.Type, p.Output, p.Required, p.Immutable)
+		}
+	}
+
+	if state := properties["state"]; state != nil {
+		if !strings.Contains(state.Description, "OPEN: The shelter takes in pets.") || strings.Contains(state.Description, "STATE_UNSPECIFIED") {
+			t.Errorf("Expected state to describe its values, found %q", state.Description)
+		}
+	}
+	if parent := properties["parentShelter"]; parent != nil {
+		var recursive *api.Type
+		for _, p := range parent.Properties {
+			if p.Name == "parentShelter" {
+				recursive = p
+			}
+		}
+		if recursive == nil || recursive.Type != "String" || recursive.CustomExpand == "" {
+			t.Error("Expected the second-level parentShelter to be represented as JSON")
+		}
+	}
+}
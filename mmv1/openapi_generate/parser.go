@@ -93,8 +93,12 @@ func (parser Parser) WriteYaml(filePath string) {
 	doc, _ := loader.LoadFromFile(filePath)
 	_ = doc.Validate(ctx)
 
+	parser.writeDocument(strings.Split(filepath.Base(filePath), "_")[0], doc)
+}
+
+func (parser Parser) writeDocument(productName string, doc *openapi3.T) {
 	resources := findResources(doc)
-	productPath := buildProduct(filePath, parser.Output, doc, header)
+	productPath := buildProduct(productName, parser.Output, doc, header)
 
 	log.Printf("Generated product %+v/product.yaml", productPath)
 	for name, resource := range resources {
@@ -145,8 +149,12 @@ func (parser Parser) writeResource(resource api.Resource, productPath string) {
 }
 
 type resourceOp struct {
-	path  string
+	path string
+	verb string
+	// Whether the operation returns a google.longrunning.Operation.
 	async bool
+	// Whether the operation accepts an updateMask query parameter.
+	updateMask bool
 }
 
 type resource struct {
@@ -168,21 +176,75 @@ func anyToBool(a any) bool {
 	}
 }
 
-func buildOperation(resourcePath string, op *openapi3.Operation, prefix string) (string, *resourceOp) {
+func buildOperation(resourcePath string, op *openapi3.Operation, prefix, verb string) (string, *resourceOp) {
 	if op == nil {
 		return "", nil
 	}
 	if strings.HasPrefix(op.OperationID, prefix) {
 		resourceName := strings.Replace(op.OperationID, prefix, "", 1)
-		async := false
-		if a, ok := op.Extensions["x-google-lro"]; ok {
-			async = anyToBool(a)
+		return resourceName, &resourceOp{
+			path:       resourcePath,
+			verb:       verb,
+			async:      isLongRunning(op),
+			updateMask: hasQueryParameter(op, "updateMask"),
 		}
-		return resourceName, &resourceOp{path: resourcePath, async: async}
 	}
 	return "", nil
 }
 
+// isLongRunning reports whether op returns a google.longrunning.Operation,
+// either because it is marked with the x-google-lro extension or because its
+// successful response is an Operation.
+func isLongRunning(op *openapi3.Operation) bool {
+	if a, ok := op.Extensions["x-google-lro"]; ok {
+		return anyToBool(a)
+	}
+	if op.Responses == nil {
+		return false
+	}
+	for code, response := range op.Responses.Map() {
+		if code != "default" && !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if response.Value == nil {
+			continue
+		}
+		if mediaType := response.Value.Content.Get("application/json"); mediaType != nil && isOperationSchema(mediaType.Schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOperationSchema reports whether schema is a google.longrunning.Operation.
+// Specs converted from discovery documents name it Operation or
+// GoogleLongrunningOperation; otherwise it is recognized by its fields.
+func isOperationSchema(schema *openapi3.SchemaRef) bool {
+	if schema == nil {
+		return false
+	}
+	if name := path.Base(schema.Ref); name == "Operation" || name == "GoogleLongrunningOperation" {
+		return true
+	}
+	if schema.Value == nil {
+		return false
+	}
+	props := schema.Value.Properties
+	if props["name"] == nil || props["done"] == nil {
+		return false
+	}
+	return props["response"] != nil || props["error"] != nil || props["metadata"] != nil
+}
+
+func hasQueryParameter(op *openapi3.Operation, name string) bool {
+	for _, param := range op.Parameters {
+		if param.Value != nil && param.Value.In == openapi3.ParameterInQuery && param.Value.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func findResources(doc *openapi3.T) map[string]*resource {
 	resources := make(map[string]*resource)
 	getDefault := func(n string) *resource {
@@ -195,15 +257,15 @@ func findResources(doc *openapi3.T) map[string]*resource {
 	}
 
 	for key, pathValue := range doc.Paths.Map() {
-		if name, op := buildOperation(key, pathValue.Post, "Create"); op != nil {
+		if name, op := buildOperation(key, pathValue.Post, "Create", "POST"); op != nil {
 			getDefault(name).create = op
 		}
-		if name, op := buildOperation(key, pathValue.Delete, "Delete"); op != nil {
+		if name, op := buildOperation(key, pathValue.Delete, "Delete", "DELETE"); op != nil {
 			getDefault(name).delete = op
 		}
-		if name, op := buildOperation(key, pathValue.Patch, "Update"); op != nil {
+		if name, op := buildOperation(key, pathValue.Patch, "Update", "PATCH"); op != nil {
 			getDefault(name).update = op
-		} else if name, op := buildOperation(key, pathValue.Put, "Update"); op != nil {
+		} else if name, op := buildOperation(key, pathValue.Put, "Update", "PUT"); op != nil {
 			getDefault(name).update = op
 		}
 	}
@@ -211,12 +273,11 @@ func findResources(doc *openapi3.T) map[string]*resource {
 	return resources
 }
 
-func buildProduct(filePath, output string, root *openapi3.T, header []byte) string {
+func buildProduct(productName, output string, root *openapi3.T, header []byte) string {
 
 	version := root.Info.Version
 	server := root.Servers[0].URL
 
-	productName := strings.Split(filepath.Base(filePath), "_")[0]
 	productPath := filepath.Join(output, productName)
 
 	if err := os.MkdirAll(productPath, os.ModePerm); err != nil {
@@ -295,10 +356,8 @@ func buildSingleton(resourceName string, in *resource, root *openapi3.T) api.Res
 	resourcePath := in.update.path
 
 	op := root.Paths.Find(resourcePath).Patch
-	verb := "PATCH"
-	if op == nil {
+	if in.update.verb == "PUT" {
 		op = root.Paths.Find(resourcePath).Put
-		verb = "PUT"
 	}
 	parsedObjects := parseOpenApi(resourcePath, resourceName, op)
 
@@ -313,21 +372,18 @@ func buildSingleton(resourceName string, in *resource, root *openapi3.T) api.Res
 	resource.Parameters = parameters
 	resource.Properties = properties
 	resource.SelfLink = selfLink
-	resource.CreateUrl = fmt.Sprintf("%s=?updateMask=*", baseUrl)
+	resource.CreateUrl = baseUrl
+	if in.update.updateMask {
+		resource.CreateUrl = fmt.Sprintf("%s?updateMask=*", baseUrl)
+	}
 
-	resource.CreateVerb = verb
+	resource.CreateVerb = in.update.verb
 
-	resource.UpdateVerb = verb
-	resource.UpdateMask = true
+	resource.UpdateVerb = in.update.verb
+	resource.UpdateMask = in.update.updateMask
 	if in.update.async {
 		resource.AutogenAsync = true
-		async := api.NewAsync()
-		async.Operation.BaseUrl = "{{op_id}}"
-		async.Result.ResourceInsideResponse = true
-		// Clear the default, we will attach the right values below
-		async.Actions = nil
-		resource.Async = async
-		resource.Async.Actions = append(resource.Async.Actions, "update")
+		resource.Async = buildAsync([]string{"create", "update"})
 	}
 
 	resource.ExcludeDelete = true
@@ -358,28 +414,28 @@ func buildResource(resourceName string, in *resource, root *openapi3.T) api.Reso
 	resource.SelfLink = selfLink
 	resource.CreateUrl = fmt.Sprintf("%s?%s={{%s}}", baseUrl, queryParam, google.Underscore(queryParam))
 
-	resource.AutogenAsync = true
-	async := api.NewAsync()
-	async.Operation.BaseUrl = "{{op_id}}"
-	async.Result.ResourceInsideResponse = true
-	// Clear the default, we will attach the right values below
-	async.Actions = nil
-	resource.Async = async
+	var asyncActions []string
 	if in.create.async {
-		resource.Async.Actions = append(resource.Async.Actions, "create")
+		asyncActions = append(asyncActions, "create")
 	}
 
 	if in.update != nil {
-		resource.UpdateVerb = "PATCH"
-		resource.UpdateMask = true
+		resource.UpdateVerb = in.update.verb
+		resource.UpdateMask = in.update.updateMask
 		if in.update.async {
-			resource.Async.Actions = append(resource.Async.Actions, "update")
+			asyncActions = append(asyncActions, "update")
 		}
 	} else {
+		// Without an update method every change recreates the resource
 		resource.Immutable = true
 	}
 	if in.delete != nil && in.delete.async {
-		resource.Async.Actions = append(resource.Async.Actions, "delete")
+		asyncActions = append(asyncActions, "delete")
+	}
+
+	if len(asyncActions) > 0 {
+		resource.AutogenAsync = true
+		resource.Async = buildAsync(asyncActions)
 	}
 
 	resource = attachStandardFunctionality(resource)
@@ -387,6 +443,18 @@ func buildResource(resourceName string, in *resource, root *openapi3.T) api.Reso
 	return resource
 }
 
+// buildAsync returns an Async block polling the google.longrunning.Operation
+// returned by actions. Operations are named by their full relative resource
+// name (e.g. projects/p/locations/l/operations/o), so the name is also the
+// URL they are polled at, and their response holds the resource.
+func buildAsync(actions []string) *api.Async {
+	async := api.NewAsync()
+	async.Operation.BaseUrl = "{{op_id}}"
+	async.Result.ResourceInsideResponse = true
+	async.Actions = actions
+	return async
+}
+
 // Standard functionality between regular and singleton resources
 func attachStandardFunctionality(resource api.Resource) api.Resource {
 	resource.Description = "Description"
@@ -538,15 +606,11 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 	switch objType[0] {
 	case "string":
 		field.Type = "String"
+		if t, ok := stringFormatType(obj.Value.Format); ok {
+			field.Type = t
+		}
 		if len(obj.Value.Enum) > 0 {
-			var enums []string
-			for _, enum := range obj.Value.Enum {
-				if strings.HasSuffix(fmt.Sprintf("%v", enum), "_UNSPECIFIED") {
-					continue
-				}
-				enums = append(enums, fmt.Sprintf("%v", enum))
-			}
-			additionalDescription = fmt.Sprintf("\n Possible values:\n %s", strings.Join(enums, "\n"))
+			additionalDescription = enumDescription(obj.Value)
 		}
 	case "integer":
 		field.Type = "Integer"
@@ -591,6 +655,9 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 		switch typ[0] {
 		case "string":
 			subField.Type = "String"
+			if t, ok := stringFormatType(obj.Value.Items.Value.Format); ok {
+				subField.Type = t
+			}
 		case "integer":
 			subField.Type = "Integer"
 		case "number":
@@ -686,3 +753,42 @@ func trimDescription(description string) string {
 	}
 	return strings.Join(trimmedDescription, "\n")
 }
+
+// stringFormatType maps the formats Discovery and OpenAPI documents use for
+// strings holding other types of values onto MMv1 types.
+func stringFormatType(format string) (string, bool) {
+	switch format {
+	case "int64", "uint64", "int32", "uint32":
+		return "Integer", true
+	case "google-datetime", "date-time":
+		return "Time", true
+	}
+	return "", false
+}
+
+// enumDescription lists the values of an enum schema, along with their
+// descriptions when the schema has x-google-enum-descriptions.
+func enumDescription(schema *openapi3.Schema) string {
+	var descriptions []string
+	switch d := schema.Extensions["x-google-enum-descriptions"].(type) {
+	case []string:
+		descriptions = d
+	case []any:
+		for _, v := range d {
+			descriptions = append(descriptions, fmt.Sprintf("%v", v))
+		}
+	}
+
+	var enums []string
+	for i, enum := range schema.Enum {
+		value := fmt.Sprintf("%v", enum)
+		if strings.HasSuffix(value, "_UNSPECIFIED") {
+			continue
+		}
+		if i < len(descriptions) && descriptions[i] != "" {
+			value = fmt.Sprintf("%s: %s", value, descriptions[i])
+		}
+		enums = append(enums, value)
+	}
+	return fmt.Sprintf("\n Possible values:\n %s", strings.Join(enums, "\n"))
+}
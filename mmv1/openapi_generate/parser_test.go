@@ -2,6 +2,7 @@ package openapi_generate
 
 import (
 	_ "embed"
+	"slices"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
@@ -70,8 +71,8 @@ func TestFindResources(t *testing.T) {
 		t.Fatalf("Could not validate data %s", err)
 	}
 	res := findResources(doc)
-	if len(res) != 3 {
-		t.Fatalf("Expected 2 resources, found: %d", len(res))
+	if len(res) != 5 {
+		t.Fatalf("Expected 5 resources, found: %d", len(res))
 	}
 	if !res["Food"].create.async {
 		t.Error("Food resource is supposed to be detected as async and is not")
@@ -82,6 +83,61 @@ func TestFindResources(t *testing.T) {
 	if res["Breeds"].update == nil {
 		t.Error("Singleton update should be found")
 	}
+	if !res["Shelter"].create.async || !res["Shelter"].delete.async {
+		t.Error("Shelter create and delete return an Operation and are supposed to be detected as async")
+	}
+	if res["Shelter"].update.async {
+		t.Error("Shelter update is not supposed to be detected as async")
+	}
+	if !res["Shelter"].update.updateMask {
+		t.Error("Shelter update is supposed to accept an updateMask")
+	}
+	if res["Toy"].update.verb != "PUT" || res["Toy"].update.updateMask {
+		t.Errorf("Toy update is supposed to be a PUT without an updateMask, found verb %q with updateMask %t", res["Toy"].update.verb, res["Toy"].update.updateMask)
+	}
+}
+
+func TestBuildResourceAsyncAndUpdate(t *testing.T) {
+	ctx := t.Context()
+	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+	doc, err := loader.LoadFromData(testData)
+	if err != nil {
+		t.Fatalf("Could not load data %s", err)
+	}
+	res := findResources(doc)
+
+	shelter := buildResource("Shelter", res["Shelter"], doc)
+	if shelter.Async == nil {
+		t.Fatal("Expected Shelter to have an async block")
+	}
+	if !shelter.AutogenAsync {
+		t.Error("Expected Shelter to have autogen_async set")
+	}
+	if got, want := shelter.Async.Actions, []string{"create", "delete"}; !slices.Equal(got, want) {
+		t.Errorf("Expected Shelter async actions %v, got %v", want, got)
+	}
+	if shelter.Async.Operation.BaseUrl != "{{op_id}}" {
+		t.Errorf("Expected Shelter operation base_url {{op_id}}, got %q", shelter.Async.Operation.BaseUrl)
+	}
+	if !shelter.Async.Result.ResourceInsideResponse {
+		t.Error("Expected Shelter operation result to hold the resource")
+	}
+	if shelter.UpdateVerb != "PATCH" || !shelter.UpdateMask || shelter.Immutable {
+		t.Errorf("Expected Shelter to be updated with a PATCH and update mask, got verb %q, update_mask %t, immutable %t", shelter.UpdateVerb, shelter.UpdateMask, shelter.Immutable)
+	}
+
+	toy := buildResource("Toy", res["Toy"], doc)
+	if toy.Async != nil || toy.AutogenAsync {
+		t.Errorf("Expected Toy to have no async block, got %+v", toy.Async)
+	}
+	if toy.UpdateVerb != "PUT" || toy.UpdateMask {
+		t.Errorf("Expected Toy to be updated with a PUT without update mask, got verb %q, update_mask %t", toy.UpdateVerb, toy.UpdateMask)
+	}
+
+	pet := buildResource("Pet", res["Pet"], doc)
+	if !pet.Immutable {
+		t.Error("Expected Pet to be immutable as it has no update method")
+	}
 }
 
 func TestReadOnlyPropagation(t *testing.T) {
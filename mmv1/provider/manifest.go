@@ -0,0 +1,328 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest records, for one provider and version, which output files each
+// resource generated and a hash of everything that went into them. It is
+// stored in the output folder so later runs can skip unchanged resources and
+// clean up outputs of resources that no longer exist.
+type Manifest struct {
+	// Generator is a hash of the mmv1 binary that wrote the manifest. Any
+	// change to the generator invalidates every entry.
+	Generator string                       `json:"generator"`
+	Resources map[string]*ManifestResource `json:"resources"`
+}
+
+// ManifestResource is the manifest entry for a single resource, keyed by
+// "<product package path>/<resource name>".
+type ManifestResource struct {
+	Product string `json:"product"`
+
+	// Hash covers the compiled resource (its YAML, any override YAML and
+	// product-level settings) and the generation options.
+	Hash string `json:"hash"`
+
+	// Inputs maps each template, custom_code or example file read while
+	// generating the resource to the hash of its content.
+	Inputs map[string]string `json:"inputs"`
+
+	// Outputs lists the generated files, relative to the output folder.
+	Outputs []string `json:"outputs"`
+}
+
+// incremental holds the state of an incremental run. It is nil unless
+// EnableIncrementalGeneration was called.
+var incremental *incrementalRun
+
+type incrementalRun struct {
+	mu           sync.Mutex
+	manifestPath string
+	outputFolder string
+	inScope      func(productPath string) bool
+	previous     Manifest
+	current      Manifest
+}
+
+// EnableIncrementalGeneration makes generators skip resources whose inputs
+// are unchanged since the manifest for providerName and version in
+// outputFolder was written. inScope reports whether every resource of a
+// product is being generated; only resources of those products are treated
+// as removed when they aren't seen during the run.
+func EnableIncrementalGeneration(outputFolder, providerName, version string, inScope func(productPath string) bool) error {
+	run := &incrementalRun{
+		manifestPath: filepath.Join(outputFolder, fmt.Sprintf(".mmv1-manifest-%s-%s.json", providerName, version)),
+		outputFolder: outputFolder,
+		inScope:      inScope,
+		current:      Manifest{Generator: generatorFingerprint(), Resources: map[string]*ManifestResource{}},
+	}
+
+	b, err := outputFS.ReadFile(run.manifestPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		log.Printf("No manifest found at %q, generating all resources", run.manifestPath)
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &run.previous); err != nil {
+			log.Printf("Ignoring unreadable manifest %q: %v", run.manifestPath, err)
+			run.previous = Manifest{}
+		}
+	}
+
+	incremental = run
+	return nil
+}
+
+// FinishIncrementalGeneration removes outputs that were generated by earlier
+// runs but are no longer produced, then writes the updated manifest.
+func FinishIncrementalGeneration() error {
+	run := incremental
+	if run == nil {
+		return nil
+	}
+	incremental = nil
+
+	written := map[string]bool{}
+	for _, r := range run.current.Resources {
+		for _, o := range r.Outputs {
+			written[o] = true
+		}
+	}
+
+	keys := make([]string, 0, len(run.previous.Resources))
+	for k := range run.previous.Resources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		prev := run.previous.Resources[key]
+		var stale []string
+		if cur, ok := run.current.Resources[key]; ok {
+			for _, o := range prev.Outputs {
+				if !slices.Contains(cur.Outputs, o) {
+					stale = append(stale, o)
+				}
+			}
+		} else if run.inScope(prev.Product) {
+			log.Printf("%s no longer exists, removing its generated files", key)
+			stale = prev.Outputs
+		} else {
+			run.current.Resources[key] = prev
+			continue
+		}
+
+		for _, o := range stale {
+			if written[o] {
+				continue
+			}
+			err := outputFS.Remove(filepath.Join(run.outputFolder, o))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(run.current, "", "  ")
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if err := outputFS.WriteFile(run.manifestPath, append(b, '\n'), 0644); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// generateTracked calls generate with a TemplateData for object. In an
+// incremental run it skips generate entirely if nothing the resource depends
+// on has changed, and otherwise records which templates the TemplateData read
+// and which files it wrote. options are any generation settings that affect
+// the output.
+func generateTracked(object api.Resource, outputFolder, versionName string, templateFS fs.FS, generate func(templateData *TemplateData) error, options ...any) error {
+	run := incremental
+	if run == nil {
+		return generate(NewTemplateData(outputFolder, versionName, templateFS))
+	}
+
+	key := object.ProductMetadata.PackagePath + "/" + object.Name
+	hash, err := resourceHash(object, options...)
+	if err != nil {
+		log.Printf("Cannot hash %s, regenerating it: %v", key, err)
+	}
+
+	if prev := run.previousEntry(key); err == nil && prev != nil && run.upToDate(prev, hash, templateFS) {
+		log.Printf("%s is unchanged, skipping generation", key)
+		run.record(key, prev)
+		return nil
+	}
+
+	reads := &recordingTemplateFS{FS: templateFS, reads: map[string]string{}}
+	writes := &recordingOutputFS{OutputFS: outputFS}
+	templateData := NewTemplateData(outputFolder, versionName, reads)
+	templateData.outputFS = writes
+	if err := generate(templateData); err != nil {
+		return err
+	}
+
+	entry := &ManifestResource{Product: object.ProductMetadata.PackagePath, Hash: hash, Inputs: reads.reads}
+	for _, w := range writes.writes {
+		rel, err := filepath.Rel(outputFolder, w)
+		if err != nil {
+			rel = w
+		}
+		if !slices.Contains(entry.Outputs, rel) {
+			entry.Outputs = append(entry.Outputs, rel)
+		}
+	}
+	sort.Strings(entry.Outputs)
+	run.record(key, entry)
+	return nil
+}
+
+func (run *incrementalRun) previousEntry(key string) *ManifestResource {
+	if run.previous.Resources == nil {
+		return nil
+	}
+	return run.previous.Resources[key]
+}
+
+func (run *incrementalRun) record(key string, entry *ManifestResource) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.current.Resources[key] = entry
+}
+
+// upToDate reports whether prev was produced from the same resource, inputs
+// and generator, and all of its outputs still exist.
+func (run *incrementalRun) upToDate(prev *ManifestResource, hash string, templateFS fs.FS) bool {
+	if run.current.Generator == "" || prev.Hash != hash || run.previous.Generator != run.current.Generator {
+		return false
+	}
+	for p, h := range prev.Inputs {
+		b, err := fs.ReadFile(templateFS, p)
+		if err != nil || hashBytes(b) != h {
+			return false
+		}
+	}
+	for _, o := range prev.Outputs {
+		if _, err := outputFS.Stat(filepath.Join(run.outputFolder, o)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceHash hashes the compiled resource together with the product-level
+// settings and generation options that affect its output.
+func resourceHash(object api.Resource, options ...any) (string, error) {
+	h := sha256.New()
+	enc := yaml.NewEncoder(h)
+	if object.ProductMetadata != nil {
+		product := *object.ProductMetadata
+		product.Objects = nil
+		if err := enc.Encode(product); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Encode(&object); err != nil {
+		return "", err
+	}
+	if err := enc.Encode(options); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func generatorFingerprint() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordingTemplateFS remembers the hash of every file read through it.
+type recordingTemplateFS struct {
+	fs.FS
+
+	mu    sync.Mutex
+	reads map[string]string
+}
+
+func (r *recordingTemplateFS) ReadFile(name string) ([]byte, error) {
+	b, err := fs.ReadFile(r.FS, name)
+	if err == nil {
+		r.mu.Lock()
+		r.reads[name] = hashBytes(b)
+		r.mu.Unlock()
+	}
+	return b, err
+}
+
+func (r *recordingTemplateFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(r.FS, name)
+}
+
+// recordingOutputFS remembers the path of every file written through it.
+type recordingOutputFS struct {
+	google.OutputFS
+
+	mu     sync.Mutex
+	writes []string
+}
+
+func (r *recordingOutputFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	r.mu.Lock()
+	r.writes = append(r.writes, name)
+	r.mu.Unlock()
+	return r.OutputFS.WriteFile(name, data, perm)
+}
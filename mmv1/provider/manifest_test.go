@@ -0,0 +1,90 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+func TestIncrementalGeneration(t *testing.T) {
+	mem := google.NewMemoryOutputFS()
+	SetOutputFS(mem)
+	t.Cleanup(func() { SetOutputFS(google.OSOutputFS{}) })
+
+	outputFolder := t.TempDir()
+	templates := fstest.MapFS{
+		"templates/resource.tmpl": &fstest.MapFile{Data: []byte("v1")},
+	}
+	product := &api.Product{Name: "Pubsub", PackagePath: "products/pubsub"}
+	topic := api.Resource{Name: "Topic", ProductMetadata: product}
+	schema := api.Resource{Name: "Schema", ProductMetadata: product}
+
+	// run generates every resource in resources, returning the names of the
+	// ones that weren't skipped.
+	run := func(resources ...api.Resource) []string {
+		t.Helper()
+		if err := EnableIncrementalGeneration(outputFolder, "terraform", "ga", func(string) bool { return true }); err != nil {
+			t.Fatal(err)
+		}
+		var generated []string
+		for _, r := range resources {
+			err := generateTracked(r, outputFolder, "ga", templates, func(td *TemplateData) error {
+				generated = append(generated, r.Name)
+				if _, err := fs.ReadFile(td.templateFS, "templates/resource.tmpl"); err != nil {
+					return err
+				}
+				return td.outputFS.WriteFile(filepath.Join(outputFolder, r.Name+".go"), []byte(r.Name), 0644)
+			}, true, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := FinishIncrementalGeneration(); err != nil {
+			t.Fatal(err)
+		}
+		return generated
+	}
+
+	if got := run(topic, schema); len(got) != 2 {
+		t.Fatalf("first run generated %v, want both resources", got)
+	}
+
+	if got := run(topic, schema); len(got) != 0 {
+		t.Errorf("unchanged run generated %v, want nothing", got)
+	}
+
+	topic.Description = "changed"
+	if got := run(topic, schema); len(got) != 1 || got[0] != "Topic" {
+		t.Errorf("after changing Topic generated %v, want [Topic]", got)
+	}
+
+	templates["templates/resource.tmpl"] = &fstest.MapFile{Data: []byte("v2")}
+	if got := run(topic, schema); len(got) != 2 {
+		t.Errorf("after changing a template generated %v, want both resources", got)
+	}
+
+	run(topic)
+	if _, err := mem.Stat(filepath.Join(outputFolder, "Schema.go")); err == nil {
+		t.Errorf("Schema.go still exists after Schema was removed")
+	}
+	if _, err := mem.Stat(filepath.Join(outputFolder, "Topic.go")); err != nil {
+		t.Errorf("Topic.go was removed: %v", err)
+	}
+}
@@ -2,19 +2,34 @@ package provider
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"reflect"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"github.com/otiai10/copy"
 )
 
 type Provider interface {
-	Generate(string, string, bool, bool)
-	CopyCommonFiles(outputFolder string, generateCode, generateDocs bool)
-	CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string)
+	Generate(string, string, bool, bool) error
+	CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error
+	CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error
 }
 
 // Shared constants and functions among the providers
 
+// outputFS is where every provider writes generated files. It is the local
+// disk unless replaced with SetOutputFS, e.g. by an in-memory FS for dry runs.
+var outputFS google.OutputFS = google.OSOutputFS{}
+
+// SetOutputFS replaces the filesystem generated files are written to. It must
+// be called before any generation starts.
+func SetOutputFS(fsys google.OutputFS) {
+	outputFS = fsys
+}
+
 const TERRAFORM_PROVIDER_GA = "github.com/hashicorp/terraform-provider-google"
 const TERRAFORM_PROVIDER_BETA = "github.com/hashicorp/terraform-provider-google-beta"
 const TGC_PROVIDER = "github.com/GoogleCloudPlatform/terraform-google-conversion/v7"
@@ -43,3 +58,51 @@ func ImportPathFromVersion(v string) string {
 	}
 	return fmt.Sprintf("%s/%s", tpg, dir)
 }
+
+// copyDir copies srcDir from the local disk into dstDir on the output
+// filesystem. Paths for which skip returns true are not copied; for
+// directories their whole subtree is skipped.
+func copyDir(srcDir, dstDir string, skip func(src string) bool) error {
+	opts := copy.Options{}
+	if skip != nil {
+		opts.Skip = func(srcinfo os.FileInfo, src, dest string) (bool, error) {
+			return skip(src), nil
+		}
+	}
+	if _, ok := outputFS.(google.OSOutputFS); ok {
+		return copy.Copy(srcDir, dstDir, opts)
+	}
+
+	// Copy into a staging directory, then write the copied files to the output
+	// filesystem.
+	stagingDir, err := os.MkdirTemp("", "mmv1-copy")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+	if err := copy.Copy(srcDir, stagingDir, opts); err != nil {
+		return err
+	}
+	return filepath.WalkDir(stagingDir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(stagingDir, src)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dstDir, rel)
+		if d.IsDir() {
+			return outputFS.MkdirAll(dst, os.ModePerm)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		return outputFS.WriteFile(dst, data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+func writeCopyDirSource(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	files := map[string]string{
+		"go.mod":                    "module example",
+		"pkg/util.go":               "package pkg",
+		"pkg/services/compute/a.go": "package compute",
+		"pkg/services/storage/b.go": "package storage",
+		"test/testdata/config.json": "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return src
+}
+
+func skipServices(src string) bool {
+	return strings.Contains(src, "pkg/services/")
+}
+
+// Not parallel, as the tests replace the shared output filesystem.
+func TestCopyDir(t *testing.T) {
+	want := map[string]string{
+		"go.mod":                    "module example",
+		"pkg/util.go":               "package pkg",
+		"test/testdata/config.json": "{}",
+	}
+	notWant := []string{"pkg/services/compute/a.go", "pkg/services/storage/b.go"}
+
+	t.Run("disk", func(t *testing.T) {
+		src := writeCopyDirSource(t)
+		dst := filepath.Join(t.TempDir(), "out")
+		if err := copyDir(src, dst, skipServices); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range want {
+			got, err := os.ReadFile(filepath.Join(dst, name))
+			if err != nil || string(got) != content {
+				t.Errorf("%s = %q, %v; want %q", name, got, err, content)
+			}
+		}
+		for _, name := range notWant {
+			if _, err := os.Stat(filepath.Join(dst, name)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("%s was copied, want it skipped", name)
+			}
+		}
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		mem := google.NewMemoryOutputFS()
+		SetOutputFS(mem)
+		defer SetOutputFS(google.OSOutputFS{})
+
+		src := writeCopyDirSource(t)
+		dst := filepath.Join(t.TempDir(), "out")
+		if err := copyDir(src, dst, skipServices); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range want {
+			got, ok := mem.Written(filepath.Join(dst, name))
+			if !ok || string(got) != content {
+				t.Errorf("%s = %q, written: %t; want %q", name, got, ok, content)
+			}
+		}
+		for _, name := range notWant {
+			if _, ok := mem.Written(filepath.Join(dst, name)); ok {
+				t.Errorf("%s was copied, want it skipped", name)
+			}
+		}
+		if _, err := os.Stat(dst); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s was created on disk, want the copy kept in memory", dst)
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		mem := google.NewMemoryOutputFS()
+		SetOutputFS(mem)
+		defer SetOutputFS(google.OSOutputFS{})
+
+		err := copyDir(filepath.Join(t.TempDir(), "missing"), t.TempDir(), nil)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("copyDir() = %v, want a not exist error", err)
+		}
+	})
+}
@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"go/format"
 	"io/fs"
-	"os"
 	"path/filepath"
 	"text/template"
 
@@ -33,6 +32,7 @@ type TemplateData struct {
 	OutputFolder string
 	VersionName  string
 	templateFS   fs.FS
+	outputFS     google.OutputFS
 
 	// TODO rewrite: is this needed?
 	//     # Information about the local environment
@@ -46,11 +46,11 @@ var ALPHA_VERSION = "alpha"
 var PRIVATE_VERSION = "private"
 
 func NewTemplateData(outputFolder string, versionName string, templateFS fs.FS) *TemplateData {
-	td := TemplateData{OutputFolder: outputFolder, VersionName: versionName, templateFS: templateFS}
+	td := TemplateData{OutputFolder: outputFolder, VersionName: versionName, templateFS: templateFS, outputFS: outputFS}
 	return &td
 }
 
-func (td *TemplateData) GenerateResourceFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateResourceFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/resource.go.tmpl"
 	templates := []string{
 		templatePath,
@@ -63,84 +63,150 @@ func (td *TemplateData) GenerateResourceFile(filePath string, resource api.Resou
 		"templates/terraform/update_mask.go.tmpl",
 		"templates/terraform/nested_query.go.tmpl",
 		"templates/terraform/unordered_list_customize_diff.go.tmpl",
+		"templates/terraform/resource_functions.go.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateFWResourceFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateFWResourceFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/resource_fw.go.tmpl"
 	templates := []string{
 		templatePath,
 		"templates/terraform/schema_property_fw.go.tmpl",
+		"templates/terraform/schema_property.go.tmpl",
+		"templates/terraform/schema_subresource.go.tmpl",
+		"templates/terraform/expand_resource_ref.tmpl",
+		"templates/terraform/custom_flatten/bigquery_table_ref.go.tmpl",
+		"templates/terraform/flatten_property_method.go.tmpl",
+		"templates/terraform/expand_property_method.go.tmpl",
+		"templates/terraform/update_mask.go.tmpl",
+		"templates/terraform/nested_query.go.tmpl",
+		"templates/terraform/resource_functions.go.tmpl",
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateEphemeralResourceFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/ephemeral_resource.go.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/schema_property_fw.go.tmpl",
+		"templates/terraform/expand_resource_ref.tmpl",
+		"templates/terraform/custom_flatten/bigquery_table_ref.go.tmpl",
+		"templates/terraform/flatten_property_method.go.tmpl",
+		"templates/terraform/expand_property_method.go.tmpl",
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateActionFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/action.go.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/expand_resource_ref.tmpl",
+		"templates/terraform/expand_property_method.go.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateMetadataFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateMetadataFile(filePath string, resource api.Resource) error {
 	metadata := metadata.FromResource(resource)
 	bytes, err := yaml.Marshal(metadata)
 	if err != nil {
-		glog.Exit("error marshalling yaml %v: %v", filePath)
-	}
-	err = os.WriteFile(filePath, bytes, 0644)
-	if err != nil {
-		glog.Exit(err)
+		return fmt.Errorf("error marshalling yaml %v: %w", filePath, err)
 	}
+	return td.outputFS.WriteFile(filePath, bytes, 0644)
 }
 
-func (td *TemplateData) GenerateDataSourceFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateDataSourceFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/datasource.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateProductFile(filePath string, product api.Product) {
+func (td *TemplateData) GeneratePluralDataSourceFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/datasource_plural.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateProductFile(filePath string, product api.Product) error {
 	templatePath := "templates/terraform/product.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, product, true, templates...)
+	return td.GenerateFile(filePath, templatePath, product, true, templates...)
 }
 
-func (td *TemplateData) GenerateOperationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateOperationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/operation.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateDocumentationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateDocumentationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/resource.html.markdown.tmpl"
 	templates := []string{
 		templatePath,
 		"templates/terraform/property_documentation.html.markdown.tmpl",
 		"templates/terraform/nested_property_documentation.html.markdown.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateListResourceDocumentationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateListResourceDocumentationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/list_resource.html.markdown.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateDataSourceDocumentationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateDataSourceDocumentationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/datasource.html.markdown.tmpl"
 	templates := []string{
 		templatePath,
 		"templates/terraform/property_documentation.html.markdown.tmpl",
 		"templates/terraform/nested_property_documentation.html.markdown.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GeneratePluralDataSourceDocumentationFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/datasource_plural.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
+}
+
+func (td *TemplateData) GenerateEphemeralResourceDocumentationFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/ephemeral_resource.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/property_documentation.html.markdown.tmpl",
+		"templates/terraform/nested_property_documentation.html.markdown.tmpl",
+	}
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
+}
+
+func (td *TemplateData) GenerateActionDocumentationFile(filePath string, method api.CustomMethod) error {
+	templatePath := "templates/terraform/action.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/property_documentation.html.markdown.tmpl",
+		"templates/terraform/nested_property_documentation.html.markdown.tmpl",
+	}
+	return td.GenerateFile(filePath, templatePath, method, false, templates...)
+}
+
+func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/samples/base_configs/test_file.go.tmpl"
 	templates := []string{
 		"templates/terraform/env_var_context.go.tmpl",
@@ -166,10 +232,10 @@ func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource)
 		VMWAREENGINE_PROJECT: "my-vmwareengine-project",
 	}
 
-	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
+	return td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
-func (td *TemplateData) GenerateDataSourceTestFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateDataSourceTestFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/samples/base_configs/datasource_test_file.go.tmpl"
 	templates := []string{
 		"templates/terraform/env_var_context.go.tmpl",
@@ -195,62 +261,182 @@ func (td *TemplateData) GenerateDataSourceTestFile(filePath string, resource api
 		VMWAREENGINE_PROJECT: "my-vmwareengine-project",
 	}
 
-	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
+	return td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
-func (td *TemplateData) GenerateIamPolicyFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GeneratePluralDataSourceTestFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/samples/base_configs/datasource_plural_test_file.go.tmpl"
+	templates := []string{
+		"templates/terraform/env_var_context.go.tmpl",
+		templatePath,
+	}
+	tmplInput := TestInput{
+		Res:                  resource,
+		ImportPath:           resource.ImportPath,
+		PROJECT_NAME:         "my-project-name",
+		CREDENTIALS:          "my/credentials/filename.json",
+		REGION:               "us-west1",
+		ORG_ID:               "123456789",
+		ORG_DOMAIN:           "example.com",
+		ORG_TARGET:           "123456789",
+		PROJECT_NUMBER:       "1111111111111",
+		BILLING_ACCT:         "000000-0000000-0000000-000000",
+		MASTER_BILLING_ACCT:  "000000-0000000-0000000-000000",
+		SERVICE_ACCT:         "my@service-account.com",
+		CUST_ID:              "A01b123xz",
+		IDENTITY_USER:        "cloud_identity_user",
+		PAP_DESCRIPTION:      "description",
+		CHRONICLE_ID:         "00000000-0000-0000-0000-000000000000",
+		VMWAREENGINE_PROJECT: "my-vmwareengine-project",
+	}
+
+	return td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
+}
+
+func (td *TemplateData) GenerateEphemeralResourceTestFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/samples/base_configs/ephemeral_resource_test_file.go.tmpl"
+	templates := []string{
+		"templates/terraform/env_var_context.go.tmpl",
+		templatePath,
+	}
+	tmplInput := TestInput{
+		Res:                  resource,
+		ImportPath:           resource.ImportPath,
+		PROJECT_NAME:         "my-project-name",
+		CREDENTIALS:          "my/credentials/filename.json",
+		REGION:               "us-west1",
+		ORG_ID:               "123456789",
+		ORG_DOMAIN:           "example.com",
+		ORG_TARGET:           "123456789",
+		PROJECT_NUMBER:       "1111111111111",
+		BILLING_ACCT:         "000000-0000000-0000000-000000",
+		MASTER_BILLING_ACCT:  "000000-0000000-0000000-000000",
+		SERVICE_ACCT:         "my@service-account.com",
+		CUST_ID:              "A01b123xz",
+		IDENTITY_USER:        "cloud_identity_user",
+		PAP_DESCRIPTION:      "description",
+		CHRONICLE_ID:         "00000000-0000-0000-0000-000000000000",
+		VMWAREENGINE_PROJECT: "my-vmwareengine-project",
+	}
+
+	return td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
+}
+
+func (td *TemplateData) GenerateActionTestFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/samples/base_configs/action_test_file.go.tmpl"
+	templates := []string{
+		"templates/terraform/env_var_context.go.tmpl",
+		templatePath,
+	}
+	tmplInput := TestInput{
+		Res:                  resource,
+		ImportPath:           resource.ImportPath,
+		PROJECT_NAME:         "my-project-name",
+		CREDENTIALS:          "my/credentials/filename.json",
+		REGION:               "us-west1",
+		ORG_ID:               "123456789",
+		ORG_DOMAIN:           "example.com",
+		ORG_TARGET:           "123456789",
+		PROJECT_NUMBER:       "1111111111111",
+		BILLING_ACCT:         "000000-0000000-0000000-000000",
+		MASTER_BILLING_ACCT:  "000000-0000000-0000000-000000",
+		SERVICE_ACCT:         "my@service-account.com",
+		CUST_ID:              "A01b123xz",
+		IDENTITY_USER:        "cloud_identity_user",
+		PAP_DESCRIPTION:      "description",
+		CHRONICLE_ID:         "00000000-0000-0000-0000-000000000000",
+		VMWAREENGINE_PROJECT: "my-vmwareengine-project",
+	}
+
+	return td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
+}
+
+func (td *TemplateData) GenerateIdFunctionsFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/id_functions.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateIdFunctionsTestFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/samples/base_configs/id_functions_test_file.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateRoundTripTestFile(filePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/samples/base_configs/round_trip_test_file.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateIdFunctionDocumentationFiles(parseFilePath, buildFilePath string, resource api.Resource) error {
+	templatePath := "templates/terraform/id_function_parse.html.markdown.tmpl"
+	if err := td.GenerateFile(parseFilePath, templatePath, resource, false, templatePath); err != nil {
+		return err
+	}
+	templatePath = "templates/terraform/id_function_build.html.markdown.tmpl"
+	return td.GenerateFile(buildFilePath, templatePath, resource, false, templatePath)
+}
+
+func (td *TemplateData) GenerateIamPolicyFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/iam_policy.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateIamResourceDocumentationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateIamResourceDocumentationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/resource_iam.html.markdown.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateIamDatasourceDocumentationFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateIamDatasourceDocumentationFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/datasource_iam.html.markdown.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateIamPolicyTestFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateIamPolicyTestFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/samples/base_configs/iam_test_file.go.tmpl"
 	templates := []string{
 		templatePath,
 		"templates/terraform/env_var_context.go.tmpl",
 		"templates/terraform/iam/iam_test_setup.go.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
 // GenerateQueryTestFile emits a Terraform query-mode acceptance test for list resources (generate_list_resource).
-func (td *TemplateData) GenerateQueryTestFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateQueryTestFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/samples/base_configs/query_test_file.go.tmpl"
 	templates := []string{
 		templatePath,
 		"templates/terraform/env_var_context.go.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateSweeperFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateSweeperFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/terraform/sweeper_file.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
-func (td *TemplateData) GenerateTGCResourceFile(templatePath, filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateTGCResourceFile(templatePath, filePath string, resource api.Resource) error {
 	templates := []string{
 		templatePath,
 		"templates/terraform/expand_property_method.go.tmpl",
@@ -262,26 +448,26 @@ func (td *TemplateData) GenerateTGCResourceFile(templatePath, filePath string, r
 		"templates/tgc_next/cai2hcl/flatten_property_method_tgc.go.tmpl",
 		"templates/tgc_next/cai2hcl/full_to_relative_path.go.tmpl",
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateTGCIamResourceFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateTGCIamResourceFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/tgc/resource_converter_iam.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateTGCNextTestFile(filePath string, resource api.Resource) {
+func (td *TemplateData) GenerateTGCNextTestFile(filePath string, resource api.Resource) error {
 	templatePath := "templates/tgc_next/test/test_file.go.tmpl"
 	templates := []string{
 		templatePath,
 	}
-	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+	return td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
-func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, goFormat bool, templates ...string) {
+func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, goFormat bool, templates ...string) error {
 	templateFileName := filepath.Base(templatePath)
 	if templatePath == "templates/terraform/examples/base_configs/iam_test_file.go.tmpl" {
 		templatePath = "templates/terraform/samples/base_configs/iam_test_file.go.tmpl"
@@ -296,17 +482,17 @@ func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, g
 
 	tmpl, err := template.New(templateFileName).Funcs(funcMap).ParseFS(td.templateFS, templates...)
 	if err != nil {
-		glog.Exit(fmt.Sprintf("error parsing %s for filepath %s ", templateFileName, filePath), err)
+		return fmt.Errorf("error parsing %s for filepath %s: %w", templateFileName, filePath, err)
 	}
 
 	contents := bytes.Buffer{}
 	if err = tmpl.ExecuteTemplate(&contents, templateFileName, input); err != nil {
-		glog.Exit(fmt.Sprintf("error executing %s for filepath %s ", templateFileName, filePath), err)
+		return fmt.Errorf("error executing %s for filepath %s: %w", templateFileName, filePath, err)
 	}
 
 	sourceByte := contents.Bytes()
 	if len(bytes.TrimSpace(sourceByte)) == 0 {
-		return
+		return nil
 	}
 
 	if goFormat {
@@ -318,10 +504,7 @@ func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, g
 		}
 	}
 
-	err = os.WriteFile(filePath, sourceByte, 0644)
-	if err != nil {
-		glog.Exit(err)
-	}
+	return td.outputFS.WriteFile(filePath, sourceByte, 0644)
 }
 
 type TestInput struct {
@@ -90,7 +90,9 @@ func TestGenerateFile(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			td.GenerateFile(tc.filePath, tc.templatePath, tc.input, tc.goFormat, tc.templates...)
+			if err := td.GenerateFile(tc.filePath, tc.templatePath, tc.input, tc.goFormat, tc.templates...); err != nil {
+				t.Fatal(err)
+			}
 
 			_, err := os.Stat(tc.filePath)
 			exists := !os.IsNotExist(err)
@@ -111,3 +113,26 @@ func TestGenerateFile(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateFileTemplateError(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"templates/parse.go.tmpl":   &fstest.MapFile{Data: []byte(`{{if}}`)},
+		"templates/execute.go.tmpl": &fstest.MapFile{Data: []byte(`{{template "missing"}}`)},
+	}
+
+	tempDir := t.TempDir()
+	td := NewTemplateData(tempDir, "ga", mockFS)
+
+	for _, name := range []string{"parse", "execute"} {
+		t.Run(name, func(t *testing.T) {
+			templatePath := "templates/" + name + ".go.tmpl"
+			filePath := filepath.Join(tempDir, name+".go")
+			if err := td.GenerateFile(filePath, templatePath, nil, true, templatePath); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+				t.Errorf("expected %s not to be written", filePath)
+			}
+		})
+	}
+}
@@ -14,7 +14,6 @@
 package provider
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -74,20 +73,25 @@ func NewTerraform(product *api.Product, versionName string, startTime time.Time,
 	return t
 }
 
-func (t Terraform) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+func (t Terraform) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
-	t.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs)
+	if err := t.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs); err != nil {
+		return err
+	}
 
 	if generateCode {
-		t.GenerateProduct(outputFolder)
-		t.GenerateOperation(outputFolder)
+		if err := t.GenerateProduct(outputFolder); err != nil {
+			return err
+		}
+		return t.GenerateOperation(outputFolder)
 	}
+	return nil
 }
 
-func (t *Terraform) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (t *Terraform) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	for _, object := range t.Product.Objects {
 		object.ExcludeIfNotInVersion(t.Product.Version)
 
@@ -96,133 +100,274 @@ func (t *Terraform) GenerateObjects(outputFolder, resourceToGenerate string, gen
 			continue
 		}
 
-		t.GenerateObject(*object, outputFolder, t.TargetVersionName, generateCode, generateDocs)
+		if err := t.GenerateObject(*object, outputFolder, t.TargetVersionName, generateCode, generateDocs); err != nil {
+			return fmt.Errorf("error generating %s: %w", object.Name, err)
+		}
 	}
+	return nil
 }
 
-func (t *Terraform) GenerateObject(object api.Resource, outputFolder, productPath string, generateCode, generateDocs bool) {
-	templateData := NewTemplateData(outputFolder, t.TargetVersionName, t.templateFS)
+func (t *Terraform) GenerateObject(object api.Resource, outputFolder, productPath string, generateCode, generateDocs bool) error {
+	return generateTracked(object, outputFolder, t.TargetVersionName, t.templateFS, func(templateData *TemplateData) error {
+		if object.IsEphemeral() {
+			if object.IsExcluded() {
+				return nil
+			}
+			log.Printf("Generating %s ephemeral resource", object.Name)
+			return t.GenerateEphemeralResource(object, *templateData, outputFolder, generateCode, generateDocs)
+		}
+
+		if !object.IsExcluded() {
+			log.Printf("Generating %s resource", object.Name)
+			if err := t.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
+			if err := t.GenerateSingularDataSource(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
+			if err := t.GeneratePluralDataSource(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
+			if err := t.GenerateActions(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
+			if err := t.GenerateIdFunctions(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
 
-	if !object.IsExcluded() {
-		log.Printf("Generating %s resource", object.Name)
-		t.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
-		t.GenerateSingularDataSource(object, *templateData, outputFolder, generateCode, generateDocs)
-
-		if generateCode {
-			// log.Printf("Generating %s tests", object.Name)
-			t.GenerateResourceTests(object, *templateData, outputFolder)
-			t.GenerateResourceSweeper(object, *templateData, outputFolder)
-			t.GenerateSingularDataSourceTests(object, *templateData, outputFolder)
-			// log.Printf("Generating %s metadata", object.Name)
-			t.GenerateResourceMetadata(object, *templateData, outputFolder)
+			if generateCode {
+				// log.Printf("Generating %s tests", object.Name)
+				if err := t.GenerateResourceTests(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+				if err := t.GenerateResourceSweeper(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+				if err := t.GenerateRoundTripTests(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+				if err := t.GenerateSingularDataSourceTests(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+				if err := t.GeneratePluralDataSourceTests(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+				// log.Printf("Generating %s metadata", object.Name)
+				if err := t.GenerateResourceMetadata(object, *templateData, outputFolder); err != nil {
+					return err
+				}
+			}
 		}
-	}
 
-	// if iam_policy is not defined or excluded, don't generate it
-	if object.IamPolicy == nil || object.IamPolicy.Exclude {
-		return
-	}
+		// if iam_policy is not defined or excluded, don't generate it
+		if object.IamPolicy == nil || object.IamPolicy.Exclude {
+			return nil
+		}
 
-	t.GenerateIamPolicy(object, *templateData, outputFolder, generateCode, generateDocs)
+		return t.GenerateIamPolicy(object, *templateData, outputFolder, generateCode, generateDocs)
+	}, generateCode, generateDocs)
 }
 
 func (t *Terraform) makeFolder(filePath ...string) string {
 	targetFolder := path.Join(filePath...)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	return targetFolder
 }
 
-func (t *Terraform) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (t *Terraform) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	if generateCode {
 		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 		if object.FrameworkResource {
-			fmt.Printf("\n\x1b[1;33mWARNING:\x1b[0m\n")
-			fmt.Printf("The plugin framework generation code is considered a WIP and experimental.\nAre you sure you want to use it for %s? (y/n) ", t.ResourceGoFilename(object))
-
-			reader := bufio.NewReader(os.Stdin)
-			input, err := reader.ReadString('\n')
-			if err != nil {
-				log.Fatalf("Error reading input: %v", err)
-			}
-
-			if strings.ToLower(strings.TrimSpace(input)) == "y" {
-				targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_fw_%s.go", t.ResourceGoFilename(object)))
-				templateData.GenerateFWResourceFile(targetFilePath, object)
-			} else {
-				log.Fatalf("please remove \"plugin_framework_experimental: true\" from the YAML configuration.")
+			targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_fw_%s.go", t.ResourceGoFilename(object)))
+			if err := templateData.GenerateFWResourceFile(targetFilePath, object); err != nil {
+				return err
 			}
 		} else {
 			targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s.go", t.ResourceGoFilename(object)))
-			templateData.GenerateResourceFile(targetFilePath, object)
+			if err := templateData.GenerateResourceFile(targetFilePath, object); err != nil {
+				return err
+			}
 		}
 
-		t.GenerateListResource(object, templateData, targetFolder)
+		if err := t.GenerateListResource(object, templateData, targetFolder); err != nil {
+			return err
+		}
 	}
 
 	if generateDocs {
 		targetFolder := t.makeFolder(outputFolder, "website", "docs", "r")
 		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.FullResourceName(object)))
-		templateData.GenerateDocumentationFile(targetFilePath, object)
+		if err := templateData.GenerateDocumentationFile(targetFilePath, object); err != nil {
+			return err
+		}
 
 		if object.GenerateListResource {
 			listDocFolder := t.makeFolder(outputFolder, "website", "docs", "list-resources")
 			listDocFilePath := path.Join(listDocFolder, fmt.Sprintf("%s.html.markdown", object.TerraformName()))
-			templateData.GenerateListResourceDocumentationFile(listDocFilePath, object)
+			if err := templateData.GenerateListResourceDocumentationFile(listDocFilePath, object); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (t *Terraform) GenerateListResource(object api.Resource, templateData TemplateData, targetFolder string) {
-	if object.GenerateListResource {
-		if object.ExcludeIdentityGeneration {
-			log.Fatalf("generate_list_resource requires identity support; remove exclude_identity_generation from resource %q or disable generate_list_resource", object.Name)
+// GenerateEphemeralResource generates the plugin framework ephemeral resource
+// of a resource with an `ephemeral` block, with its tests and documentation.
+func (t *Terraform) GenerateEphemeralResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("ephemeral_%s.go", t.ResourceGoFilename(object)))
+		if err := templateData.GenerateEphemeralResourceFile(targetFilePath, object); err != nil {
+			return err
+		}
+
+		if object.Examples != nil {
+			return fmt.Errorf("examples block exists in %v", object.Name)
+		}
+		if !object.Ephemeral.ExcludeTest && len(object.TestSamples()) > 0 {
+			targetFilePath := path.Join(targetFolder, fmt.Sprintf("ephemeral_%s_generated_test.go", t.ResourceGoFilename(object)))
+			if err := templateData.GenerateEphemeralResourceTestFile(targetFilePath, object); err != nil {
+				return err
+			}
 		}
-		if object.ExcludeRead {
-			log.Fatalf("generate_list_resource requires read support; remove exclude_read from resource %q or disable generate_list_resource", object.Name)
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "ephemeral-resources")
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.FullResourceName(object)))
+		if err := templateData.GenerateEphemeralResourceDocumentationFile(targetFilePath, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateActions generates the plugin framework actions of the custom
+// methods of a resource, with their tests and documentation.
+func (t *Terraform) GenerateActions(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
+	methods := object.UserCustomMethods()
+	if len(methods) == 0 {
+		return nil
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("action_%s.go", t.ResourceGoFilename(object)))
+		if err := templateData.GenerateActionFile(targetFilePath, object); err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(methods, func(m *api.CustomMethod) bool { return m.ShouldGenerateTests() }) {
+			targetFilePath := path.Join(targetFolder, fmt.Sprintf("action_%s_generated_test.go", t.ResourceGoFilename(object)))
+			if err := templateData.GenerateActionTestFile(targetFilePath, object); err != nil {
+				return err
+			}
+		}
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "actions")
+		for _, m := range methods {
+			targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", strings.TrimPrefix(m.TerraformName(), "google_")))
+			if err := templateData.GenerateActionDocumentationFile(targetFilePath, *m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateIdFunctions generates the provider functions parsing and building
+// the ids of a resource, with their tests and documentation.
+func (t *Terraform) GenerateIdFunctions(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
+	if !object.ShouldGenerateIdFunctions() {
+		return nil
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("function_%s_id.go", t.ResourceGoFilename(object)))
+		if err := templateData.GenerateIdFunctionsFile(targetFilePath, object); err != nil {
+			return err
+		}
+		targetFilePath = path.Join(targetFolder, fmt.Sprintf("function_%s_id_internal_test.go", t.ResourceGoFilename(object)))
+		if err := templateData.GenerateIdFunctionsTestFile(targetFilePath, object); err != nil {
+			return err
+		}
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "functions")
+		parseFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", object.ParseIdFunctionName()))
+		buildFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", object.BuildIdFunctionName()))
+		if err := templateData.GenerateIdFunctionDocumentationFiles(parseFilePath, buildFilePath, object); err != nil {
+			return err
 		}
-		targetFilePath := path.Join(targetFolder, fmt.Sprintf("list_%s.go", t.ResourceGoFilename(object)))
-		templateData.GenerateFile(targetFilePath, "templates/terraform/list_resource.go.tmpl", object, true,
-			"templates/terraform/list_resource.go.tmpl",
-			"templates/terraform/list_resource_method.go.tmpl",
-		)
+	}
+	return nil
+}
 
-		t.GenerateListResourceQueryTest(object, templateData, targetFolder)
+func (t *Terraform) GenerateListResource(object api.Resource, templateData TemplateData, targetFolder string) error {
+	if !object.GenerateListResource {
+		return nil
+	}
+	if object.ExcludeIdentityGeneration {
+		return fmt.Errorf("generate_list_resource requires identity support; remove exclude_identity_generation from resource %q or disable generate_list_resource", object.Name)
 	}
+	if object.ExcludeRead {
+		return fmt.Errorf("generate_list_resource requires read support; remove exclude_read from resource %q or disable generate_list_resource", object.Name)
+	}
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("list_%s.go", t.ResourceGoFilename(object)))
+	err := templateData.GenerateFile(targetFilePath, "templates/terraform/list_resource.go.tmpl", object, true,
+		"templates/terraform/list_resource.go.tmpl",
+		"templates/terraform/list_resource_method.go.tmpl",
+	)
+	if err != nil {
+		return err
+	}
+
+	return t.GenerateListResourceQueryTest(object, templateData, targetFolder)
 }
 
 // GenerateResourceFile is the Bazel counterpart to GenerateResource(), generating *only() the .go file and
 // taking the full path to the output file to generate rather than implicitly generating the path.
-func (t *Terraform) GenerateResourceFile(object api.Resource, targetFilePath string) {
-	if object.FrameworkResource {
-		log.Fatalf("Framework resources are currently unsupported")
-	}
+func (t *Terraform) GenerateResourceFile(object api.Resource, targetFilePath string) error {
 	targetFolder := path.Dir(targetFilePath)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
-	templateData.GenerateResourceFile(targetFilePath, object)
+	if object.IsEphemeral() {
+		return templateData.GenerateEphemeralResourceFile(targetFilePath, object)
+	}
+	if object.FrameworkResource {
+		return templateData.GenerateFWResourceFile(targetFilePath, object)
+	}
+	return templateData.GenerateResourceFile(targetFilePath, object)
 }
 
-func (t *Terraform) GenerateResourceMetadata(object api.Resource, templateData TemplateData, outputFolder string) {
+func (t *Terraform) GenerateResourceMetadata(object api.Resource, templateData TemplateData, outputFolder string) error {
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	target := fmt.Sprintf("resource_%s_generated_meta.yaml", t.FullResourceName(object))
 	targetFilePath := path.Join(targetFolder, target)
-	templateData.GenerateMetadataFile(targetFilePath, object)
-	t.addHashicorpCopyRightHeader(outputFolder, path.Join(t.FolderName(), "services", t.Product.ApiName, target))
+	if err := templateData.GenerateMetadataFile(targetFilePath, object); err != nil {
+		return err
+	}
+	return t.addHashicorpCopyRightHeader(outputFolder, path.Join(t.FolderName(), "services", t.Product.ApiName, target))
 }
 
 // GenerateResourceMetadataFile is used by the Bazel version of the MM compiler to generate the specified
 // resource's `generated_meta.yaml` file.
-func (t *Terraform) GenerateResourceMetadataFile(object api.Resource, targetFilePath string) {
+func (t *Terraform) GenerateResourceMetadataFile(object api.Resource, targetFilePath string) error {
 	targetFolder := path.Dir(targetFilePath)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
-	templateData.GenerateMetadataFile(targetFilePath, object)
+	return templateData.GenerateMetadataFile(targetFilePath, object)
 }
 
 func (t *Terraform) hasEligibleSample(object api.Resource) bool {
@@ -237,137 +382,188 @@ func (t *Terraform) hasEligibleSample(object api.Resource) bool {
 	return false
 }
 
-func (t *Terraform) GenerateResourceTests(object api.Resource, templateData TemplateData, outputFolder string) {
+func (t *Terraform) GenerateResourceTests(object api.Resource, templateData TemplateData, outputFolder string) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 
 	if !t.hasEligibleSample(object) {
-		return
+		return nil
 	}
 
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s_generated_test.go", t.ResourceGoFilename(object)))
-	templateData.GenerateTestFile(targetFilePath, object)
+	return templateData.GenerateTestFile(targetFilePath, object)
 }
 
-func (t *Terraform) GenerateListResourceQueryTest(object api.Resource, templateData TemplateData, targetFolder string) {
+func (t *Terraform) GenerateListResourceQueryTest(object api.Resource, templateData TemplateData, targetFolder string) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 	if object.Samples == nil || !t.hasEligibleSample(object) {
-		return
+		return nil
 	}
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("list_%s_generated_test.go", t.ResourceGoFilename(object)))
-	templateData.GenerateQueryTestFile(targetFilePath, object)
+	return templateData.GenerateQueryTestFile(targetFilePath, object)
+}
+
+func (t *Terraform) GenerateRoundTripTests(object api.Resource, templateData TemplateData, outputFolder string) error {
+	if !object.ShouldGenerateRoundTripTests() {
+		return nil
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s_round_trip_internal_test.go", t.ResourceGoFilename(object)))
+	return templateData.GenerateRoundTripTestFile(targetFilePath, object)
 }
 
-func (t *Terraform) GenerateResourceSweeper(object api.Resource, templateData TemplateData, outputFolder string) {
+func (t *Terraform) GenerateResourceSweeper(object api.Resource, templateData TemplateData, outputFolder string) error {
 	if !object.ShouldGenerateSweepers() {
-		return
+		return nil
 	}
 
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s_sweeper.go", t.ResourceGoFilename(object)))
-	templateData.GenerateSweeperFile(targetFilePath, object)
+	return templateData.GenerateSweeperFile(targetFilePath, object)
 }
 
 // GenerateResourceMetadataFile is used by the Bazel version of the MM compiler to generate the sweeper for
-// the specified resource. It panics if the resource does not use a sweeper.
-func (t *Terraform) GenerateResourceSweeperFile(object api.Resource, targetFilePath string) {
+// the specified resource. It returns an error if the resource does not use a sweeper.
+func (t *Terraform) GenerateResourceSweeperFile(object api.Resource, targetFilePath string) error {
 	if !object.ShouldGenerateSweepers() {
-		log.Fatalf("attempting to generate a sweeper for unswept resource %q", object.Name)
+		return fmt.Errorf("attempting to generate a sweeper for unswept resource %q", object.Name)
 	}
 	targetFolder := path.Dir(targetFilePath)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
-	templateData.GenerateSweeperFile(targetFilePath, object)
+	return templateData.GenerateSweeperFile(targetFilePath, object)
 }
 
-func (t *Terraform) GenerateSingularDataSource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (t *Terraform) GenerateSingularDataSource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	if !object.ShouldGenerateSingularDataSource() {
-		return
+		return nil
 	}
 
 	if generateCode {
 		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 		targetFilePath := path.Join(targetFolder, fmt.Sprintf("data_source_%s.go", t.ResourceGoFilename(object)))
-		templateData.GenerateDataSourceFile(targetFilePath, object)
+		if err := templateData.GenerateDataSourceFile(targetFilePath, object); err != nil {
+			return err
+		}
 	}
 
 	if generateDocs {
 		targetFolder := t.makeFolder(outputFolder, "website", "docs", "d")
 		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.FullResourceName(object)))
-		templateData.GenerateDataSourceDocumentationFile(targetFilePath, object)
+		if err := templateData.GenerateDataSourceDocumentationFile(targetFilePath, object); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (t *Terraform) GenerateSingularDataSourceTests(object api.Resource, templateData TemplateData, outputFolder string) {
+func (t *Terraform) GenerateSingularDataSourceTests(object api.Resource, templateData TemplateData, outputFolder string) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 
 	if !object.ShouldGenerateSingularDataSourceTests() {
-		return
+		return nil
 	}
 
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("data_source_%s_test.go", t.ResourceGoFilename(object)))
-	templateData.GenerateDataSourceTestFile(targetFilePath, object)
+	return templateData.GenerateDataSourceTestFile(targetFilePath, object)
+}
+
+func (t *Terraform) GeneratePluralDataSource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
+	if !object.ShouldGeneratePluralDataSource() {
+		return nil
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("data_source_%s.go", t.PluralResourceName(object)))
+		if err := templateData.GeneratePluralDataSourceFile(targetFilePath, object); err != nil {
+			return err
+		}
+	}
 
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "d")
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.PluralResourceName(object)))
+		if err := templateData.GeneratePluralDataSourceDocumentationFile(targetFilePath, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Terraform) GeneratePluralDataSourceTests(object api.Resource, templateData TemplateData, outputFolder string) error {
+	if object.Examples != nil {
+		return fmt.Errorf("examples block exists in %v", object.Name)
+	}
+
+	if !object.ShouldGeneratePluralDataSource() || !object.ShouldGeneratePluralDataSourceTests() {
+		return nil
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("data_source_%s_test.go", t.PluralResourceName(object)))
+	return templateData.GeneratePluralDataSourceTestFile(targetFilePath, object)
 }
 
 // GenerateProduct creates the product.go file for a given service directory.
 // This will be used to seed the directory and add a package-level comment
 // specific to the product.
-func (t *Terraform) GenerateProduct(outputFolder string) {
+func (t *Terraform) GenerateProduct(outputFolder string) error {
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	targetFilePath := path.Join(targetFolder, "product.go")
 	templateData := NewTemplateData(outputFolder, t.TargetVersionName, t.templateFS)
-	templateData.GenerateProductFile(targetFilePath, *t.Product)
+	return templateData.GenerateProductFile(targetFilePath, *t.Product)
 }
 
 // GenerateProduct creates the product.go file for the bazel version of the MM compiler.
-func (t *Terraform) GenerateProductFile(targetFilePath string) {
+func (t *Terraform) GenerateProductFile(targetFilePath string) error {
 	targetFolder := path.Dir(targetFilePath)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
-	templateData.GenerateProductFile(targetFilePath, *t.Product)
+	return templateData.GenerateProductFile(targetFilePath, *t.Product)
 }
 
-func (t *Terraform) GenerateOperation(outputFolder string) {
+func (t *Terraform) GenerateOperation(outputFolder string) error {
 	asyncObjects := google.Select(t.Product.Objects, func(o *api.Resource) bool {
 		return o.AutogenAsync
 	})
 
 	if len(asyncObjects) == 0 {
-		return
+		return nil
 	}
 
 	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_operation.go", google.Underscore(t.Product.Name)))
 	templateData := NewTemplateData(outputFolder, t.TargetVersionName, t.templateFS)
-	templateData.GenerateOperationFile(targetFilePath, *asyncObjects[0])
+	return templateData.GenerateOperationFile(targetFilePath, *asyncObjects[0])
 }
 
 // GenerateProduct creates the operation.go file for the bazel version of the MM compiler.
-func (t *Terraform) GenerateOperationFile(object api.Resource, targetFilePath string) {
+func (t *Terraform) GenerateOperationFile(object api.Resource, targetFilePath string) error {
 	targetFolder := path.Dir(targetFilePath)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
-	templateData.GenerateOperationFile(targetFilePath, object)
+	return templateData.GenerateOperationFile(targetFilePath, object)
 }
 
-func (t *Terraform) GenerateIamPolicy(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (t *Terraform) GenerateIamPolicy(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 
 	if object.IamPolicy.SampleConfigBody == "" {
@@ -377,7 +573,9 @@ func (t *Terraform) GenerateIamPolicy(object api.Resource, templateData Template
 	if generateCode && object.IamPolicy != nil && (object.IamPolicy.MinVersion == "" || slices.Index(product.ORDER, object.IamPolicy.MinVersion) <= slices.Index(product.ORDER, t.TargetVersionName)) {
 		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
 		targetFilePath := path.Join(targetFolder, fmt.Sprintf("iam_%s.go", t.ResourceGoFilename(object)))
-		templateData.GenerateIamPolicyFile(targetFilePath, object)
+		if err := templateData.GenerateIamPolicyFile(targetFilePath, object); err != nil {
+			return err
+		}
 
 		// Only generate test if testable example configs exist.
 		samples := google.Reject(object.Samples, func(s *resource.Sample) bool {
@@ -385,22 +583,27 @@ func (t *Terraform) GenerateIamPolicy(object api.Resource, templateData Template
 		})
 		if len(samples) != 0 {
 			targetFilePath := path.Join(targetFolder, fmt.Sprintf("iam_%s_generated_test.go", t.ResourceGoFilename(object)))
-			templateData.GenerateIamPolicyTestFile(targetFilePath, object)
+			if err := templateData.GenerateIamPolicyTestFile(targetFilePath, object); err != nil {
+				return err
+			}
 		}
 	}
 	if generateDocs {
-		t.GenerateIamDocumentation(object, templateData, outputFolder, generateCode, generateDocs)
+		return t.GenerateIamDocumentation(object, templateData, outputFolder, generateCode, generateDocs)
 	}
+	return nil
 }
 
-func (t *Terraform) GenerateIamDocumentation(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (t *Terraform) GenerateIamDocumentation(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	resourceDocFolder := t.makeFolder(outputFolder, "website", "docs", "r")
 	targetFilePath := path.Join(resourceDocFolder, fmt.Sprintf("%s_iam.html.markdown", t.FullResourceName(object)))
-	templateData.GenerateIamResourceDocumentationFile(targetFilePath, object)
+	if err := templateData.GenerateIamResourceDocumentationFile(targetFilePath, object); err != nil {
+		return err
+	}
 
 	datasourceDocFolder := t.makeFolder(outputFolder, "website", "docs", "d")
 	targetFilePath = path.Join(datasourceDocFolder, fmt.Sprintf("%s_iam_policy.html.markdown", t.FullResourceName(object)))
-	templateData.GenerateIamDatasourceDocumentationFile(targetFilePath, object)
+	return templateData.GenerateIamDatasourceDocumentationFile(targetFilePath, object)
 }
 
 // Finds the folder name for a given version of the terraform provider
@@ -431,6 +634,12 @@ func (t *Terraform) ResourceGoFilename(object api.Resource) string {
 	return fmt.Sprintf("%s_%s", productName, resName)
 }
 
+// PluralResourceName returns the name of the plural data source of a resource
+// without the provider prefix, such as certificate_manager_certificates.
+func (t *Terraform) PluralResourceName(object api.Resource) string {
+	return strings.TrimPrefix(object.PluralTerraformName(), "google_")
+}
+
 func (t *Terraform) FullResourceName(object api.Resource) string {
 	// early exit- resource-level legacy names override the product too
 	if object.LegacyName != "" {
@@ -447,11 +656,11 @@ func (t *Terraform) FullResourceName(object api.Resource) string {
 	return fmt.Sprintf("%s_%s", productName, google.Underscore(object.Name))
 }
 
-func (t Terraform) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) {
+func (t Terraform) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
 	log.Printf("Copying common files for %s", ProviderName(t))
 
 	files := t.getCommonCopyFiles(t.TargetVersionName, generateCode, generateDocs)
-	t.CopyFileList(outputFolder, files, generateCode)
+	return t.CopyFileList(outputFolder, files, generateCode)
 }
 
 // To copy a new folder, add the folder to foldersCopiedToRootDir or foldersCopiedToGoogleDir.
@@ -564,23 +773,23 @@ func (t Terraform) getCopyFilesInFolder(folderPath, targetDir string) map[string
 	return m
 }
 
-func (t Terraform) CopyFileList(outputFolder string, files map[string]string, generateCode bool) {
+func (t Terraform) CopyFileList(outputFolder string, files map[string]string, generateCode bool) error {
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
 
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 		// If we've modified a file since starting an MM run, it's a reasonable
 		// assumption that it was this run that modified it.
-		if info, err := os.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && t.StartTime.Before(info.ModTime()) {
-			log.Fatalf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
+		if info, err := outputFS.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && t.StartTime.Before(info.ModTime()) {
+			return fmt.Errorf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
 		}
 
 		sourceByte, err := fs.ReadFile(t.templateFS, source)
 		if err != nil {
-			log.Fatalf("Cannot read source file %s while copying: %s", source, err)
+			return fmt.Errorf("cannot read source file %s while copying: %w", source, err)
 		}
 
 		var permission fs.FileMode
@@ -590,33 +799,40 @@ func (t Terraform) CopyFileList(outputFolder string, files map[string]string, ge
 			permission = 0644
 		}
 
-		err = os.WriteFile(targetFile, sourceByte, permission)
+		err = outputFS.WriteFile(targetFile, sourceByte, permission)
 		if err != nil {
-			log.Fatalf("Cannot write target file %s while copying: %s", target, err)
+			return fmt.Errorf("cannot write target file %s while copying: %w", target, err)
 		}
 
 		// Replace import path based on version (beta/alpha)
 		if filepath.Ext(target) == ".go" || (filepath.Ext(target) == ".mod" && generateCode) {
-			t.replaceImportPath(outputFolder, target)
+			if err := t.replaceImportPath(outputFolder, target); err != nil {
+				return err
+			}
 		}
 		if filepath.Ext(target) == ".go" || filepath.Ext(target) == ".markdown" {
-			t.addCopyfileHeader(source, outputFolder, target)
+			if err := t.addCopyfileHeader(source, outputFolder, target); err != nil {
+				return err
+			}
 		}
 		if filepath.Ext(target) == ".go" || filepath.Ext(target) == ".yaml" {
-			t.addHashicorpCopyRightHeader(outputFolder, target)
+			if err := t.addHashicorpCopyRightHeader(outputFolder, target); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
 // Compiles files that are shared at the provider level
-func (t Terraform) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) {
+func (t Terraform) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
 	log.Printf("Generating common files for %s", ProviderName(t))
 	if t.Product == nil {
 		t.generateResourcesForVersion(products)
 	}
 	files := t.getCommonCompileFiles(t.TargetVersionName)
 	templateData := NewTemplateData(outputFolder, t.TargetVersionName, t.templateFS)
-	t.CompileFileList(outputFolder, files, *templateData, products)
+	return t.CompileFileList(outputFolder, files, *templateData, products)
 }
 
 // To compile a new folder, add the folder to foldersCompiledToRootDir or foldersCompiledToGoogleDir.
@@ -703,20 +919,20 @@ func (t Terraform) getCompileFilesInFolder(folderPath, targetDir string) map[str
 	return m
 }
 
-func (t Terraform) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) {
+func (t Terraform) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) error {
 	providerWithProducts := ProviderWithProducts{
 		Terraform: t,
 		Products:  products,
 	}
 
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 
@@ -726,34 +942,43 @@ func (t Terraform) CompileFileList(outputFolder string, files map[string]string,
 
 		formatFile := filepath.Ext(targetFile) == ".go"
 
-		fileTemplate.GenerateFile(targetFile, source, providerWithProducts, formatFile, templates...)
+		if err := fileTemplate.GenerateFile(targetFile, source, providerWithProducts, formatFile, templates...); err != nil {
+			return err
+		}
 		// continue to next file if no file was generated
-		if _, err := os.Stat(targetFile); errors.Is(err, os.ErrNotExist) {
+		if _, err := outputFS.Stat(targetFile); errors.Is(err, os.ErrNotExist) {
 			continue
 		}
-		t.replaceImportPath(outputFolder, target)
+		if err := t.replaceImportPath(outputFolder, target); err != nil {
+			return err
+		}
 		if filepath.Ext(targetFile) == ".go" || filepath.Ext(targetFile) == ".markdown" {
-			t.addCopyfileHeader(source, outputFolder, target)
+			if err := t.addCopyfileHeader(source, outputFolder, target); err != nil {
+				return err
+			}
+		}
+		if err := t.addHashicorpCopyRightHeader(outputFolder, target); err != nil {
+			return err
 		}
-		t.addHashicorpCopyRightHeader(outputFolder, target)
 	}
+	return nil
 }
 
-func (t Terraform) addCopyfileHeader(srcpath, outputFolder, target string) {
+func (t Terraform) addCopyfileHeader(srcpath, outputFolder, target string) error {
 	githubPrefix := "https://github.com/GoogleCloudPlatform/magic-modules/tree/main/mmv1/"
 	if !strings.HasPrefix(srcpath, githubPrefix) {
 		srcpath = githubPrefix + srcpath
 	}
 
 	targetFile := filepath.Join(outputFolder, target)
-	sourceByte, err := os.ReadFile(targetFile)
+	sourceByte, err := outputFS.ReadFile(targetFile)
 	if err != nil {
-		log.Fatalf("Cannot read file %s to add copy file header: %s", targetFile, err)
+		return fmt.Errorf("cannot read file %s to add copy file header: %w", targetFile, err)
 	}
 
 	srcStr := string(sourceByte)
 	if strings.Contains(srcStr, "***     AUTO GENERATED CODE    ***    Type: Handwritten     ***") {
-		return
+		return nil
 	}
 
 	templateFormat := `// ----------------------------------------------------------------------------
@@ -786,17 +1011,18 @@ func (t Terraform) addCopyfileHeader(srcpath, outputFolder, target string) {
 		sourceByte, err = format.Source(sourceByte)
 		if err != nil {
 			log.Printf("error formatting %s: %s\n", targetFile, err)
-			return
+			return nil
 		}
 	}
 
-	err = os.WriteFile(targetFile, sourceByte, 0644)
+	err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 	if err != nil {
-		log.Fatalf("Cannot write file %s to add copy file header: %s", target, err)
+		return fmt.Errorf("cannot write file %s to add copy file header: %w", target, err)
 	}
+	return nil
 }
 
-func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) {
+func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) error {
 	if !expectedOutputFolder(outputFolder) {
 		log.Printf("Unexpected output folder (%s) detected "+
 			"when deciding to add HashiCorp copyright headers.\n"+
@@ -804,7 +1030,7 @@ func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) {
 	}
 	// only add copyright headers when generating TPG, TPGB, and TPGN
 	if !(strings.HasSuffix(outputFolder, "terraform-provider-google") || strings.HasSuffix(outputFolder, "terraform-provider-google-beta") || strings.HasSuffix(outputFolder, "terraform-provider-google-nightly")) {
-		return
+		return nil
 	}
 
 	// Prevent adding copyright header to files with paths or names matching the strings below
@@ -823,7 +1049,7 @@ func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) {
 		}
 	}
 	if !shouldAddHeader {
-		return
+		return nil
 	}
 
 	for _, file := range ignoredFiles {
@@ -834,7 +1060,7 @@ func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) {
 		}
 	}
 	if !shouldAddHeader {
-		return
+		return nil
 	}
 
 	lang := languageFromFilename(target)
@@ -842,24 +1068,28 @@ func (t Terraform) addHashicorpCopyRightHeader(outputFolder, target string) {
 	// e.g. .sh where headers are functional
 	// Also, this guards against new filetypes being added and triggering build errors
 	if lang == "unsupported" {
-		return
+		return nil
 	}
 
 	// File is not ignored and is appropriate file type to add header to
 	copyrightHeader := []string{"Copyright IBM Corp. 2014, 2026", "SPDX-License-Identifier: MPL-2.0"}
-	header := commentBlock(copyrightHeader, lang)
+	header, err := commentBlock(copyrightHeader, lang)
+	if err != nil {
+		return fmt.Errorf("cannot add Hashicorp copy right to %s: %w", target, err)
+	}
 
 	targetFile := filepath.Join(outputFolder, target)
-	sourceByte, err := os.ReadFile(targetFile)
+	sourceByte, err := outputFS.ReadFile(targetFile)
 	if err != nil {
-		log.Fatalf("Cannot read file %s to add Hashicorp copy right: %s", targetFile, err)
+		return fmt.Errorf("cannot read file %s to add Hashicorp copy right: %w", targetFile, err)
 	}
 
 	sourceByte = google.Concat([]byte(header), sourceByte)
-	err = os.WriteFile(targetFile, sourceByte, 0644)
+	err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 	if err != nil {
-		log.Fatalf("Cannot write file %s to add Hashicorp copy right: %s", target, err)
+		return fmt.Errorf("cannot write file %s to add Hashicorp copy right: %w", target, err)
 	}
+	return nil
 }
 
 func expectedOutputFolder(outputFolder string) bool {
@@ -876,11 +1106,11 @@ func expectedOutputFolder(outputFolder string) bool {
 	return isExpected
 }
 
-func (t Terraform) replaceImportPath(outputFolder, target string) {
+func (t Terraform) replaceImportPath(outputFolder, target string) error {
 	targetFile := filepath.Join(outputFolder, target)
-	sourceByte, err := os.ReadFile(targetFile)
+	sourceByte, err := outputFS.ReadFile(targetFile)
 	if err != nil {
-		log.Fatalf("Cannot read file %s to replace import path: %s", targetFile, err)
+		return fmt.Errorf("cannot read file %s to replace import path: %w", targetFile, err)
 	}
 
 	data := string(sourceByte)
@@ -889,11 +1119,11 @@ func (t Terraform) replaceImportPath(outputFolder, target string) {
 	betaImportPath := ImportPathFromVersion("beta")
 
 	if strings.Contains(data, betaImportPath) {
-		log.Fatalf("Importing a package from module %s is not allowed in file %s. Please import a package from module %s.", betaImportPath, filepath.Base(target), gaImportPath)
+		return fmt.Errorf("importing a package from module %s is not allowed in file %s. Please import a package from module %s", betaImportPath, filepath.Base(target), gaImportPath)
 	}
 
 	if t.TargetVersionName == "ga" {
-		return
+		return nil
 	}
 
 	// Replace the import pathes in utility files
@@ -920,10 +1150,11 @@ func (t Terraform) replaceImportPath(outputFolder, target string) {
 		}
 	}
 
-	err = os.WriteFile(targetFile, sourceByte, 0644)
+	err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 	if err != nil {
-		log.Fatalf("Cannot write file %s to replace import path: %s", target, err)
+		return fmt.Errorf("cannot write file %s to replace import path: %w", target, err)
 	}
+	return nil
 }
 
 func (t Terraform) ProviderFromVersion() string {
@@ -981,7 +1212,7 @@ func (t *Terraform) generateResourcesForVersion(products []*api.Product) {
 	for _, productDefinition := range products {
 		service := strings.ToLower(productDefinition.Name)
 		for _, object := range productDefinition.Objects {
-			if object.Exclude || object.NotInVersion(productDefinition.VersionObjOrClosest(t.TargetVersionName)) {
+			if object.Exclude || object.IsEphemeral() || object.NotInVersion(productDefinition.VersionObjOrClosest(t.TargetVersionName)) {
 				continue
 			}
 
@@ -1013,7 +1244,7 @@ func (t *Terraform) generateResourcesForVersion(products []*api.Product) {
 
 // # Adapted from the method used in templating
 // # See: mmv1/compile/core.rb
-func commentBlock(text []string, lang string) string {
+func commentBlock(text []string, lang string) (string, error) {
 	var headers []string
 	switch lang {
 	case "python", "yaml":
@@ -1021,11 +1252,11 @@ func commentBlock(text []string, lang string) string {
 	case "go":
 		headers = commentText(text, "//")
 	default:
-		log.Fatalf("Unknown language for comment: %s", lang)
+		return "", fmt.Errorf("unknown language for comment: %s", lang)
 	}
 
 	headerString := strings.Join(headers, "\n")
-	return fmt.Sprintf("%s\n", headerString) // add trailing newline to returned value
+	return fmt.Sprintf("%s\n", headerString), nil // add trailing newline to returned value
 }
 
 func commentText(text []string, symbols string) []string {
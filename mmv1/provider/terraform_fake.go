@@ -0,0 +1,366 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generator for an in-memory fake of the APIs of the provider's resources.
+
+package provider
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// TerraformFake generates fakegcp, an in-memory fake of the Google Cloud
+// APIs the provider's resources can run their acceptance tests against. It
+// is generated into the provider, next to its services.
+type TerraformFake struct {
+	TargetVersionName string
+
+	Product *api.Product
+
+	StartTime time.Time
+
+	templateFS fs.FS
+}
+
+func NewTerraformFake(product *api.Product, versionName string, startTime time.Time, templateFS fs.FS) TerraformFake {
+	t := TerraformFake{
+		Product:           product,
+		TargetVersionName: versionName,
+		StartTime:         startTime,
+		templateFS:        templateFS,
+	}
+
+	if product != nil {
+		t.Product.ImportPath = ImportPathFromVersion(versionName)
+		for _, r := range t.Product.Objects {
+			r.ImportPath = ImportPathFromVersion(versionName)
+		}
+	}
+
+	return t
+}
+
+// FolderName is the folder of the provider fakegcp is generated into.
+func (f TerraformFake) FolderName() string {
+	if f.TargetVersionName == "ga" {
+		return RESOURCE_DIRECTORY_GA
+	}
+	return "google-" + f.TargetVersionName
+}
+
+// ImportPath is the import path of the provider fakegcp is generated into.
+func (f TerraformFake) ImportPath() string {
+	return ImportPathFromVersion(f.TargetVersionName)
+}
+
+func (f TerraformFake) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	for _, object := range f.Product.Objects {
+		object.ExcludeIfNotInVersion(f.Product.Version)
+
+		if resourceToGenerate != "" && object.Name != resourceToGenerate {
+			log.Printf("Excluding %s per user request", object.Name)
+			continue
+		}
+
+		if err := f.GenerateObject(*object, outputFolder, generateCode, generateDocs); err != nil {
+			return fmt.Errorf("error generating %s: %w", object.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f TerraformFake) GenerateObject(object api.Resource, outputFolder string, generateCode, generateDocs bool) error {
+	if !generateCode {
+		return nil
+	}
+	route, ok := NewFakeRoute(object)
+	if !ok {
+		return nil
+	}
+
+	return generateTracked(object, outputFolder, f.TargetVersionName, f.templateFS, func(templateData *TemplateData) error {
+		targetFolder := path.Join(outputFolder, f.FolderName(), "fakegcp")
+		if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			return fmt.Errorf("error creating parent directory %v: %w", targetFolder, err)
+		}
+
+		name := google.Underscore(object.ResourceName())
+		templatePath := "templates/fakegcp/route.go.tmpl"
+		if err := templateData.GenerateFile(path.Join(targetFolder, name+".go"), templatePath, route, true, templatePath); err != nil {
+			return err
+		}
+		templatePath = "templates/fakegcp/route_test.go.tmpl"
+		return templateData.GenerateFile(path.Join(targetFolder, name+"_test.go"), templatePath, route, true, templatePath)
+	}, generateCode, generateDocs)
+}
+
+// CopyCommonFiles copies the handwritten runtime of the fake.
+func (f TerraformFake) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
+	if !generateCode || f.Product != nil {
+		return nil
+	}
+
+	log.Printf("Copying common files for fakegcp.")
+	return copyDir("third_party/fakegcp", filepath.Join(outputFolder, f.FolderName(), "fakegcp"), func(src string) bool {
+		return strings.HasSuffix(src, ".tmpl")
+	})
+}
+
+// CompileCommonFiles generates the command serving the fake.
+func (f TerraformFake) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
+	if f.Product != nil {
+		return nil
+	}
+
+	targetFolder := filepath.Join(outputFolder, f.FolderName(), "fakegcp", "cmd", "fakegcp")
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating output directory %v: %w", targetFolder, err)
+	}
+	templatePath := "third_party/fakegcp/cmd/fakegcp/main.go.tmpl"
+	templateData := NewTemplateData(outputFolder, f.TargetVersionName, f.templateFS)
+	return templateData.GenerateFile(filepath.Join(targetFolder, "main.go"), templatePath, f, true, templatePath)
+}
+
+// FakeRoute is how fakegcp serves the API of a resource. Its fields mirror
+// the fakegcp.Route the generated code registers.
+type FakeRoute struct {
+	Resource       api.Resource
+	Product        string
+	EndpointEnvVar string
+
+	CollectionPath string
+	SelfLinkPath   string
+	CreatePath     string
+
+	CreateVerb string
+	UpdateVerb string
+	DeleteVerb string
+
+	CreateIdParam string
+	UpdateMask    bool
+	AsyncActions  []string
+	CollectionKey string
+
+	OutputFields []FakeOutputField
+
+	// TestValues are the values of the fields of the self link in the
+	// generated test.
+	TestValues map[string]string
+	// TestUpdateField is the field changed by the update of the generated
+	// test, if the resource has one the fake can update.
+	TestUpdateField string
+}
+
+// FakeOutputField is an output-only field filled by the fake, with the
+// fakegcp.FieldKind filling it.
+type FakeOutputField struct {
+	Name  string
+	Kind  string
+	Value string
+}
+
+// fakeReadyStates are the values preferred for output-only enums, such as
+// `state`, so that resources waiting for a state don't time out.
+var fakeReadyStates = []string{"READY", "ACTIVE", "RUNNING", "ENABLED", "AVAILABLE", "SUCCEEDED", "UP", "DONE"}
+
+// NewFakeRoute returns the route of a resource, or false if fakegcp can't
+// serve it: excluded and ephemeral resources, resources nested in another
+// object, and resources with absolute URLs or URLs rewritten by custom code.
+func NewFakeRoute(r api.Resource) (FakeRoute, bool) {
+	if r.IsExcluded() || r.IsEphemeral() || r.NestedQuery != nil || r.ExcludeRead {
+		return FakeRoute{}, false
+	}
+	collection, _, _ := strings.Cut(r.BaseUrl, "?")
+	selfLink, selfLinkQuery, _ := strings.Cut(r.SelfLinkUri(), "?")
+	createPath, createQuery, _ := strings.Cut(r.CreateUri(), "?")
+	for _, p := range []string{collection, selfLink, createPath} {
+		// Custom code fills placeholders such as PRE_CREATE_REPLACE_ME.
+		if strings.Contains(p, "://") || strings.Contains(p, "REPLACE_ME") {
+			return FakeRoute{}, false
+		}
+	}
+	// Objects identified by query parameters, such as rules fetched with
+	// getRule?priority={{priority}}, can't be stored by path.
+	if strings.Contains(selfLinkQuery, "{{") {
+		return FakeRoute{}, false
+	}
+	// Resources "deleted" by updating them, such as billing info, can't be
+	// told apart from updates.
+	if r.DeleteVerb == "PUT" || r.DeleteVerb == "PATCH" {
+		return FakeRoute{}, false
+	}
+	// Objects created by calling a method of another object, such as
+	// :subscribe, are stored where the API decides.
+	if strings.Contains(createPath[strings.LastIndex(createPath, "/")+1:], ":") {
+		return FakeRoute{}, false
+	}
+	singleField := strings.Count(selfLink, "{{") == 1 && strings.HasPrefix(selfLink, "{{") && strings.HasSuffix(selfLink, "}}")
+	switch {
+	case singleField:
+		// The API names the object in the collection it's created in.
+		collection = createPath
+		if strings.HasSuffix(createPath, "}}") {
+			collection = createPath[:strings.LastIndex(createPath, "/{{")]
+		}
+	case !strings.HasSuffix(selfLink, "}}"):
+		// Singletons, such as projects/{{project}}/settings, have no
+		// collection.
+		collection = selfLink
+	case !strings.HasPrefix(selfLink, collection+"/"):
+		// Some collections leave out the parent of their objects, as with
+		// base_url: dnsZones for {{org_id}}/dnsZones/{{dns_zone_id}}.
+		collection = selfLink[:strings.LastIndex(selfLink, "/{{")]
+	}
+
+	route := FakeRoute{
+		Resource:       r,
+		Product:        strings.ToLower(r.ProductMetadata.Name),
+		EndpointEnvVar: fmt.Sprintf("GOOGLE_%s_CUSTOM_ENDPOINT", strings.ToUpper(google.Underscore(r.ProductMetadata.Name))),
+		CollectionPath: collection,
+		SelfLinkPath:   selfLink,
+		CreatePath:     createPath,
+		CreateVerb:     r.CreateVerb,
+		DeleteVerb:     r.DeleteVerb,
+		UpdateMask:     r.UpdateMask,
+		CollectionKey:  r.CollectionUrlKey,
+		TestValues:     make(map[string]string),
+	}
+	if !r.Immutable {
+		route.UpdateVerb = r.UpdateVerb
+	}
+
+	selfLinkFields := r.ExtractIdentifiers(selfLink)
+	createFields := r.ExtractIdentifiers(createPath)
+	if query, err := url.ParseQuery(createQuery); err == nil {
+		for param, values := range query {
+			fields := r.ExtractIdentifiers(strings.Join(values, ""))
+			if len(fields) == 1 && slices.Contains(selfLinkFields, fields[0]) && !slices.Contains(createFields, fields[0]) {
+				route.CreateIdParam = param
+			}
+		}
+	}
+
+	if async := r.GetAsync(); async != nil && async.IsA("OpAsync") {
+		for _, action := range []string{"create", "update", "delete"} {
+			if async.Allow(action) {
+				route.AsyncActions = append(route.AsyncActions, action)
+			}
+		}
+	}
+
+	for _, p := range []string{collection, createPath, selfLink} {
+		for _, field := range r.ExtractIdentifiers(p) {
+			route.TestValues[field] = "my-" + strings.ReplaceAll(field, "_", "-")
+		}
+	}
+	// Self links made of a single field, such as {{name}}, hold the whole
+	// name the API assigns in the collection.
+	if singleField {
+		field := selfLinkFields[0]
+		name := renderFakePath(createPath, route.TestValues)
+		if !strings.HasSuffix(createPath, "}}") {
+			name += "/" + route.TestValues[field]
+		}
+		route.TestValues[field] = name
+	}
+
+	props := r.AllUserProperties()
+	for _, p := range props {
+		if !p.Output || p.UrlParamOnly || p.IgnoreRead || p.FlattenObject || strings.Contains(p.ApiName, ".") {
+			continue
+		}
+		// Terraform-only views of user fields, such as effective_labels,
+		// share the name of the field in the API.
+		if slices.ContainsFunc(props, func(o *api.Type) bool { return !o.Output && o.ApiName == p.ApiName }) {
+			continue
+		}
+		if f, ok := fakeOutputField(p); ok {
+			route.OutputFields = append(route.OutputFields, f)
+		}
+	}
+
+	if route.UpdateVerb != "" {
+		for _, p := range props {
+			if p.Output || p.Immutable || p.UrlParamOnly || p.IgnoreRead || p.UpdateUrl != "" || !p.IsA("String") ||
+				p.ApiName == "name" || strings.Contains(p.ApiName, ".") || slices.Contains(selfLinkFields, google.Underscore(p.Name)) {
+				continue
+			}
+			route.TestUpdateField = p.ApiName
+			break
+		}
+	}
+
+	return route, true
+}
+
+// renderFakePath replaces the fields of a path by their values.
+func renderFakePath(path string, values map[string]string) string {
+	for field, value := range values {
+		path = strings.ReplaceAll(path, "{{"+field+"}}", value)
+		path = strings.ReplaceAll(path, "{{%"+field+"}}", value)
+	}
+	return path
+}
+
+// fakeOutputField returns how the fake fills an output-only field, or false
+// if it doesn't fill it.
+func fakeOutputField(p *api.Type) (FakeOutputField, bool) {
+	f := FakeOutputField{Name: p.ApiName, Kind: "FieldValue"}
+	switch {
+	case p.ApiName == "name":
+		f.Kind = "FieldName"
+	case p.ApiName == "selfLink" || p.ApiName == "selfLinkWithId":
+		f.Kind = "FieldSelfLink"
+	case p.IsA("Time") || (p.IsA("String") && strings.HasSuffix(p.ApiName, "Time")):
+		f.Kind = "FieldTime"
+	case p.ApiName == "id" || p.ApiName == "uid":
+		f.Kind = "FieldId"
+	case p.IsA("Enum"):
+		if len(p.EnumValues) == 0 {
+			return f, false
+		}
+		value := p.EnumValues[0]
+		for _, v := range fakeReadyStates {
+			if slices.Contains(p.EnumValues, v) {
+				value = v
+				break
+			}
+		}
+		f.Value = fmt.Sprintf("%q", value)
+	case p.IsA("String") && (p.ApiName == "state" || p.ApiName == "status"):
+		f.Value = fmt.Sprintf("%q", fakeReadyStates[0])
+	case p.IsA("String") || p.IsA("Fingerprint"):
+		f.Value = fmt.Sprintf("%q", "fake")
+	case p.IsA("Integer"):
+		f.Value = "1"
+	case p.IsA("Double"):
+		f.Value = "1.0"
+	case p.IsA("Boolean"):
+		f.Value = "false"
+	default:
+		return f, false
+	}
+	return f, true
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+)
+
+func TestNewFakeRoute(t *testing.T) {
+	t.Parallel()
+
+	p := &api.Product{Name: "AlloyDB"}
+
+	cases := []struct {
+		description string
+		obj         api.Resource
+		want        FakeRoute
+		wantOk      bool
+	}{
+		{
+			description: "create with an id parameter",
+			obj: api.Resource{
+				Name:             "Instance",
+				BaseUrl:          "{{cluster}}/instances?instanceId={{instance_id}}",
+				SelfLink:         "{{cluster}}/instances/{{instance_id}}",
+				CreateVerb:       "POST",
+				UpdateVerb:       "PATCH",
+				DeleteVerb:       "DELETE",
+				UpdateMask:       true,
+				CollectionUrlKey: "instances",
+				Async:            &api.Async{Type: "OpAsync", Actions: []string{"create", "delete"}},
+				Properties: []*api.Type{
+					{Name: "name", ApiName: "name", Type: "String", Output: true},
+					{Name: "createTime", ApiName: "createTime", Type: "Time", Output: true},
+					{Name: "uid", ApiName: "uid", Type: "String", Output: true},
+					{Name: "state", ApiName: "state", Type: "Enum", Output: true, EnumValues: []string{"CREATING", "READY"}},
+					{Name: "labels", ApiName: "labels", Type: "KeyValueLabels"},
+					{Name: "displayName", ApiName: "displayName", Type: "String"},
+				},
+				Parameters: []*api.Type{
+					{Name: "cluster", ApiName: "cluster", Type: "String", UrlParamOnly: true, Immutable: true},
+					{Name: "instanceId", ApiName: "instanceId", Type: "String", UrlParamOnly: true, Immutable: true},
+				},
+				ProductMetadata: p,
+			},
+			want: FakeRoute{
+				Product:        "alloydb",
+				EndpointEnvVar: "GOOGLE_ALLOY_DB_CUSTOM_ENDPOINT",
+				CollectionPath: "{{cluster}}/instances",
+				SelfLinkPath:   "{{cluster}}/instances/{{instance_id}}",
+				CreatePath:     "{{cluster}}/instances",
+				CreateVerb:     "POST",
+				UpdateVerb:     "PATCH",
+				DeleteVerb:     "DELETE",
+				CreateIdParam:  "instanceId",
+				UpdateMask:     true,
+				AsyncActions:   []string{"create", "delete"},
+				CollectionKey:  "instances",
+				OutputFields: []FakeOutputField{
+					{Name: "name", Kind: "FieldName"},
+					{Name: "createTime", Kind: "FieldTime"},
+					{Name: "uid", Kind: "FieldId"},
+					{Name: "state", Kind: "FieldValue", Value: `"READY"`},
+				},
+				TestValues:      map[string]string{"cluster": "my-cluster", "instance_id": "my-instance-id"},
+				TestUpdateField: "displayName",
+			},
+			wantOk: true,
+		},
+		{
+			description: "singleton",
+			obj: api.Resource{
+				Name:            "Settings",
+				BaseUrl:         "projects/{{project}}",
+				SelfLink:        "projects/{{project}}/settings",
+				CreateVerb:      "PATCH",
+				CreateUrl:       "projects/{{project}}/settings",
+				UpdateVerb:      "PATCH",
+				DeleteVerb:      "DELETE",
+				Immutable:       true,
+				ProductMetadata: p,
+			},
+			want: FakeRoute{
+				Product:        "alloydb",
+				EndpointEnvVar: "GOOGLE_ALLOY_DB_CUSTOM_ENDPOINT",
+				CollectionPath: "projects/{{project}}/settings",
+				SelfLinkPath:   "projects/{{project}}/settings",
+				CreatePath:     "projects/{{project}}/settings",
+				CreateVerb:     "PATCH",
+				DeleteVerb:     "DELETE",
+				TestValues:     map[string]string{"project": "my-project"},
+			},
+			wantOk: true,
+		},
+		{
+			description: "name assigned by the API",
+			obj: api.Resource{
+				Name:            "Feed",
+				BaseUrl:         "projects/{{project}}/feeds",
+				SelfLink:        "{{name}}",
+				CreateVerb:      "POST",
+				DeleteVerb:      "DELETE",
+				Immutable:       true,
+				ProductMetadata: p,
+			},
+			want: FakeRoute{
+				Product:        "alloydb",
+				EndpointEnvVar: "GOOGLE_ALLOY_DB_CUSTOM_ENDPOINT",
+				CollectionPath: "projects/{{project}}/feeds",
+				SelfLinkPath:   "{{name}}",
+				CreatePath:     "projects/{{project}}/feeds",
+				CreateVerb:     "POST",
+				DeleteVerb:     "DELETE",
+				TestValues:     map[string]string{"project": "my-project", "name": "projects/my-project/feeds/my-name"},
+			},
+			wantOk: true,
+		},
+		{
+			description: "excluded",
+			obj: api.Resource{
+				Name:            "Instance",
+				BaseUrl:         "instances",
+				Exclude:         true,
+				ProductMetadata: p,
+			},
+		},
+		{
+			description: "url rewritten by custom code",
+			obj: api.Resource{
+				Name:            "Snapshot",
+				BaseUrl:         "projects/{{project}}/global/snapshots",
+				CreateUrl:       "PRE_CREATE_REPLACE_ME/createSnapshot",
+				CreateVerb:      "POST",
+				ProductMetadata: p,
+			},
+		},
+		{
+			description: "identified by a query parameter",
+			obj: api.Resource{
+				Name:            "Rule",
+				BaseUrl:         "projects/{{project}}/global/policies/{{policy}}",
+				SelfLink:        "projects/{{project}}/global/policies/{{policy}}/getRule?priority={{priority}}",
+				CreateVerb:      "POST",
+				ProductMetadata: p,
+			},
+		},
+		{
+			description: "deleted by an update",
+			obj: api.Resource{
+				Name:            "BillingInfo",
+				BaseUrl:         "projects/{{project}}/billingInfo",
+				CreateVerb:      "PUT",
+				UpdateVerb:      "PUT",
+				DeleteVerb:      "PUT",
+				ProductMetadata: p,
+			},
+		},
+		{
+			description: "created by a method call",
+			obj: api.Resource{
+				Name:            "Subscription",
+				BaseUrl:         "projects/{{project}}/subscriptions",
+				CreateUrl:       "{{data_exchange}}:subscribe",
+				CreateVerb:      "POST",
+				ProductMetadata: p,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := NewFakeRoute(tc.obj)
+			if ok != tc.wantOk {
+				t.Fatalf("NewFakeRoute() ok = %v, want %v", ok, tc.wantOk)
+			}
+			if !ok {
+				return
+			}
+			got.Resource = api.Resource{}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("NewFakeRoute() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
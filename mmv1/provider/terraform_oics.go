@@ -47,11 +47,11 @@ func NewTerraformOiCS(product *api.Product, versionName string, startTime time.T
 	return toics
 }
 
-func (toics TerraformOiCS) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
-	toics.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs)
+func (toics TerraformOiCS) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	return toics.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs)
 }
 
-func (toics TerraformOiCS) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (toics TerraformOiCS) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	for _, object := range toics.Product.Objects {
 		object.ExcludeIfNotInVersion(toics.Product.Version)
 
@@ -60,26 +60,32 @@ func (toics TerraformOiCS) GenerateObjects(outputFolder, resourceToGenerate stri
 			continue
 		}
 
-		toics.GenerateObject(*object, outputFolder, toics.TargetVersionName, generateCode, generateDocs)
+		if err := toics.GenerateObject(*object, outputFolder, toics.TargetVersionName, generateCode, generateDocs); err != nil {
+			return fmt.Errorf("error generating %s: %w", object.Name, err)
+		}
 	}
+	return nil
 }
 
-func (toics TerraformOiCS) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
-	templateData := NewTemplateData(outputFolder, toics.TargetVersionName, toics.templateFS)
+func (toics TerraformOiCS) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	return generateTracked(object, outputFolder, toics.TargetVersionName, toics.templateFS, func(templateData *TemplateData) error {
+		toics.templateFS = templateData.templateFS
 
-	if !object.IsExcluded() {
+		if object.IsExcluded() {
+			return nil
+		}
 		log.Printf("Generating %s resource", object.Name)
-		toics.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
-	}
+		return toics.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
+	}, generateCode, generateDocs)
 }
 
-func (toics TerraformOiCS) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (toics TerraformOiCS) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 
 	if !generateDocs {
-		return
+		return nil
 	}
 
 	for _, sample := range object.TestSamples() {
@@ -92,7 +98,7 @@ func (toics TerraformOiCS) GenerateResource(object api.Resource, templateData Te
 
 			targetFolder := path.Join(outputFolder, step.Name)
 
-			if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 				log.Println(fmt.Errorf("error creating oics example directory %v: %v", targetFolder, err))
 			}
 
@@ -100,33 +106,42 @@ func (toics TerraformOiCS) GenerateResource(object api.Resource, templateData Te
 			oicsExampleTemplates := []string{
 				oicsExampleTemplatePath,
 			}
-			templateData.GenerateFile(path.Join(targetFolder, "main.tf"), oicsExampleTemplatePath, step, false, oicsExampleTemplates...)
+			if err := templateData.GenerateFile(path.Join(targetFolder, "main.tf"), oicsExampleTemplatePath, step, false, oicsExampleTemplates...); err != nil {
+				return err
+			}
 
 			tutorialTemplatePath := "templates/terraform/samples/base_configs/tutorial.md.tmpl"
 			tutorialTemplates := []string{
 				tutorialTemplatePath,
 			}
-			templateData.GenerateFile(path.Join(targetFolder, "tutorial.md"), tutorialTemplatePath, step, false, tutorialTemplates...)
+			if err := templateData.GenerateFile(path.Join(targetFolder, "tutorial.md"), tutorialTemplatePath, step, false, tutorialTemplates...); err != nil {
+				return err
+			}
 
 			backingTemplatePath := "templates/terraform/samples/base_configs/example_backing_file.tf.tmpl"
 			backingTemplates := []string{
 				backingTemplatePath,
 			}
-			templateData.GenerateFile(path.Join(targetFolder, "backing_file.tf"), backingTemplatePath, step, false, backingTemplates...)
+			if err := templateData.GenerateFile(path.Join(targetFolder, "backing_file.tf"), backingTemplatePath, step, false, backingTemplates...); err != nil {
+				return err
+			}
 
 			motdTemplatePath := "templates/terraform/samples/static/motd.tmpl"
 			motdTemplates := []string{
 				motdTemplatePath,
 			}
-			templateData.GenerateFile(path.Join(targetFolder, "motd"), motdTemplatePath, step, false, motdTemplates...)
+			if err := templateData.GenerateFile(path.Join(targetFolder, "motd"), motdTemplatePath, step, false, motdTemplates...); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (toics TerraformOiCS) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) {
-
+func (toics TerraformOiCS) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
+	return nil
 }
 
-func (toics TerraformOiCS) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) {
-
+func (toics TerraformOiCS) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
+	return nil
 }
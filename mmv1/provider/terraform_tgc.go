@@ -71,17 +71,17 @@ func (tgc TerraformGoogleConversion) Compiler() string {
 	return "terraformgoogleconversion-codegen"
 }
 
-func (tgc TerraformGoogleConversion) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	// Temporary shim to generate the missing resources directory. Can be removed
 	// once the folder exists downstream.
 	resourcesFolder := path.Join(outputFolder, "converters/google/resources")
-	if err := os.MkdirAll(resourcesFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(resourcesFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", resourcesFolder, err))
 	}
-	tgc.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs)
+	return tgc.GenerateObjects(outputFolder, resourceToGenerate, generateCode, generateDocs)
 }
 
-func (tgc TerraformGoogleConversion) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) GenerateObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	for _, object := range tgc.Product.Objects {
 		object.ExcludeIfNotInVersion(tgc.Product.Version)
 
@@ -90,58 +90,67 @@ func (tgc TerraformGoogleConversion) GenerateObjects(outputFolder, resourceToGen
 			continue
 		}
 
-		tgc.GenerateObject(*object, outputFolder, tgc.TargetVersionName, generateCode, generateDocs)
+		if err := tgc.GenerateObject(*object, outputFolder, tgc.TargetVersionName, generateCode, generateDocs); err != nil {
+			return fmt.Errorf("error generating %s: %w", object.Name, err)
+		}
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversion) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	if object.ExcludeTgc {
 		log.Printf("Skipping fine-grained resource %s", object.Name)
-		return
+		return nil
+	}
+	if object.IsEphemeral() {
+		log.Printf("Skipping ephemeral resource %s", object.Name)
+		return nil
 	}
 
-	templateData := NewTemplateData(outputFolder, tgc.TargetVersionName, tgc.templateFS)
-
-	if !object.IsExcluded() {
-		tgc.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
+	return generateTracked(object, outputFolder, tgc.TargetVersionName, tgc.templateFS, func(templateData *TemplateData) error {
+		if !object.IsExcluded() {
+			if err := tgc.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
 
-		if generateCode {
-			// tgc.GenerateResourceTests(object, *templateData, outputFolder)
-			// tgc.GenerateResourceSweeper(object, *templateData, outputFolder)
+			if generateCode {
+				// tgc.GenerateResourceTests(object, *templateData, outputFolder)
+				// tgc.GenerateResourceSweeper(object, *templateData, outputFolder)
+			}
 		}
-	}
 
-	// if iam_policy is not defined or excluded, don't generate it
-	if object.IamPolicy == nil || object.IamPolicy.Exclude {
-		return
-	}
+		// if iam_policy is not defined or excluded, don't generate it
+		if object.IamPolicy == nil || object.IamPolicy.Exclude {
+			return nil
+		}
 
-	tgc.GenerateIamPolicy(object, *templateData, outputFolder, generateCode, generateDocs)
+		return tgc.GenerateIamPolicy(object, *templateData, outputFolder, generateCode, generateDocs)
+	}, generateCode, generateDocs)
 }
 
-func (tgc TerraformGoogleConversion) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	productName := tgc.Product.ApiName
 	targetFolder := path.Join(outputFolder, "converters/google/resources/services", productName)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 
 	templatePath := "templates/tgc/resource_converter.go.tmpl"
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_%s.go", productName, google.Underscore(object.Name)))
-	templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object)
+	return templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object)
 }
 
 // Generate the IAM policy for this object. This is used to query and test
 // IAM policies separately from the resource itself
 // Docs are generated for the terraform provider, not here.
-func (tgc TerraformGoogleConversion) GenerateIamPolicy(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) GenerateIamPolicy(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	if !generateCode || object.IamPolicy.ExcludeTgc {
-		return
+		return nil
 	}
 
 	productName := tgc.Product.ApiName
 	targetFolder := path.Join(outputFolder, "converters/google/resources/services", productName)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 
@@ -151,13 +160,14 @@ func (tgc TerraformGoogleConversion) GenerateIamPolicy(object api.Resource, temp
 	}
 
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_%s_iam.go", productName, name))
-	templateData.GenerateTGCIamResourceFile(targetFilePath, object)
+	if err := templateData.GenerateTGCIamResourceFile(targetFilePath, object); err != nil {
+		return err
+	}
 
 	targetFilePath = path.Join(targetFolder, fmt.Sprintf("iam_%s_%s.go", productName, name))
-	templateData.GenerateIamPolicyFile(targetFilePath, object)
-
 	// Don't generate tests - we can rely on the terraform provider
 	//  to test these.
+	return templateData.GenerateIamPolicyFile(targetFilePath, object)
 }
 
 // Generates the list of resources
@@ -184,7 +194,7 @@ func (tgc *TerraformGoogleConversion) generateCaiIamResources(products []*api.Pr
 	}
 }
 
-func (tgc TerraformGoogleConversion) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) {
+func (tgc TerraformGoogleConversion) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
 	log.Printf("Compiling common files for tgc.")
 
 	templateData := NewTemplateData(outputFolder, tgc.TargetVersionName, tgc.templateFS)
@@ -215,43 +225,56 @@ func (tgc TerraformGoogleConversion) CompileCommonFiles(outputFolder string, pro
 				filteredFiles[target] = source
 			}
 		}
-		tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
-	} else {
-		// Shared compilation
-		tgc.generateCaiIamResources(products)
-		tgc.NonDefinedTests = retrieveFullManifestOfNonDefinedTests(tgc.templateFS)
+		return tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
+	}
 
-		files := retrieveFullListOfTestFiles(tgc.templateFS)
-		for _, file := range files {
-			tgc.Tests = append(tgc.Tests, strings.Split(file, ".")[0])
-		}
-		tgc.Tests = slices.Compact(tgc.Tests)
+	// Shared compilation
+	tgc.generateCaiIamResources(products)
+	nonDefinedTests, err := retrieveFullManifestOfNonDefinedTests(tgc.templateFS)
+	if err != nil {
+		return err
+	}
+	tgc.NonDefinedTests = nonDefinedTests
 
-		testSource := make(map[string]string)
-		for target, source := range retrieveTestSourceCodeWithLocation(tgc.templateFS, ".tmpl") {
-			target := strings.Replace(target, "go.tmpl", "go", 1)
-			testSource[target] = source
-		}
-		tgc.CompileFileList(outputFolder, testSource, *templateData, products)
+	files, err := retrieveFullListOfTestFiles(tgc.templateFS)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		tgc.Tests = append(tgc.Tests, strings.Split(file, ".")[0])
+	}
+	tgc.Tests = slices.Compact(tgc.Tests)
 
-		for target, source := range resourceConverters {
-			if !strings.Contains(target, "/services/") {
-				filteredFiles[target] = source
-			}
+	testSourceTemplates, err := retrieveTestSourceCodeWithLocation(tgc.templateFS, ".tmpl")
+	if err != nil {
+		return err
+	}
+	testSource := make(map[string]string)
+	for target, source := range testSourceTemplates {
+		target := strings.Replace(target, "go.tmpl", "go", 1)
+		testSource[target] = source
+	}
+	if err := tgc.CompileFileList(outputFolder, testSource, *templateData, products); err != nil {
+		return err
+	}
+
+	for target, source := range resourceConverters {
+		if !strings.Contains(target, "/services/") {
+			filteredFiles[target] = source
 		}
-		tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
 	}
+	return tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
 }
 
-func (tgc TerraformGoogleConversion) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) {
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+func (tgc TerraformGoogleConversion) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) error {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 
@@ -261,23 +284,35 @@ func (tgc TerraformGoogleConversion) CompileFileList(outputFolder string, files
 
 		formatFile := filepath.Ext(targetFile) == ".go"
 
-		fileTemplate.GenerateFile(targetFile, source, tgc, formatFile, templates...)
-		tgc.replaceImportPath(outputFolder, target)
+		if err := fileTemplate.GenerateFile(targetFile, source, tgc, formatFile, templates...); err != nil {
+			return err
+		}
+		if err := tgc.replaceImportPath(outputFolder, target); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func retrieveFullManifestOfNonDefinedTests(fs fs.FS) []string {
+func retrieveFullManifestOfNonDefinedTests(fs fs.FS) ([]string, error) {
 	var tests []string
 	fileMap := make(map[string]bool)
 
-	files := retrieveFullListOfTestFiles(fs)
+	files, err := retrieveFullListOfTestFiles(fs)
+	if err != nil {
+		return nil, err
+	}
 	for _, file := range files {
 		tests = append(tests, strings.Split(file, ".")[0])
 		fileMap[file] = true
 	}
 	tests = slices.Compact(tests)
 
-	nonDefinedTests := google.Diff(tests, retrieveListOfManuallyDefinedTests(fs))
+	manuallyDefinedTests, err := retrieveListOfManuallyDefinedTests(fs)
+	if err != nil {
+		return nil, err
+	}
+	nonDefinedTests := google.Diff(tests, manuallyDefinedTests)
 	nonDefinedTests = google.Reject(nonDefinedTests, func(file string) bool {
 		return strings.HasSuffix(file, "_without_default_project")
 	})
@@ -285,52 +320,55 @@ func retrieveFullManifestOfNonDefinedTests(fs fs.FS) []string {
 	for _, test := range nonDefinedTests {
 		_, ok := fileMap[fmt.Sprintf("%s.json", test)]
 		if !ok {
-			log.Fatalf("test file named %s.json expected but found none", test)
+			return nil, fmt.Errorf("test file named %s.json expected but found none", test)
 		}
 
 		_, ok = fileMap[fmt.Sprintf("%s.tf", test)]
 		if !ok {
-			log.Fatalf("test file named %s.tf expected but found none", test)
+			return nil, fmt.Errorf("test file named %s.tf expected but found none", test)
 		}
 	}
 
-	return nonDefinedTests
+	return nonDefinedTests, nil
 }
 
 // Gets all of the test files in the folder third_party/tgc/tests/data
-func retrieveFullListOfTestFiles(fsys fs.FS) []string {
+func retrieveFullListOfTestFiles(fsys fs.FS) ([]string, error) {
 	var testFiles []string
 
 	files, err := fs.ReadDir(fsys, "third_party/tgc/tests/data")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	for _, file := range files {
 		testFiles = append(testFiles, file.Name())
 	}
 	slices.Sort(testFiles)
 
-	return testFiles
+	return testFiles, nil
 }
 
 // Gets all of files in the folder third_party/tgc/tests/data
-func retrieveFullListOfTestTilesWithLocation(fs fs.FS) map[string]string {
+func retrieveFullListOfTestTilesWithLocation(fs fs.FS) (map[string]string, error) {
 	testFiles := make(map[string]string)
-	files := retrieveFullListOfTestFiles(fs)
+	files, err := retrieveFullListOfTestFiles(fs)
+	if err != nil {
+		return nil, err
+	}
 	for _, file := range files {
 		target := fmt.Sprintf("testdata/templates/%s", file)
 		source := fmt.Sprintf("third_party/tgc/tests/data/%s", file)
 		testFiles[target] = source
 	}
-	return testFiles
+	return testFiles, nil
 }
 
-func retrieveTestSourceCodeWithLocation(fsys fs.FS, suffix string) map[string]string {
+func retrieveTestSourceCodeWithLocation(fsys fs.FS, suffix string) (map[string]string, error) {
 	var fileNames []string
 	path := "third_party/tgc/tests/source"
 	files, err := fs.ReadDir(fsys, path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	for _, file := range files {
@@ -347,20 +385,26 @@ func retrieveTestSourceCodeWithLocation(fsys fs.FS, suffix string) map[string]st
 		source := fmt.Sprintf("%s/%s", path, file)
 		testSource[target] = source
 	}
-	return testSource
+	return testSource, nil
 }
 
-func retrieveListOfManuallyDefinedTests(fs fs.FS) []string {
-	m1 := retrieveListOfManuallyDefinedTestsFromFile(fs, "third_party/tgc/tests/source/cli_test.go.tmpl")
-	m2 := retrieveListOfManuallyDefinedTestsFromFile(fs, "third_party/tgc/tests/source/read_test.go.tmpl")
-	return google.Concat(m1, m2)
+func retrieveListOfManuallyDefinedTests(fs fs.FS) ([]string, error) {
+	m1, err := retrieveListOfManuallyDefinedTestsFromFile(fs, "third_party/tgc/tests/source/cli_test.go.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	m2, err := retrieveListOfManuallyDefinedTestsFromFile(fs, "third_party/tgc/tests/source/read_test.go.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	return google.Concat(m1, m2), nil
 }
 
 // Reads the content of the file and then finds all of the tests in the contents
-func retrieveListOfManuallyDefinedTestsFromFile(fsys fs.FS, file string) []string {
+func retrieveListOfManuallyDefinedTestsFromFile(fsys fs.FS, file string) ([]string, error) {
 	data, err := fs.ReadFile(fsys, file)
 	if err != nil {
-		log.Fatalf("Cannot open the file: %v", file)
+		return nil, fmt.Errorf("cannot open the file %v: %w", file, err)
 	}
 
 	var tests []string
@@ -369,14 +413,14 @@ func retrieveListOfManuallyDefinedTestsFromFile(fsys fs.FS, file string) []strin
 	for _, testWithName := range matches {
 		tests = append(tests, testWithName[1])
 	}
-	return tests
+	return tests, nil
 }
 
-func (tgc TerraformGoogleConversion) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversion) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
 	log.Printf("Copying common files for tgc.")
 
 	if !generateCode {
-		return
+		return nil
 	}
 
 	resourceConverters := map[string]string{
@@ -483,61 +527,77 @@ func (tgc TerraformGoogleConversion) CopyCommonFiles(outputFolder string, genera
 		}
 	} else {
 		// Shared files
-		tgc.CopyFileList(outputFolder, retrieveFullListOfTestTilesWithLocation(tgc.templateFS))
-		tgc.CopyFileList(outputFolder, retrieveTestSourceCodeWithLocation(tgc.templateFS, ".go"))
+		testFiles, err := retrieveFullListOfTestTilesWithLocation(tgc.templateFS)
+		if err != nil {
+			return err
+		}
+		if err := tgc.CopyFileList(outputFolder, testFiles); err != nil {
+			return err
+		}
+		testSource, err := retrieveTestSourceCodeWithLocation(tgc.templateFS, ".go")
+		if err != nil {
+			return err
+		}
+		if err := tgc.CopyFileList(outputFolder, testSource); err != nil {
+			return err
+		}
 		for target, source := range resourceConverters {
 			if !strings.Contains(target, "/services/") {
 				filteredFiles[target] = source
 			}
 		}
 	}
-	tgc.CopyFileList(outputFolder, filteredFiles)
+	return tgc.CopyFileList(outputFolder, filteredFiles)
 }
 
-func (tgc TerraformGoogleConversion) CopyFileList(outputFolder string, files map[string]string) {
+func (tgc TerraformGoogleConversion) CopyFileList(outputFolder string, files map[string]string) error {
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
 
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 		// If we've modified a file since starting an MM run, it's a reasonable
 		// assumption that it was this run that modified it.
-		if info, err := os.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && tgc.StartTime.Before(info.ModTime()) {
-			log.Fatalf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
+		if info, err := outputFS.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && tgc.StartTime.Before(info.ModTime()) {
+			return fmt.Errorf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
 		}
 
 		sourceByte, err := fs.ReadFile(tgc.templateFS, source)
 		if err != nil {
-			log.Fatalf("Cannot read source file %s while copying: %s", source, err)
+			return fmt.Errorf("cannot read source file %s while copying: %w", source, err)
 		}
 
-		err = os.WriteFile(targetFile, sourceByte, 0644)
+		err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 		if err != nil {
-			log.Fatalf("Cannot write target file %s while copying: %s", target, err)
+			return fmt.Errorf("cannot write target file %s while copying: %w", target, err)
 		}
 
 		// Replace import path based on version (beta/alpha)
 		if filepath.Ext(target) == ".go" || filepath.Ext(target) == ".mod" {
-			tgc.replaceImportPath(outputFolder, target)
+			if err := tgc.replaceImportPath(outputFolder, target); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversion) replaceImportPath(outputFolder, target string) {
+func (tgc TerraformGoogleConversion) replaceImportPath(outputFolder, target string) error {
 	// Replace import paths to reference the resources dir instead of the google provider
 	targetFile := filepath.Join(outputFolder, target)
-	sourceByte, err := os.ReadFile(targetFile)
+	sourceByte, err := outputFS.ReadFile(targetFile)
 	if err != nil {
-		log.Fatalf("Cannot read file %s to replace import path: %s", targetFile, err)
+		return fmt.Errorf("cannot read file %s to replace import path: %w", targetFile, err)
 	}
 
 	// replace google to google-beta
 	gaImportPath := ImportPathFromVersion("ga")
 	sourceByte = bytes.Replace(sourceByte, []byte(gaImportPath), []byte(TERRAFORM_PROVIDER_BETA+"/"+RESOURCE_DIRECTORY_BETA), -1)
-	err = os.WriteFile(targetFile, sourceByte, 0644)
+	err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 	if err != nil {
-		log.Fatalf("Cannot write file %s to replace import path: %s", target, err)
+		return fmt.Errorf("cannot write file %s to replace import path: %w", target, err)
 	}
+	return nil
 }
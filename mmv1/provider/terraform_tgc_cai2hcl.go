@@ -24,7 +24,6 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
-	"github.com/otiai10/copy"
 )
 
 // Code generator for a library converting GCP CAI objects to Terraform state.
@@ -55,35 +54,36 @@ func NewCaiToTerraformConversion(product *api.Product, versionName string, start
 	return t
 }
 
-func (cai2hcl CaiToTerraformConversion) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (cai2hcl CaiToTerraformConversion) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	return nil
 }
 
-func (cai2hcl CaiToTerraformConversion) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) {
+func (cai2hcl CaiToTerraformConversion) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
+	return nil
 }
 
-func (cai2hcl CaiToTerraformConversion) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) {
+func (cai2hcl CaiToTerraformConversion) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
 	if !generateCode {
-		return
+		return nil
 	}
 	log.Print("Copying cai2hcl common files")
 
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
 	if cai2hcl.Product != nil {
 		srcDir := filepath.Join("third_party/cai2hcl/services", cai2hcl.Product.ApiName)
 		dstDir := filepath.Join(outputFolder, "services", cai2hcl.Product.ApiName)
-		if err := copy.Copy(srcDir, dstDir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		if err := copyDir(srcDir, dstDir, nil); err != nil && !errors.Is(err, os.ErrNotExist) {
 			log.Println(fmt.Errorf("error copying service directory %v: %v", srcDir, err))
 		}
 	} else {
-		if err := copy.Copy("third_party/cai2hcl", outputFolder, copy.Options{
-			Skip: func(srcinfo os.FileInfo, src, dest string) (bool, error) {
-				return strings.Contains(src, "/services/"), nil
-			},
+		if err := copyDir("third_party/cai2hcl", outputFolder, func(src string) bool {
+			return strings.Contains(src, "/services/")
 		}); err != nil {
 			log.Println(fmt.Errorf("error copying directory %v: %v", outputFolder, err))
 		}
 	}
+	return nil
 }
@@ -25,13 +25,14 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
-	"github.com/otiai10/copy"
 )
 
 var testRegex = regexp.MustCompile("func (TestAcc[^(]+)")
@@ -64,6 +65,16 @@ type ResourceIdentifier struct {
 	CaiAssetNameFormat string
 	ImportFormats      []string
 	IdentityParams     []string
+	// The format of the ids importing the Terraform resource, e.g.
+	// "projects/{{project}}/topics/{{name}}".
+	ImportIdFormat string
+	// The Terraform IAM resources splitting the IAM policies of the CAI assets,
+	// e.g. "google_pubsub_topic_iam", and the CAI asset name template parsing
+	// their parent attribute. Both are empty if the IAM policies can't be split.
+	IamTerraformName      string
+	IamCaiAssetNameFormat string
+	// Whether the Terraform IAM resources have a condition block.
+	IamConditions bool
 }
 
 func NewTerraformGoogleConversionNext(product *api.Product, versionName string, startTime time.Time, templateFS fs.FS) TerraformGoogleConversionNext {
@@ -85,8 +96,10 @@ func NewTerraformGoogleConversionNext(product *api.Product, versionName string,
 	return t
 }
 
-func (tgc TerraformGoogleConversionNext) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
-	tgc.GenerateProduct(outputFolder)
+func (tgc TerraformGoogleConversionNext) Generate(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
+	if err := tgc.GenerateProduct(outputFolder); err != nil {
+		return err
+	}
 	for _, object := range tgc.Product.Objects {
 		object.ExcludeIfNotInVersion(tgc.Product.Version)
 
@@ -95,34 +108,44 @@ func (tgc TerraformGoogleConversionNext) Generate(outputFolder, resourceToGenera
 			continue
 		}
 
-		tgc.GenerateObject(*object, outputFolder, tgc.TargetVersionName, generateCode, generateDocs)
+		if err := tgc.GenerateObject(*object, outputFolder, tgc.TargetVersionName, generateCode, generateDocs); err != nil {
+			return fmt.Errorf("error generating %s: %w", object.Name, err)
+		}
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversionNext) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversionNext) GenerateObject(object api.Resource, outputFolder, resourceToGenerate string, generateCode, generateDocs bool) error {
 	if !object.IncludeInTGCNext {
-		return
+		return nil
 	}
 
-	templateData := NewTemplateData(outputFolder, tgc.TargetVersionName, tgc.templateFS)
+	return generateTracked(object, outputFolder, tgc.TargetVersionName, tgc.templateFS, func(templateData *TemplateData) error {
+		tgc.templateFS = templateData.templateFS
 
-	if !object.ExcludeResource {
-		tgc.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
-	}
-	tgc.addTestsFromSamples(&object)
-	if err := tgc.addTestsFromHandwrittenTests(&object); err != nil {
-		log.Printf("Error adding examples from handwritten tests: %v", err)
-	}
+		if !object.ExcludeResource {
+			if err := tgc.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs); err != nil {
+				return err
+			}
+		}
+		if err := tgc.addTestsFromSamples(&object); err != nil {
+			return err
+		}
+		if err := tgc.addTestsFromHandwrittenTests(&object); err != nil {
+			log.Printf("Error adding examples from handwritten tests: %v", err)
+		}
 
-	if err := tgc.GenerateResourceTests(object, *templateData, outputFolder); err != nil {
-		log.Fatalf("Error generating resource tests: %v", err)
-	}
+		if err := tgc.GenerateResourceTests(object, *templateData, outputFolder); err != nil {
+			return fmt.Errorf("error generating resource tests: %w", err)
+		}
+		return nil
+	}, generateCode, generateDocs)
 }
 
-func (tgc TerraformGoogleConversionNext) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversionNext) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) error {
 	productName := tgc.Product.ApiName
 	targetFolder := path.Join(outputFolder, "pkg/services", productName)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 
@@ -132,14 +155,18 @@ func (tgc TerraformGoogleConversionNext) GenerateResource(object api.Resource, t
 	for _, converter := range converters {
 		templatePath := fmt.Sprintf("templates/tgc_next/%s/resource_converter.go.tmpl", converter)
 		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_%s.go", fileNamePrefix, converter))
-		templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object)
+		if err := templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object); err != nil {
+			return err
+		}
 	}
 
 	templatePath := "templates/tgc_next/services/resource.go.tmpl"
 	fileName := fmt.Sprintf("%s.go", fileNamePrefix)
 	targetFilePath := path.Join(targetFolder, fileName)
-	templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object)
-	tgc.replaceImportPath(targetFolder, fileName)
+	if err := templateData.GenerateTGCResourceFile(templatePath, targetFilePath, object); err != nil {
+		return err
+	}
+	return tgc.replaceImportPath(targetFolder, fileName)
 }
 
 func (tgc TerraformGoogleConversionNext) GenerateCaiToHclObjects(outputFolder, resourceToGenerate string, generateCode, generateDocs bool) {
@@ -158,30 +185,31 @@ func (tgc *TerraformGoogleConversionNext) GenerateResourceTests(object api.Resou
 
 	productName := tgc.Product.ApiName
 	targetFolder := path.Join(outputFolder, "test", "services", productName)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_generated_test.go", tgc.ResourceGoFilename(object)))
-	templateData.GenerateTGCNextTestFile(targetFilePath, object)
-	return nil
+	return templateData.GenerateTGCNextTestFile(targetFilePath, object)
 }
 
 // GenerateProduct creates the product.go file for a given service directory.
 // This will be used to seed the directory and add a package-level comment
 // specific to the product.
-func (tgc *TerraformGoogleConversionNext) GenerateProduct(outputFolder string) {
+func (tgc *TerraformGoogleConversionNext) GenerateProduct(outputFolder string) error {
 	targetFolder := path.Join(outputFolder, "pkg", "services", tgc.Product.ApiName)
-	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 
 	targetFilePath := path.Join(targetFolder, "product.go")
 	templateData := NewTemplateData(outputFolder, tgc.TargetVersionName, tgc.templateFS)
-	templateData.GenerateProductFile(targetFilePath, *tgc.Product)
-	tgc.replaceImportPath(targetFolder, "product.go")
+	if err := templateData.GenerateProductFile(targetFilePath, *tgc.Product); err != nil {
+		return err
+	}
+	return tgc.replaceImportPath(targetFolder, "product.go")
 }
 
-func (tgc TerraformGoogleConversionNext) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) {
+func (tgc TerraformGoogleConversionNext) CompileCommonFiles(outputFolder string, products []*api.Product, overridePath string) error {
 	resourceConverters := map[string]string{
 		// common
 		"pkg/transport/config.go":                    "third_party/terraform/transport/config.go.tmpl",
@@ -215,34 +243,34 @@ func (tgc TerraformGoogleConversionNext) CompileCommonFiles(outputFolder string,
 				filteredFiles[target] = source
 			}
 		}
-		tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
-	} else {
-		// Shared compilation
-		tgc.generateResourcesForVersion(products)
-		for target, source := range resourceConverters {
-			if !strings.Contains(target, "/services/") {
-				filteredFiles[target] = source
-			}
+		return tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
+	}
+
+	// Shared compilation
+	tgc.generateResourcesForVersion(products)
+	for target, source := range resourceConverters {
+		if !strings.Contains(target, "/services/") {
+			filteredFiles[target] = source
 		}
-		tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
 	}
+	return tgc.CompileFileList(outputFolder, filteredFiles, *templateData, products)
 }
 
-func (tgc TerraformGoogleConversionNext) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) {
+func (tgc TerraformGoogleConversionNext) CompileFileList(outputFolder string, files map[string]string, fileTemplate TemplateData, products []*api.Product) error {
 	providerWithProducts := TgcWithProducts{
 		TerraformGoogleConversionNext: tgc,
 		Compiler:                      "terraformgoogleconversion-codegen",
 		Products:                      products,
 	}
 
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 
@@ -252,19 +280,24 @@ func (tgc TerraformGoogleConversionNext) CompileFileList(outputFolder string, fi
 
 		formatFile := filepath.Ext(targetFile) == ".go"
 
-		fileTemplate.GenerateFile(targetFile, source, providerWithProducts, formatFile, templates...)
-		tgc.replaceImportPath(outputFolder, target)
+		if err := fileTemplate.GenerateFile(targetFile, source, providerWithProducts, formatFile, templates...); err != nil {
+			return err
+		}
+		if err := tgc.replaceImportPath(outputFolder, target); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) {
+func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, generateCode, generateDocs bool) error {
 	if !generateCode {
-		return
+		return nil
 	}
 
 	log.Printf("Copying common files for tgc.")
 
-	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+	if err := outputFS.MkdirAll(outputFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
 	}
 
@@ -275,8 +308,11 @@ func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, ge
 		"pkg/transport/batcher.go":                "third_party/terraform/transport/batcher.go",
 		"pkg/transport/error_retry_predicates.go": "third_party/terraform/transport/error_retry_predicates.go",
 		"pkg/transport/header_transport.go":       "third_party/terraform/transport/header_transport.go",
+		"pkg/transport/rate_limit_transport.go":   "third_party/terraform/transport/rate_limit_transport.go",
+		"pkg/transport/request_log_transport.go":  "third_party/terraform/transport/request_log_transport.go",
 		"pkg/transport/retry_transport.go":        "third_party/terraform/transport/retry_transport.go",
 		"pkg/transport/retry_utils.go":            "third_party/terraform/transport/retry_utils.go",
+		"pkg/transport/tracing.go":                "third_party/terraform/transport/tracing.go",
 		"pkg/transport/transport.go":              "third_party/terraform/transport/transport.go",
 		"pkg/tpgresource/utils.go":                "third_party/terraform/tpgresource/utils.go",
 		"pkg/tpgresource/self_link_helpers.go":    "third_party/terraform/tpgresource/self_link_helpers.go",
@@ -284,6 +320,7 @@ func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, ge
 		"pkg/tpgresource/regional_utils.go":       "third_party/terraform/tpgresource/regional_utils.go",
 		"pkg/tpgresource/field_helpers.go":        "third_party/terraform/tpgresource/field_helpers.go",
 		"pkg/tpgresource/service_scope.go":        "third_party/terraform/tpgresource/service_scope.go",
+		"pkg/tpgresource/tracing.go":              "third_party/terraform/tpgresource/tracing.go",
 		"pkg/verify/validation.go":                "third_party/terraform/verify/validation.go",
 		"pkg/verify/path_or_contents.go":          "third_party/terraform/verify/path_or_contents.go",
 		"pkg/version/version.go":                  "third_party/terraform/version/version.go",
@@ -309,19 +346,19 @@ func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, ge
 				filteredFiles[target] = source
 			}
 		}
-		tgc.CopyFileList(outputFolder, filteredFiles)
+		if err := tgc.CopyFileList(outputFolder, filteredFiles); err != nil {
+			return err
+		}
 
 		srcDir := filepath.Join("third_party/tgc_next/pkg/services", tgc.Product.ApiName)
 		dstDir := filepath.Join(outputFolder, "pkg/services", tgc.Product.ApiName)
-		if err := copy.Copy(srcDir, dstDir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		if err := copyDir(srcDir, dstDir, nil); err != nil && !errors.Is(err, os.ErrNotExist) {
 			log.Println(fmt.Errorf("error copying service directory %v: %v", srcDir, err))
 		}
 	} else {
 		// Shared copying
-		if err := copy.Copy("third_party/tgc_next", outputFolder, copy.Options{
-			Skip: func(srcinfo os.FileInfo, src, dest string) (bool, error) {
-				return strings.Contains(src, "pkg/services/"), nil
-			},
+		if err := copyDir("third_party/tgc_next", outputFolder, func(src string) bool {
+			return strings.Contains(src, "pkg/services/")
 		}); err != nil {
 			log.Println(fmt.Errorf("error copying directory %v: %v", outputFolder, err))
 		}
@@ -331,60 +368,64 @@ func (tgc TerraformGoogleConversionNext) CopyCommonFiles(outputFolder string, ge
 				filteredFiles[target] = source
 			}
 		}
-		tgc.CopyFileList(outputFolder, filteredFiles)
+		return tgc.CopyFileList(outputFolder, filteredFiles)
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversionNext) CopyTfToCaiCommonFiles(outputFolder string) {
+func (tgc TerraformGoogleConversionNext) CopyTfToCaiCommonFiles(outputFolder string) error {
 	resourceConverters := map[string]string{
 		"pkg/tfplan2cai/converters/services/compute/image.go":     "third_party/terraform/services/compute/image.go",
 		"pkg/tfplan2cai/converters/services/compute/disk_type.go": "third_party/terraform/services/compute/disk_type.go",
 	}
-	tgc.CopyFileList(outputFolder, resourceConverters)
+	return tgc.CopyFileList(outputFolder, resourceConverters)
 }
 
-func (tgc TerraformGoogleConversionNext) CopyCaiToHclCommonFiles(outputFolder string) {
+func (tgc TerraformGoogleConversionNext) CopyCaiToHclCommonFiles(outputFolder string) error {
 	resourceConverters := map[string]string{}
-	tgc.CopyFileList(outputFolder, resourceConverters)
+	return tgc.CopyFileList(outputFolder, resourceConverters)
 }
 
-func (tgc TerraformGoogleConversionNext) CopyFileList(outputFolder string, files map[string]string) {
+func (tgc TerraformGoogleConversionNext) CopyFileList(outputFolder string, files map[string]string) error {
 	for target, source := range files {
 		targetFile := filepath.Join(outputFolder, target)
 		targetDir := filepath.Dir(targetFile)
 
-		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		if err := outputFS.MkdirAll(targetDir, os.ModePerm); err != nil {
 			log.Println(fmt.Errorf("error creating output directory %v: %v", targetDir, err))
 		}
 		// If we've modified a file since starting an MM run, it's a reasonable
 		// assumption that it was this run that modified it.
-		if info, err := os.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && tgc.StartTime.Before(info.ModTime()) {
-			log.Fatalf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
+		if info, err := outputFS.Stat(targetFile); !errors.Is(err, os.ErrNotExist) && tgc.StartTime.Before(info.ModTime()) {
+			return fmt.Errorf("%s was already modified during this run at %s", targetFile, info.ModTime().String())
 		}
 
 		sourceByte, err := fs.ReadFile(tgc.templateFS, source)
 		if err != nil {
-			log.Fatalf("Cannot read source file %s while copying: %s", source, err)
+			return fmt.Errorf("cannot read source file %s while copying: %w", source, err)
 		}
 
-		err = os.WriteFile(targetFile, sourceByte, 0644)
+		err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 		if err != nil {
-			log.Fatalf("Cannot write target file %s while copying: %s", target, err)
+			return fmt.Errorf("cannot write target file %s while copying: %w", target, err)
 		}
 
 		// Replace import path based on version (beta/alpha)
 		if filepath.Ext(target) == ".go" || filepath.Ext(target) == ".mod" {
-			tgc.replaceImportPath(outputFolder, target)
+			if err := tgc.replaceImportPath(outputFolder, target); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversionNext) replaceImportPath(outputFolder, target string) {
+func (tgc TerraformGoogleConversionNext) replaceImportPath(outputFolder, target string) error {
 	// Replace import paths to reference the resources dir instead of the google provider
 	targetFile := filepath.Join(outputFolder, target)
-	sourceByte, err := os.ReadFile(targetFile)
+	sourceByte, err := outputFS.ReadFile(targetFile)
 	if err != nil {
-		log.Fatalf("Cannot read file %s to replace import path: %s", targetFile, err)
+		return fmt.Errorf("cannot read file %s to replace import path: %w", targetFile, err)
 	}
 
 	// replace google to google-beta
@@ -392,15 +433,16 @@ func (tgc TerraformGoogleConversionNext) replaceImportPath(outputFolder, target
 	sourceByte = bytes.Replace(sourceByte, []byte(gaImportPath), []byte(TGC_PROVIDER+"/"+RESOURCE_DIRECTORY_TGC), -1)
 	sourceByte = bytes.Replace(sourceByte, []byte(TERRAFORM_PROVIDER_GA+"/version"), []byte(TGC_PROVIDER+"/"+RESOURCE_DIRECTORY_TGC+"/version"), -1)
 
-	err = os.WriteFile(targetFile, sourceByte, 0644)
+	err = outputFS.WriteFile(targetFile, sourceByte, 0644)
 	if err != nil {
-		log.Fatalf("Cannot write file %s to replace import path: %s", target, err)
+		return fmt.Errorf("cannot write file %s to replace import path: %w", target, err)
 	}
+	return nil
 }
 
-func (tgc TerraformGoogleConversionNext) addTestsFromSamples(object *api.Resource) {
+func (tgc TerraformGoogleConversionNext) addTestsFromSamples(object *api.Resource) error {
 	if object.Examples != nil {
-		log.Fatalf("Examples block exists in %v", object.Name)
+		return fmt.Errorf("examples block exists in %v", object.Name)
 	}
 	for _, sample := range object.Samples {
 		if sample.ExcludeTest {
@@ -414,6 +456,7 @@ func (tgc TerraformGoogleConversionNext) addTestsFromSamples(object *api.Resourc
 			Skip: sample.TGCSkipTest,
 		})
 	}
+	return nil
 }
 
 func (tgc TerraformGoogleConversionNext) addTestsByTestNameMatch(object *api.Resource) error {
@@ -603,6 +646,12 @@ func (tgc *TerraformGoogleConversionNext) generateResourcesForVersion(products [
 				AliasName:          object.ResourceName(),
 				CaiAssetNameFormat: object.GetCaiAssetNameTemplate(),
 				ImportFormats:      object.ImportFormat,
+				ImportIdFormat:     strings.ReplaceAll(object.ImportIdFormatsFromResource()[0], "{{%", "{{"),
+			}
+			if iamTemplate := tgc.iamCaiAssetNameTemplate(object); iamTemplate != "" {
+				resourceIdentifier.IamTerraformName = object.IamTerraformName()
+				resourceIdentifier.IamCaiAssetNameFormat = iamTemplate
+				resourceIdentifier.IamConditions = object.IamPolicy.IamConditionsRequestType != ""
 			}
 			tgc.ResourcesForVersion = append(tgc.ResourcesForVersion, resourceIdentifier)
 
@@ -627,6 +676,35 @@ func (tgc *TerraformGoogleConversionNext) generateResourcesForVersion(products [
 	}
 }
 
+// Returns the CAI asset name template parsing the parent attribute of the IAM
+// resources of the object from its CAI asset name, e.g.
+// "//pubsub.googleapis.com/{{topic}}" for google_pubsub_topic_iam_*, whose
+// topic accepts the relative name "projects/{{project}}/topics/{{name}}".
+// It's empty if the object has no IAM resources in the target version, or if
+// they don't accept the relative CAI asset name as their parent.
+func (tgc *TerraformGoogleConversionNext) iamCaiAssetNameTemplate(object *api.Resource) string {
+	iam := object.IamPolicy
+	if iam == nil || iam.Exclude || iam.ExcludeTgc || iam.ParentIsResourceId {
+		return ""
+	}
+	if iam.MinVersion != "" && slices.Index(product.ORDER, iam.MinVersion) > slices.Index(product.ORDER, tgc.TargetVersionName) {
+		return ""
+	}
+
+	service, relativeName, ok := strings.Cut(strings.TrimPrefix(object.GetCaiAssetNameTemplate(), "//"), "/")
+	if !ok {
+		return ""
+	}
+	placeholders := regexp.MustCompile(`\{\{%?\w+\}\}`)
+	relativeName = placeholders.ReplaceAllString(relativeName, "{{}}")
+	for _, format := range object.ImportIdFormatsFromIam() {
+		if placeholders.ReplaceAllString(format, "{{}}") == relativeName {
+			return fmt.Sprintf("//%s/{{%s}}", service, object.IamParentResourceName())
+		}
+	}
+	return ""
+}
+
 // Analyzes a list of CAI asset names and finds all path segments
 // that contain different values across all names, dropping only the segments
 // that are identical across the entire group. This robustly retains identifying
@@ -19,6 +19,8 @@ import (
 	"testing/fstest"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 )
 
 func TestFindIdentityParams(t *testing.T) {
@@ -129,6 +131,72 @@ func TestFindIdentityParams(t *testing.T) {
 	}
 }
 
+func TestIamCaiAssetNameTemplate(t *testing.T) {
+	pubsub := &api.Product{
+		Name: "Pubsub",
+		Version: &product.Version{
+			Name:    "ga",
+			BaseUrl: "https://pubsub.googleapis.com/v1/",
+		},
+	}
+
+	cases := []struct {
+		name      string
+		iamPolicy *resource.IamPolicy
+		caiFormat string
+		expected  string
+	}{
+		{
+			name:      "relative asset name accepted by the parent attribute",
+			iamPolicy: &resource.IamPolicy{ParentResourceAttribute: "topic"},
+			expected:  "//pubsub.googleapis.com/{{topic}}",
+		},
+		{
+			name:     "no IAM policy",
+			expected: "",
+		},
+		{
+			name:      "IAM policy excluded from TGC",
+			iamPolicy: &resource.IamPolicy{ParentResourceAttribute: "topic", ExcludeTgc: true},
+			expected:  "",
+		},
+		{
+			name:      "IAM policy in a later version",
+			iamPolicy: &resource.IamPolicy{ParentResourceAttribute: "topic", MinVersion: "beta"},
+			expected:  "",
+		},
+		{
+			name:      "parent attribute takes the resource id",
+			iamPolicy: &resource.IamPolicy{ParentResourceAttribute: "topic", ParentIsResourceId: true},
+			expected:  "",
+		},
+		{
+			name:      "CAI asset name not accepted by the parent attribute",
+			iamPolicy: &resource.IamPolicy{ParentResourceAttribute: "topic"},
+			caiFormat: "topics/{{name}}",
+			expected:  "",
+		},
+	}
+
+	tgc := TerraformGoogleConversionNext{TargetVersionName: "ga"}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &api.Resource{
+				Name:            "Topic",
+				BaseUrl:         "projects/{{project}}/topics",
+				IdFormat:        "projects/{{project}}/topics/{{name}}",
+				ImportFormat:    []string{"projects/{{project}}/topics/{{name}}"},
+				IamPolicy:       c.iamPolicy,
+				ProductMetadata: pubsub,
+			}
+			r.CaiAssetNameFormat = c.caiFormat
+			if got := tgc.iamCaiAssetNameTemplate(r); got != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
 func TestAddTestsFromHandwrittenTests(t *testing.T) {
 	mockFS := fstest.MapFS{
 		"third_party/terraform/services/dummy/resource_dummy_dummy_test.go": &fstest.MapFile{
@@ -0,0 +1,50 @@
+package fakegcp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+)
+
+// tokenPath is the path of the OAuth2 token endpoint of the fake.
+const tokenPath = "/token"
+
+// Credentials returns service account credentials whose tokens are issued by
+// the server at url, for the GOOGLE_CREDENTIALS environment variable. The
+// fake doesn't check tokens, but the provider requires credentials.
+func (s *Server) Credentials(url string) (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	creds, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "fake-project",
+		"private_key_id": "fake",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "fake@fake-project.iam.gserviceaccount.com",
+		"client_id":      "1",
+		"token_uri":      strings.TrimSuffix(url, "/") + tokenPath,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(creds), nil
+}
+
+// serveToken issues an access token for any request.
+func serveToken(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "fake-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
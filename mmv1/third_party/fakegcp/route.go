@@ -0,0 +1,154 @@
+// Package fakegcp is an in-memory fake of the Google Cloud APIs of the
+// provider's generated resources, so that acceptance tests can run locally,
+// without credentials. The routes of every resource are generated by Magic
+// Modules from its MMv1 definition.
+package fakegcp
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FieldKind describes how the fake fills an output-only field.
+type FieldKind int
+
+const (
+	// FieldValue fills the field with a static value.
+	FieldValue FieldKind = iota
+	// FieldName fills the field with the relative resource name of the
+	// object, such as projects/my-project/topics/my-topic.
+	FieldName
+	// FieldSelfLink fills the field with the URL of the object.
+	FieldSelfLink
+	// FieldTime fills the field with the time the object was created.
+	FieldTime
+	// FieldId fills the field with a unique number.
+	FieldId
+)
+
+// OutputField is an output-only field of a resource, which the fake fills
+// when an object is created.
+type OutputField struct {
+	// Name is the name of the field in the API.
+	Name string
+	Kind FieldKind
+	// Value is the value of FieldValue fields.
+	Value any
+}
+
+// Route describes how the fake serves the API of a resource.
+type Route struct {
+	// Resource is the Terraform name of the resource, such as
+	// google_pubsub_topic.
+	Resource string
+	// Product is the first segment of the paths of the resource, such as
+	// pubsub. The custom endpoint of the product points to it.
+	Product string
+	// EndpointEnvVar is the environment variable setting the custom
+	// endpoint of the product.
+	EndpointEnvVar string
+
+	// The paths of the resource, relative to the product. Fields are
+	// written as {{field}}, or {{%field}} if they can contain slashes.
+	CollectionPath string
+	SelfLinkPath   string
+	CreatePath     string
+
+	CreateVerb string
+	UpdateVerb string
+	DeleteVerb string
+
+	// CreateIdParam is the query parameter holding the id of new objects.
+	// If empty, the id is the last segment of the `name` of the request.
+	CreateIdParam string
+	// UpdateMask is whether updates honor the updateMask query parameter.
+	UpdateMask bool
+	// AsyncActions are the actions, among create, update and delete,
+	// returning a long-running operation.
+	AsyncActions []string
+	// CollectionKey is the field holding the objects of list responses.
+	CollectionKey string
+
+	OutputFields []OutputField
+}
+
+var routes = make(map[string]Route)
+
+// Register adds the route of a resource to every new Server.
+func (r Route) Register() {
+	if _, ok := routes[r.Resource]; ok {
+		log.Fatalf("fakegcp: duplicate route for %s", r.Resource)
+	}
+	routes[r.Resource] = r
+}
+
+// Routes returns the registered routes, sorted by resource.
+func Routes() []Route {
+	var result []Route
+	for _, r := range routes {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Resource < result[j].Resource
+	})
+	return result
+}
+
+var pathField = regexp.MustCompile(`\{\{(%?)(\w+)\}\}`)
+
+// pathRegexp returns a regexp matching the paths of template, followed by
+// suffix, with a named group per field. Lenient regexps let every field
+// contain slashes, as references to other resources often do.
+func pathRegexp(template, suffix string, lenient bool) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, m := range pathField.FindAllStringSubmatchIndex(template, -1) {
+		b.WriteString(regexp.QuoteMeta(template[last:m[0]]))
+		name := template[m[4]:m[5]]
+		if lenient || m[3] > m[2] {
+			fmt.Fprintf(&b, "(?P<%s>.+)", name)
+		} else {
+			fmt.Fprintf(&b, "(?P<%s>[^/]+)", name)
+		}
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(template[last:]))
+	b.WriteString(suffix)
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// pathFields returns the fields of template.
+func pathFields(template string) []string {
+	var fields []string
+	for _, m := range pathField.FindAllStringSubmatch(template, -1) {
+		fields = append(fields, m[2])
+	}
+	return fields
+}
+
+// renderPath replaces the fields of template by their values.
+func renderPath(template string, values map[string]string) string {
+	return pathField.ReplaceAllStringFunc(template, func(match string) string {
+		return values[pathField.FindStringSubmatch(match)[2]]
+	})
+}
+
+// matchPath returns the values of the fields of re in path.
+func matchPath(re *regexp.Regexp, path string) (map[string]string, bool) {
+	m := re.FindStringSubmatch(path)
+	if m == nil {
+		return nil, false
+	}
+	values := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			values[name] = m[i]
+		}
+	}
+	return values, true
+}
@@ -0,0 +1,428 @@
+package fakegcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory fake of the APIs of the registered routes. Objects
+// are stored in memory, long-running operations are done as soon as they're
+// returned and output-only fields are filled on creation.
+//
+// Requests are routed on their first path segment, the product, so the
+// custom endpoint of a product must be the URL of the server followed by the
+// product and a slash.
+type Server struct {
+	mu         sync.Mutex
+	routes     map[string][]route
+	objects    map[string]map[string]any
+	operations map[string]map[string]any
+	lastId     int
+	now        func() time.Time
+}
+
+type route struct {
+	Route
+
+	// strict and lenient are the regexps matching the paths of the route,
+	// the lenient ones being tried when no strict one matches.
+	strict  routeRegexps
+	lenient routeRegexps
+}
+
+type routeRegexps struct {
+	collection *regexp.Regexp
+	selfLink   *regexp.Regexp
+	method     *regexp.Regexp
+	create     *regexp.Regexp
+}
+
+func newRouteRegexps(r Route, lenient bool) routeRegexps {
+	return routeRegexps{
+		collection: pathRegexp(r.CollectionPath, "", lenient),
+		selfLink:   pathRegexp(r.SelfLinkPath, "", lenient),
+		method:     pathRegexp(r.SelfLinkPath, `[:/][A-Za-z]+`, lenient),
+		create:     pathRegexp(r.CreatePath, "", lenient),
+	}
+}
+
+// NewServer returns a Server for the registered routes.
+func NewServer() *Server {
+	return newServer(Routes())
+}
+
+func newServer(routes []Route) *Server {
+	s := &Server{
+		routes:     make(map[string][]route),
+		objects:    make(map[string]map[string]any),
+		operations: make(map[string]map[string]any),
+		now:        time.Now,
+	}
+	for _, r := range routes {
+		s.routes[r.Product] = append(s.routes[r.Product], route{
+			Route:   r,
+			strict:  newRouteRegexps(r, false),
+			lenient: newRouteRegexps(r, true),
+		})
+	}
+	return s
+}
+
+// Endpoints returns the custom endpoint environment variables of the
+// products of the server, pointing to url, the URL of the server.
+func (s *Server) Endpoints(url string) map[string]string {
+	endpoints := make(map[string]string)
+	for product, routes := range s.routes {
+		for _, r := range routes {
+			endpoints[r.EndpointEnvVar] = fmt.Sprintf("%s/%s/", strings.TrimSuffix(url, "/"), product)
+		}
+	}
+	return endpoints
+}
+
+var operationPath = regexp.MustCompile(`(?:^|/)operations/([^/]+)$`)
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.URL.Path == tokenPath && req.Method == http.MethodPost {
+		serveToken(w)
+		return
+	}
+
+	product, path, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if req.Method == http.MethodGet {
+		if m := operationPath.FindStringSubmatch(path); m != nil {
+			if op, ok := s.operations[m[1]]; ok {
+				writeJSON(w, http.StatusOK, op)
+				return
+			}
+		}
+	}
+
+	var body map[string]any
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+	}
+	if body == nil {
+		body = make(map[string]any)
+	}
+
+	c := call{w: w, req: req, product: product, base: baseURL(req, product)}
+	for _, lenient := range []bool{false, true} {
+		for _, r := range s.routes[product] {
+			c.route = r
+			if s.serve(c, path, body, lenient) {
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s is not served by the fake", req.Method, req.URL.Path))
+}
+
+// serve serves the request if its path is one of the route of c, and
+// returns whether it did.
+func (s *Server) serve(c call, path string, body map[string]any, lenient bool) bool {
+	re := c.route.strict
+	if lenient {
+		re = c.route.lenient
+	}
+	method := c.req.Method
+
+	if values, ok := matchPath(re.create, path); ok && method == c.route.CreateVerb {
+		if c.route.CreatePath == c.route.SelfLinkPath && s.objects[c.key(path)] != nil && method == c.route.UpdateVerb {
+			s.update(c, path, body, false)
+		} else {
+			s.create(c, values, body)
+		}
+		return true
+	}
+	// Lists come first, as self links made of a single field, such as
+	// {{name}}, match collections too.
+	if values, ok := matchPath(re.collection, path); ok && method == http.MethodGet && c.route.CollectionPath != c.route.SelfLinkPath {
+		s.list(c, renderPath(c.route.CollectionPath, values))
+		return true
+	}
+	if _, ok := matchPath(re.selfLink, path); ok {
+		switch method {
+		case http.MethodGet:
+			s.get(c, path)
+			return true
+		case c.route.DeleteVerb:
+			s.delete(c, path)
+			return true
+		case c.route.UpdateVerb:
+			s.update(c, path, body, false)
+			return true
+		}
+	}
+	if values, ok := matchPath(re.method, path); ok && method != http.MethodGet && method != http.MethodDelete {
+		s.update(c, renderPath(c.route.SelfLinkPath, values), body, true)
+		return true
+	}
+	return false
+}
+
+// call is a request routed to a resource.
+type call struct {
+	w       http.ResponseWriter
+	req     *http.Request
+	route   route
+	product string
+	// base is the URL of the product, which paths are relative to.
+	base string
+}
+
+func (c call) key(path string) string {
+	return c.product + "/" + path
+}
+
+func (s *Server) create(c call, values map[string]string, body map[string]any) {
+	id := ""
+	if c.route.CreateIdParam != "" {
+		id = c.req.URL.Query().Get(c.route.CreateIdParam)
+	} else if name, ok := body["name"].(string); ok {
+		id = name[strings.LastIndex(name, "/")+1:]
+	}
+	if id == "" {
+		// The API assigns the id.
+		id = fmt.Sprintf("fake-%d", s.nextId())
+	}
+	fields := pathFields(c.route.SelfLinkPath)
+	if len(fields) == 1 && pathField.FindString(c.route.SelfLinkPath) == c.route.SelfLinkPath {
+		// The self link is the whole name, such as {{name}}, of an object
+		// of the collection the object is created in.
+		values[fields[0]] = renderPath(c.route.CreatePath, values)
+		if !strings.HasSuffix(c.route.CreatePath, "}}") {
+			values[fields[0]] += "/" + id
+		}
+	}
+	for _, field := range fields {
+		if _, ok := values[field]; !ok {
+			values[field] = id
+		}
+	}
+
+	path := renderPath(c.route.SelfLinkPath, values)
+	if _, ok := s.objects[c.key(path)]; ok {
+		writeError(c.w, http.StatusConflict, fmt.Sprintf("%s already exists", path))
+		return
+	}
+	for _, f := range c.route.OutputFields {
+		switch f.Kind {
+		case FieldName:
+			body[f.Name] = path
+		case FieldSelfLink:
+			body[f.Name] = c.base + path
+		case FieldTime:
+			body[f.Name] = s.now().UTC().Format(time.RFC3339)
+		case FieldId:
+			body[f.Name] = strconv.Itoa(s.nextId())
+		default:
+			body[f.Name] = f.Value
+		}
+	}
+	s.objects[c.key(path)] = body
+	s.respond(c, "create", path, body)
+}
+
+func (s *Server) get(c call, path string) {
+	obj, ok := s.objects[c.key(path)]
+	if !ok {
+		writeError(c.w, http.StatusNotFound, fmt.Sprintf("%s not found", path))
+		return
+	}
+	writeJSON(c.w, http.StatusOK, obj)
+}
+
+// update applies body to the object at path. Method calls, such as
+// setLabels, merge body into the object. Otherwise the fields of the
+// updateMask are replaced, or every field if there's no mask.
+func (s *Server) update(c call, path string, body map[string]any, method bool) {
+	obj, ok := s.objects[c.key(path)]
+	if !ok {
+		writeError(c.w, http.StatusNotFound, fmt.Sprintf("%s not found", path))
+		return
+	}
+
+	mask := c.req.URL.Query().Get("updateMask")
+	switch {
+	case method:
+		for k, v := range body {
+			obj[k] = v
+		}
+	case c.route.UpdateMask && mask != "":
+		for _, field := range strings.Split(mask, ",") {
+			applyMask(obj, body, strings.Split(camelize(strings.TrimSpace(field)), "."))
+		}
+	case c.req.Method == http.MethodPatch:
+		for k, v := range body {
+			obj[k] = v
+		}
+	default:
+		for k := range obj {
+			if !c.route.isOutputField(k) {
+				delete(obj, k)
+			}
+		}
+		for k, v := range body {
+			if !c.route.isOutputField(k) {
+				obj[k] = v
+			}
+		}
+	}
+	s.respond(c, "update", path, obj)
+}
+
+func (s *Server) delete(c call, path string) {
+	if _, ok := s.objects[c.key(path)]; !ok {
+		writeError(c.w, http.StatusNotFound, fmt.Sprintf("%s not found", path))
+		return
+	}
+	delete(s.objects, c.key(path))
+	s.respond(c, "delete", path, map[string]any{})
+}
+
+func (s *Server) list(c call, path string) {
+	prefix := c.key(path) + "/"
+	var keys []string
+	for k := range s.objects {
+		if rest, ok := strings.CutPrefix(k, prefix); ok && !strings.Contains(rest, "/") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	items := make([]any, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, s.objects[k])
+	}
+	writeJSON(c.w, http.StatusOK, map[string]any{c.route.CollectionKey: items})
+}
+
+// respond writes obj, or a done operation of obj if action is asynchronous.
+func (s *Server) respond(c call, action, path string, obj map[string]any) {
+	if !slices.Contains(c.route.AsyncActions, action) {
+		writeJSON(c.w, http.StatusOK, obj)
+		return
+	}
+
+	id := fmt.Sprintf("operation-%d", s.nextId())
+	name := strings.TrimPrefix(operationParent(path)+"/operations/"+id, "/")
+	op := map[string]any{
+		"name":          name,
+		"done":          true,
+		"status":        "DONE",
+		"progress":      100,
+		"operationType": action,
+		"selfLink":      c.base + name,
+		"targetLink":    c.base + path,
+		"metadata":      map[string]any{},
+		"response":      obj,
+	}
+	s.operations[id] = op
+	writeJSON(c.w, http.StatusOK, op)
+}
+
+func (s *Server) nextId() int {
+	s.lastId++
+	return s.lastId
+}
+
+func (r route) isOutputField(name string) bool {
+	return slices.ContainsFunc(r.OutputFields, func(f OutputField) bool {
+		return f.Name == name
+	})
+}
+
+// operationParent returns the location operations of the object at path
+// belong to, such as projects/my-project/locations/us-central1.
+func operationParent(path string) string {
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || segments[0] != "projects" {
+		return ""
+	}
+	if len(segments) >= 4 && slices.Contains([]string{"locations", "regions", "zones"}, segments[2]) {
+		return strings.Join(segments[:4], "/")
+	}
+	if len(segments) >= 3 && segments[2] == "global" {
+		return strings.Join(segments[:3], "/")
+	}
+	return strings.Join(segments[:2], "/")
+}
+
+// applyMask replaces the field at path of dst by the one of src, or removes
+// it if src doesn't have it.
+func applyMask(dst, src map[string]any, path []string) {
+	if len(path) == 1 {
+		if v, ok := src[path[0]]; ok {
+			dst[path[0]] = v
+		} else {
+			delete(dst, path[0])
+		}
+		return
+	}
+	srcChild, _ := src[path[0]].(map[string]any)
+	dstChild, ok := dst[path[0]].(map[string]any)
+	if !ok {
+		dstChild = make(map[string]any)
+		dst[path[0]] = dstChild
+	}
+	applyMask(dstChild, srcChild, path[1:])
+}
+
+// camelize converts the snake_case segments of an update mask to camelCase.
+func camelize(mask string) string {
+	parts := strings.Split(mask, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func baseURL(req *http.Request, product string) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/%s/", scheme, req.Host, product)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorStatuses are the canonical statuses of the errors of the fake.
+var errorStatuses = map[int]string{
+	http.StatusBadRequest: "INVALID_ARGUMENT",
+	http.StatusNotFound:   "NOT_FOUND",
+	http.StatusConflict:   "ALREADY_EXISTS",
+}
+
+// writeError writes an error in the format of the Google APIs.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"status":  errorStatuses[code],
+		},
+	})
+}
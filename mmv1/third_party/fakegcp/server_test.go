@@ -0,0 +1,304 @@
+package fakegcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testTopicRoute = Route{
+	Resource:       "google_pubsub_topic",
+	Product:        "pubsub",
+	EndpointEnvVar: "GOOGLE_PUBSUB_CUSTOM_ENDPOINT",
+	CollectionPath: "projects/{{project}}/topics",
+	SelfLinkPath:   "projects/{{project}}/topics/{{name}}",
+	CreatePath:     "projects/{{project}}/topics/{{name}}",
+	CreateVerb:     "PUT",
+	UpdateVerb:     "PATCH",
+	DeleteVerb:     "DELETE",
+	UpdateMask:     true,
+	CollectionKey:  "topics",
+}
+
+var testInstanceRoute = Route{
+	Resource:       "google_alloydb_instance",
+	Product:        "alloydb",
+	EndpointEnvVar: "GOOGLE_ALLOYDB_CUSTOM_ENDPOINT",
+	CollectionPath: "{{%cluster}}/instances",
+	SelfLinkPath:   "{{%cluster}}/instances/{{instance_id}}",
+	CreatePath:     "{{%cluster}}/instances",
+	CreateVerb:     "POST",
+	UpdateVerb:     "PATCH",
+	DeleteVerb:     "DELETE",
+	CreateIdParam:  "instanceId",
+	UpdateMask:     true,
+	AsyncActions:   []string{"create", "update", "delete"},
+	CollectionKey:  "instances",
+	OutputFields: []OutputField{
+		{Name: "name", Kind: FieldName},
+		{Name: "createTime", Kind: FieldTime},
+		{Name: "uid", Kind: FieldId},
+		{Name: "state", Kind: FieldValue, Value: "READY"},
+	},
+}
+
+var testNetworkRoute = Route{
+	Resource:       "google_compute_network",
+	Product:        "compute",
+	EndpointEnvVar: "GOOGLE_COMPUTE_CUSTOM_ENDPOINT",
+	CollectionPath: "projects/{{project}}/global/networks",
+	SelfLinkPath:   "projects/{{project}}/global/networks/{{name}}",
+	CreatePath:     "projects/{{project}}/global/networks",
+	CreateVerb:     "POST",
+	UpdateVerb:     "PATCH",
+	DeleteVerb:     "DELETE",
+	AsyncActions:   []string{"create", "update", "delete"},
+	CollectionKey:  "items",
+	OutputFields: []OutputField{
+		{Name: "selfLink", Kind: FieldSelfLink},
+	},
+}
+
+func TestServerCRUD(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		route  Route
+		values map[string]string
+		update map[string]any
+	}{
+		"create on the self link": {
+			route:  testTopicRoute,
+			values: map[string]string{"project": "my-project", "name": "my-topic"},
+			update: map[string]any{"labels": map[string]any{"foo": "bar"}},
+		},
+		"create with an id parameter": {
+			route:  testInstanceRoute,
+			values: map[string]string{"cluster": "projects/my-project/locations/us-central1/clusters/my-cluster", "instance_id": "my-instance"},
+			update: map[string]any{"displayName": "updated"},
+		},
+		"create with a name": {
+			route:  testNetworkRoute,
+			values: map[string]string{"project": "my-project", "name": "my-network"},
+			update: map[string]any{"description": "updated"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testRouteCRUD(t, tc.route, tc.values, tc.update)
+		})
+	}
+}
+
+func TestServerOperations(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(newServer([]Route{testInstanceRoute}))
+	defer srv.Close()
+
+	op := doRequest(t, http.MethodPost, srv.URL+"/alloydb/projects/p/locations/l/clusters/c/instances?instanceId=i", map[string]any{}, http.StatusOK)
+	if diff := cmp.Diff("projects/p/locations/l/operations/operation-2", op["name"]); diff != "" {
+		t.Errorf("operation name mismatch (-want +got):\n%s", diff)
+	}
+	response, _ := op["response"].(map[string]any)
+	want := map[string]any{
+		"name":       "projects/p/locations/l/clusters/c/instances/i",
+		"createTime": response["createTime"],
+		"uid":        "1",
+		"state":      "READY",
+	}
+	if diff := cmp.Diff(want, response); diff != "" {
+		t.Errorf("operation response mismatch (-want +got):\n%s", diff)
+	}
+
+	got := doRequest(t, http.MethodGet, srv.URL+"/alloydb/"+op["name"].(string), nil, http.StatusOK)
+	if diff := cmp.Diff(op, got); diff != "" {
+		t.Errorf("polled operation mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerUpdates(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		method string
+		path   string
+		body   map[string]any
+		want   map[string]any
+	}{
+		"update mask": {
+			method: http.MethodPatch,
+			path:   "projects/p/topics/t?updateMask=labels,message_storage_policy.allowed_persistence_regions",
+			body: map[string]any{
+				"labels":               map[string]any{"new": "label"},
+				"kmsKeyName":           "ignored",
+				"messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-east1"}},
+			},
+			want: map[string]any{
+				"name":                 "t",
+				"labels":               map[string]any{"new": "label"},
+				"messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-east1"}},
+			},
+		},
+		"cleared field": {
+			method: http.MethodPatch,
+			path:   "projects/p/topics/t?updateMask=labels",
+			body:   map[string]any{},
+			want:   map[string]any{"name": "t", "messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-west1"}}},
+		},
+		"patch without a mask": {
+			method: http.MethodPatch,
+			path:   "projects/p/topics/t",
+			body:   map[string]any{"kmsKeyName": "key"},
+			want: map[string]any{
+				"name":                 "t",
+				"labels":               map[string]any{"old": "label"},
+				"kmsKeyName":           "key",
+				"messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-west1"}},
+			},
+		},
+		"method call": {
+			method: http.MethodPost,
+			path:   "projects/p/topics/t:setLabels",
+			body:   map[string]any{"labels": map[string]any{"new": "label"}},
+			want: map[string]any{
+				"name":                 "t",
+				"labels":               map[string]any{"new": "label"},
+				"messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-west1"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(newServer([]Route{testTopicRoute}))
+			defer srv.Close()
+			doRequest(t, http.MethodPut, srv.URL+"/pubsub/projects/p/topics/t", map[string]any{
+				"name":                 "t",
+				"labels":               map[string]any{"old": "label"},
+				"messageStoragePolicy": map[string]any{"allowedPersistenceRegions": []any{"us-west1"}},
+			}, http.StatusOK)
+
+			// Act
+			doRequest(t, tc.method, srv.URL+"/pubsub/"+tc.path, tc.body, http.StatusOK)
+
+			// Assert
+			got := doRequest(t, http.MethodGet, srv.URL+"/pubsub/projects/p/topics/t", nil, http.StatusOK)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("object mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(newServer([]Route{testNetworkRoute}))
+	defer srv.Close()
+	networks := srv.URL + "/compute/projects/p/global/networks"
+
+	doRequest(t, http.MethodPost, networks, map[string]any{"name": "n"}, http.StatusOK)
+	got := doRequest(t, http.MethodPost, networks, map[string]any{"name": "n"}, http.StatusConflict)
+	if diff := cmp.Diff("ALREADY_EXISTS", got["error"].(map[string]any)["status"]); diff != "" {
+		t.Errorf("status mismatch (-want +got):\n%s", diff)
+	}
+	got = doRequest(t, http.MethodPost, networks, map[string]any{}, http.StatusOK)
+	if diff := cmp.Diff(srv.URL+"/compute/projects/p/global/networks/fake-2", got["targetLink"]); diff != "" {
+		t.Errorf("generated id mismatch (-want +got):\n%s", diff)
+	}
+	doRequest(t, http.MethodGet, networks+"/missing", nil, http.StatusNotFound)
+	doRequest(t, http.MethodGet, srv.URL+"/storage/b/bucket", nil, http.StatusNotFound)
+}
+
+func TestServerLenientPaths(t *testing.T) {
+	t.Parallel()
+
+	r := testInstanceRoute
+	r.CollectionPath = "{{cluster}}/instances"
+	r.SelfLinkPath = "{{cluster}}/instances/{{instance_id}}"
+	r.CreatePath = "{{cluster}}/instances"
+	testRouteCRUD(t, r, map[string]string{"cluster": "projects/p/locations/l/clusters/c", "instance_id": "i"}, map[string]any{"displayName": "updated"})
+}
+
+func TestServerEndpointsAndCredentials(t *testing.T) {
+	t.Parallel()
+
+	s := newServer([]Route{testTopicRoute, testNetworkRoute})
+	want := map[string]string{
+		"GOOGLE_PUBSUB_CUSTOM_ENDPOINT":  "http://127.0.0.1:8080/pubsub/",
+		"GOOGLE_COMPUTE_CUSTOM_ENDPOINT": "http://127.0.0.1:8080/compute/",
+	}
+	if diff := cmp.Diff(want, s.Endpoints("http://127.0.0.1:8080/")); diff != "" {
+		t.Errorf("Endpoints() mismatch (-want +got):\n%s", diff)
+	}
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	if _, err := s.Credentials(srv.URL); err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	token := doRequest(t, http.MethodPost, srv.URL+tokenPath, map[string]any{}, http.StatusOK)
+	if token["access_token"] == nil {
+		t.Errorf("token endpoint returned %v, want an access token", token)
+	}
+}
+
+func TestPathRegexp(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		template string
+		path     string
+		want     map[string]string
+	}{
+		"fields": {
+			template: "projects/{{project}}/topics/{{name}}",
+			path:     "projects/p/topics/t",
+			want:     map[string]string{"project": "p", "name": "t"},
+		},
+		"field with slashes": {
+			template: "{{%cluster}}/instances/{{instance_id}}",
+			path:     "projects/p/locations/l/clusters/c/instances/i",
+			want:     map[string]string{"cluster": "projects/p/locations/l/clusters/c", "instance_id": "i"},
+		},
+		"no match": {
+			template: "projects/{{project}}/topics/{{name}}",
+			path:     "projects/p/topics/t/subscriptions",
+		},
+		"reference without a percent": {
+			template: "{{cluster}}/instances/{{instance_id}}",
+			path:     "projects/p/locations/l/clusters/c/instances/i",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := matchPath(pathRegexp(tc.template, "", false), tc.path)
+			if ok != (tc.want != nil) {
+				t.Fatalf("matchPath() = %v, %v, want a match: %v", got, ok, tc.want != nil)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("matchPath() mismatch (-want +got):\n%s", diff)
+			}
+			if ok && renderPath(tc.template, got) != tc.path {
+				t.Errorf("renderPath() = %q, want %q", renderPath(tc.template, got), tc.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package fakegcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRouteCRUD creates, reads, updates, lists and deletes an object of
+// route through a Server, the way the provider does. values are the fields
+// of the self link of the object, and update the fields changed by the
+// update, if the resource can be updated.
+func testRouteCRUD(t *testing.T, route Route, values map[string]string, update map[string]any) {
+	t.Helper()
+
+	srv := httptest.NewServer(newServer([]Route{route}))
+	defer srv.Close()
+	base := srv.URL + "/" + route.Product + "/"
+	selfLink := base + renderPath(route.SelfLinkPath, values)
+
+	// Create
+	createUrl := base + renderPath(route.CreatePath, values)
+	body := map[string]any{}
+	fields := pathFields(route.SelfLinkPath)
+	id := values[fields[len(fields)-1]]
+	id = id[strings.LastIndex(id, "/")+1:]
+	if route.CreateIdParam != "" {
+		createUrl += "?" + url.Values{route.CreateIdParam: {id}}.Encode()
+	} else if route.CreatePath != route.SelfLinkPath {
+		body["name"] = id
+	}
+	created := doRequest(t, route.CreateVerb, createUrl, body, http.StatusOK)
+	if slices.Contains(route.AsyncActions, "create") && created["done"] != true {
+		t.Errorf("create returned %v, want a done operation", created)
+	}
+
+	// Read
+	obj := doRequest(t, http.MethodGet, selfLink, nil, http.StatusOK)
+	for _, f := range route.OutputFields {
+		if _, ok := obj[f.Name]; !ok {
+			t.Errorf("output field %q of %v is not set", f.Name, obj)
+		}
+	}
+
+	// Update
+	if update != nil && route.UpdateVerb != "" {
+		updateUrl := selfLink
+		if route.UpdateMask {
+			var mask []string
+			for k := range update {
+				mask = append(mask, k)
+			}
+			sort.Strings(mask)
+			updateUrl += "?" + url.Values{"updateMask": {strings.Join(mask, ",")}}.Encode()
+		}
+		doRequest(t, route.UpdateVerb, updateUrl, update, http.StatusOK)
+		obj = doRequest(t, http.MethodGet, selfLink, nil, http.StatusOK)
+		for k, want := range update {
+			if diff := cmp.Diff(want, obj[k]); diff != "" {
+				t.Errorf("field %q after update mismatch (-want +got):\n%s", k, diff)
+			}
+		}
+	}
+
+	// List, unless the resource is a singleton
+	if route.CollectionPath != route.SelfLinkPath {
+		list := doRequest(t, http.MethodGet, base+renderPath(route.CollectionPath, values), nil, http.StatusOK)
+		if items, _ := list[route.CollectionKey].([]any); len(items) != 1 {
+			t.Errorf("list returned %v, want 1 object in %q", list, route.CollectionKey)
+		}
+	}
+
+	// Delete
+	doRequest(t, route.DeleteVerb, selfLink, nil, http.StatusOK)
+	doRequest(t, http.MethodGet, selfLink, nil, http.StatusNotFound)
+}
+
+// doRequest sends body to u and returns the decoded response, failing the
+// test if its status isn't wantStatus.
+func doRequest(t *testing.T, method, u string, body map[string]any, wantStatus int) map[string]any {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, u, &reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var got map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("%s %s: decoding response: %v", method, u, err)
+	}
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s returned %d %v, want %d", method, u, resp.StatusCode, got, wantStatus)
+	}
+	return got
+}
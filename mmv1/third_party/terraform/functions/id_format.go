@@ -0,0 +1,37 @@
+package functions
+
+import (
+	"regexp"
+)
+
+// idFormatField matches the fields of an id format, such as {{project}} and
+// {{%name}}, whose values may contain slashes.
+var idFormatField = regexp.MustCompile(`\{\{%?(\w+)\}\}`)
+
+// ParseIdFromFormats is reusable logic used in generated provider-defined functions
+// that parse the ids of a resource. It matches id against each regex in turn, and
+// returns the named submatches of the first one that matches.
+func ParseIdFromFormats(id string, regexes []*regexp.Regexp) (map[string]string, bool) {
+	for _, re := range regexes {
+		submatches := re.FindStringSubmatch(id)
+		if submatches == nil {
+			continue
+		}
+		fields := make(map[string]string)
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				fields[name] = submatches[i]
+			}
+		}
+		return fields, true
+	}
+	return nil, false
+}
+
+// BuildIdFromFormat is reusable logic used in generated provider-defined functions
+// that build the ids of a resource. It replaces the fields of format with their values.
+func BuildIdFromFormat(format string, fields map[string]string) string {
+	return idFormatField.ReplaceAllStringFunc(format, func(match string) string {
+		return fields[idFormatField.FindStringSubmatch(match)[1]]
+	})
+}
@@ -0,0 +1,98 @@
+package functions_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	tpg_functions "github.com/hashicorp/terraform-provider-google/google/functions"
+)
+
+func TestFunctionInternals_ParseIdFromFormats(t *testing.T) {
+
+	regexes := []*regexp.Regexp{
+		regexp.MustCompile("(?:^|/)projects/(?P<project>[^/]+)/zones/(?P<zone>[^/]+)/instances/(?P<name>[^/]+)$"),
+		regexp.MustCompile("^(?P<project>[^/]+)/(?P<zone>[^/]+)/(?P<name>[^/]+)$"),
+		regexp.MustCompile("^(?P<name>[^/]+)$"),
+	}
+
+	cases := map[string]struct {
+		Input          string
+		ExpectedFields map[string]string
+		ExpectMatch    bool
+	}{
+		"it parses a long form id": {
+			Input:          "projects/my-project/zones/us-central1-a/instances/my-instance",
+			ExpectedFields: map[string]string{"project": "my-project", "zone": "us-central1-a", "name": "my-instance"},
+			ExpectMatch:    true,
+		},
+		"it parses a self link": {
+			Input:          "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance",
+			ExpectedFields: map[string]string{"project": "my-project", "zone": "us-central1-a", "name": "my-instance"},
+			ExpectMatch:    true,
+		},
+		"it parses a short form id": {
+			Input:          "my-project/us-central1-a/my-instance",
+			ExpectedFields: map[string]string{"project": "my-project", "zone": "us-central1-a", "name": "my-instance"},
+			ExpectMatch:    true,
+		},
+		"it only returns the fields of the matching format": {
+			Input:          "my-instance",
+			ExpectedFields: map[string]string{"name": "my-instance"},
+			ExpectMatch:    true,
+		},
+		"it doesn't match an id with another shape": {
+			Input: "my-project/my-instance",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+
+			// Act
+			fields, ok := tpg_functions.ParseIdFromFormats(tc.Input, regexes)
+
+			// Assert
+			if ok != tc.ExpectMatch {
+				t.Fatalf("Expected match to be %t for input %s, got %t", tc.ExpectMatch, tc.Input, ok)
+			}
+			if diff := cmp.Diff(tc.ExpectedFields, fields); diff != "" {
+				t.Fatalf("Unexpected diff between expected and parsed fields: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionInternals_BuildIdFromFormat(t *testing.T) {
+
+	cases := map[string]struct {
+		Format     string
+		Fields     map[string]string
+		ExpectedId string
+	}{
+		"it replaces the fields of the format": {
+			Format:     "projects/{{project}}/zones/{{zone}}/instances/{{name}}",
+			Fields:     map[string]string{"project": "my-project", "zone": "us-central1-a", "name": "my-instance"},
+			ExpectedId: "projects/my-project/zones/us-central1-a/instances/my-instance",
+		},
+		"it replaces fields whose values may contain slashes": {
+			Format:     "{{project}}/{{%name}}",
+			Fields:     map[string]string{"project": "my-project", "name": "folder/my-object"},
+			ExpectedId: "my-project/folder/my-object",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+
+			// Act
+			id := tpg_functions.BuildIdFromFormat(tc.Format, tc.Fields)
+
+			// Assert
+			if id != tc.ExpectedId {
+				t.Fatalf("Expected id %s, got %s", tc.ExpectedId, id)
+			}
+		})
+	}
+}
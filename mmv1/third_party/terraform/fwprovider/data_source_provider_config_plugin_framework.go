@@ -45,9 +45,11 @@ type GoogleProviderConfigPluginFrameworkModel struct {
 	Zone                               types.String `tfsdk:"zone"`
 	Scopes                             types.List   `tfsdk:"scopes"`
 	//	omit Batching
+	//	omit RateLimit
 	UserProjectOverride                       types.Bool   `tfsdk:"user_project_override"`
 	RequestTimeout                            types.String `tfsdk:"request_timeout"`
 	RequestReason                             types.String `tfsdk:"request_reason"`
+	RequestLogFile                            types.String `tfsdk:"request_log_file"`
 	PollInterval                              types.String `tfsdk:"poll_interval"`
 	UniverseDomain                            types.String `tfsdk:"universe_domain"`
 	DefaultLabels                             types.Map    `tfsdk:"default_labels"`
@@ -131,6 +133,11 @@ func (d *GoogleProviderConfigPluginFrameworkDataSource) Schema(ctx context.Conte
 				MarkdownDescription: "The request_reason argument used to configure the provider.",
 				Computed:            true,
 			},
+			"request_log_file": schema.StringAttribute{
+				Description:         "The request_log_file argument used to configure the provider.",
+				MarkdownDescription: "The request_log_file argument used to configure the provider.",
+				Computed:            true,
+			},
 			"request_timeout": schema.StringAttribute{
 				Description:         "The request_timeout argument used to configure the provider.",
 				MarkdownDescription: "The request_timeout argument used to configure the provider.",
@@ -234,6 +241,7 @@ func (d *GoogleProviderConfigPluginFrameworkDataSource) Read(ctx context.Context
 
 	data.UserProjectOverride = types.BoolValue(d.providerConfig.UserProjectOverride)
 	data.RequestReason = types.StringValue(d.providerConfig.RequestReason)
+	data.RequestLogFile = types.StringValue(d.providerConfig.RequestLogFile)
 	data.RequestTimeout = types.StringValue(d.providerConfig.RequestTimeout.String())
 	data.PollInterval = types.StringValue(d.providerConfig.PollInterval.String())
 
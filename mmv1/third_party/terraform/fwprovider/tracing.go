@@ -0,0 +1,166 @@
+package fwprovider
+
+import (
+	"context"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// tracedServerInner is the interface of the plugin framework's provider server,
+// including the list resource and action RPCs the mux server asserts.
+type tracedServerInner interface {
+	tfprotov5.ProviderServer
+	tfprotov5.ListResourceServer
+	tfprotov5.ActionServer
+}
+
+// TraceServer returns the plugin framework provider server with a span around
+// each operation of its resources, data sources, ephemeral resources and
+// actions when tracing is enabled, the counterpart of
+// tpgresource.TraceResources. Spans are passed to the operations through their
+// context, which generated resources pass on to their config with
+// Config.WithTraceContext so that their requests, polls and locks are traced
+// under it.
+func TraceServer(server func() tfprotov5.ProviderServer) func() tfprotov5.ProviderServer {
+	if !transport_tpg.TracingEnabled() {
+		return server
+	}
+	return func() tfprotov5.ProviderServer {
+		inner := server()
+		if s, ok := inner.(tracedServerInner); ok {
+			return &tracedServer{s}
+		}
+		return inner
+	}
+}
+
+type tracedServer struct {
+	tracedServerInner
+}
+
+func (s *tracedServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	operation := "Update"
+	if isNull(req.PriorState) {
+		operation = "Create"
+	} else if isNull(req.PlannedState) {
+		operation = "Delete"
+	}
+	ctx, span := startSpan(ctx, req.TypeName, operation)
+	resp, err := s.tracedServerInner.ApplyResourceChange(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.ApplyResourceChangeResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Read")
+	resp, err := s.tracedServerInner.ReadResource(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.ReadResourceResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Import")
+	resp, err := s.tracedServerInner.ImportResourceState(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.ImportResourceStateResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Read")
+	resp, err := s.tracedServerInner.ReadDataSource(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.ReadDataSourceResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Open")
+	resp, err := s.tracedServerInner.OpenEphemeralResource(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.OpenEphemeralResourceResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Renew")
+	resp, err := s.tracedServerInner.RenewEphemeralResource(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.RenewEphemeralResourceResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+func (s *tracedServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	ctx, span := startSpan(ctx, req.TypeName, "Close")
+	resp, err := s.tracedServerInner.CloseEphemeralResource(ctx, req)
+	endSpan(span, err, responseDiagnostics(resp, func(r *tfprotov5.CloseEphemeralResourceResponse) []*tfprotov5.Diagnostic { return r.Diagnostics }))
+	return resp, err
+}
+
+// InvokeAction traces an action until it sends its completed event, as actions
+// run while Terraform reads their events.
+func (s *tracedServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	ctx, span := startSpan(ctx, req.ActionType, "Invoke")
+	stream, err := s.tracedServerInner.InvokeAction(ctx, req)
+	if err != nil || stream == nil || stream.Events == nil {
+		endSpan(span, err, nil)
+		return stream, err
+	}
+	events := stream.Events
+	return &tfprotov5.InvokeActionServerStream{
+		Events: iter.Seq[tfprotov5.InvokeActionEvent](func(yield func(tfprotov5.InvokeActionEvent) bool) {
+			var diags []*tfprotov5.Diagnostic
+			defer func() { endSpan(span, nil, diags) }()
+			for event := range events {
+				if completed, ok := event.Type.(tfprotov5.CompletedInvokeActionEventType); ok {
+					diags = completed.Diagnostics
+				}
+				if !yield(event) {
+					return
+				}
+			}
+		}),
+	}, nil
+}
+
+func startSpan(ctx context.Context, typeName, operation string) (context.Context, trace.Span) {
+	return transport_tpg.StartSpan(ctx, typeName+"."+operation, attribute.String("terraform.resource_type", typeName))
+}
+
+// endSpan ends span with the error of the RPC, or else the first error of its
+// diagnostics.
+func endSpan(span trace.Span, err error, diags []*tfprotov5.Diagnostic) {
+	if err == nil {
+		for _, d := range diags {
+			if d != nil && d.Severity == tfprotov5.DiagnosticSeverityError {
+				err = diagnosticError{d}
+				break
+			}
+		}
+	}
+	transport_tpg.EndSpan(span, err)
+}
+
+func responseDiagnostics[T any](resp *T, diags func(*T) []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	if resp == nil {
+		return nil
+	}
+	return diags(resp)
+}
+
+type diagnosticError struct {
+	*tfprotov5.Diagnostic
+}
+
+func (d diagnosticError) Error() string {
+	return d.Summary
+}
+
+func isNull(v *tfprotov5.DynamicValue) bool {
+	if v == nil {
+		return true
+	}
+	null, err := v.IsNull()
+	return err == nil && null
+}
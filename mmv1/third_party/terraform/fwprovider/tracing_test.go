@@ -0,0 +1,114 @@
+package fwprovider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testServer struct {
+	tracedServerInner
+}
+
+func (s *testServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	config := (&transport_tpg.Config{}).WithTraceContext(ctx)
+	if !trace.SpanFromContext(config.TraceContext()).SpanContext().IsValid() {
+		return nil, fmt.Errorf("the context passed to ApplyResourceChange has no span")
+	}
+	return &tfprotov5.ApplyResourceChangeResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "error creating the resource",
+		}},
+	}, nil
+}
+
+func (s *testServer) InvokeAction(ctx context.Context, req *tfprotov5.InvokeActionRequest) (*tfprotov5.InvokeActionServerStream, error) {
+	return &tfprotov5.InvokeActionServerStream{
+		Events: func(yield func(tfprotov5.InvokeActionEvent) bool) {
+			if !yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.ProgressInvokeActionEventType{Message: "invoking"}}) {
+				return
+			}
+			yield(tfprotov5.InvokeActionEvent{Type: tfprotov5.CompletedInvokeActionEventType{}})
+		},
+	}, nil
+}
+
+func TestTraceServer(t *testing.T) {
+	server := func() tfprotov5.ProviderServer { return &testServer{} }
+
+	if _, ok := TraceServer(server)().(*testServer); !ok {
+		t.Fatalf("TraceServer() wrapped the server with tracing disabled")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	t.Setenv(transport_tpg.TracingEndpointEnvVar, "http://localhost:4318")
+
+	s := TraceServer(server)().(tracedServerInner)
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}}
+	prior, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	planned, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     "google_test_resource",
+		PriorState:   &prior,
+		PlannedState: &planned,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange() returned an error: %s", err)
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("ApplyResourceChange() returned %d diagnostics, want the error of the resource", len(resp.Diagnostics))
+	}
+
+	stream, err := s.InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{ActionType: "google_test_action"})
+	if err != nil {
+		t.Fatalf("InvokeAction() returned an error: %s", err)
+	}
+	if len(recorder.Ended()) != 1 {
+		t.Fatalf("the span of InvokeAction() ended before its events were read")
+	}
+	events := 0
+	for range stream.Events {
+		events++
+	}
+	if events != 2 {
+		t.Errorf("got %d events, want 2", events)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "google_test_resource.Create" {
+		t.Errorf("span name = %q, want %q", spans[0].Name(), "google_test_resource.Create")
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("span status = %v, want an error", spans[0].Status().Code)
+	}
+	if spans[1].Name() != "google_test_action.Invoke" {
+		t.Errorf("span name = %q, want %q", spans[1].Name(), "google_test_action.Invoke")
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Errorf("span status = %v, want no error", spans[1].Status().Code)
+	}
+}
@@ -0,0 +1,889 @@
+package fwresource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+var _ tpgresource.TerraformResourceData = &ResourceData{}
+
+// ResourceData exposes the plan or state of a plugin framework resource the
+// way *schema.ResourceData exposes SDK resources, so that generated expanders,
+// flatteners and custom code work the same for both.
+//
+// Values are read in the shape the SDK uses: nested objects are single element
+// lists, sets are *schema.Set and Int64 values are int. Null and unknown values
+// read as the zero value of their type.
+type ResourceData struct {
+	ctx   context.Context
+	types map[string]attr.Type
+	// The values the data was created from, used to keep unset values null in
+	// the resulting state.
+	planned map[string]tftypes.Value
+	current map[string]tftypes.Value
+	prior   map[string]tftypes.Value
+	config  tftypes.Value
+	// Whether the state keeps the prior values, see Partial
+	partial bool
+
+	identitySchema map[string]*schema.Schema
+	identityRaw    map[string]string
+	identity       *schema.IdentityData
+
+	id         string
+	timeouts   map[string]time.Duration
+	moduleName string
+}
+
+// NewResourceData returns the data of a resource whose schema has type typ.
+// current is the plan (or the state when there is no plan) and prior is the
+// state HasChange compares it with, which may be null.
+func NewResourceData(ctx context.Context, typ attr.Type, current, prior tftypes.Value) (*ResourceData, error) {
+	object, ok := typ.(attr.TypeWithAttributeTypes)
+	if !ok {
+		return nil, fmt.Errorf("resource schema type %s isn't an object", typ)
+	}
+
+	d := &ResourceData{
+		ctx:      ctx,
+		types:    object.AttributeTypes(),
+		timeouts: map[string]time.Duration{},
+	}
+	var err error
+	if d.current, err = objectAttributes(current); err != nil {
+		return nil, err
+	}
+	if d.prior, err = objectAttributes(prior); err != nil {
+		return nil, err
+	}
+	d.planned = make(map[string]tftypes.Value, len(d.current))
+	for k, v := range d.current {
+		d.planned[k] = v
+	}
+	if id, ok := d.Get("id").(string); ok {
+		d.id = id
+	}
+	return d, nil
+}
+
+func objectAttributes(v tftypes.Value) (map[string]tftypes.Value, error) {
+	attrs := map[string]tftypes.Value{}
+	if v.Type() == nil || v.IsNull() || !v.IsKnown() {
+		return attrs, nil
+	}
+	if err := v.As(&attrs); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// SetTimeout sets the duration returned by Timeout(key).
+func (d *ResourceData) SetTimeout(key string, timeout time.Duration) {
+	d.timeouts[key] = timeout
+}
+
+// SetConfig sets the configuration returned by GetRawConfig. Only creates and
+// updates have one, other operations read a null configuration.
+func (d *ResourceData) SetConfig(config tftypes.Value) {
+	d.config = config
+}
+
+// LoadIdentity sets the schema and values of the identity returned by
+// Identity from identity, which is nil for resources without an identity.
+func (d *ResourceData) LoadIdentity(identity *tfsdk.ResourceIdentity) error {
+	if identity == nil {
+		return nil
+	}
+	d.identitySchema = map[string]*schema.Schema{}
+	for name, a := range identity.Schema.GetAttributes() {
+		t := schema.TypeString
+		if _, ok := a.GetType().(basetypes.Int64Type); ok {
+			t = schema.TypeInt
+		}
+		d.identitySchema[name] = &schema.Schema{Type: t, Optional: true}
+	}
+	attrs, err := objectAttributes(identity.Raw)
+	if err != nil {
+		return err
+	}
+	d.identityRaw = map[string]string{}
+	for name, v := range attrs {
+		pv, err := fromTerraform(v)
+		if err != nil {
+			return err
+		}
+		if pv != nil {
+			d.identityRaw[name] = fmt.Sprint(pv)
+		}
+	}
+	return nil
+}
+
+// SetModuleName sets the module_name of the provider_meta block, which is
+// appended to the user agent of requests.
+func (d *ResourceData) SetModuleName(moduleName string) {
+	d.moduleName = moduleName
+}
+
+// State returns the values of the resource, with the values that were unknown
+// in the plan and never set replaced by null.
+func (d *ResourceData) State() (tftypes.Value, error) {
+	attrTypes := map[string]tftypes.Type{}
+	attrs := map[string]tftypes.Value{}
+	for name, t := range d.types {
+		tt := t.TerraformType(d.ctx)
+		attrTypes[name] = tt
+
+		values := d.current
+		if d.partial {
+			values = d.prior
+		}
+		v, ok := values[name]
+		if name == "id" && d.id != "" {
+			v, ok = tftypes.NewValue(tt, d.id), true
+		} else if name == "id" {
+			ok = false
+		}
+		if !ok {
+			v = tftypes.NewValue(tt, nil)
+		}
+		v, err := tftypes.Transform(v, func(_ *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+			if !v.IsKnown() {
+				return tftypes.NewValue(v.Type(), nil), nil
+			}
+			return v, nil
+		})
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("error setting %s: %w", name, err)
+		}
+		attrs[name] = v
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, attrs), nil
+}
+
+// Attributes returns the names of the top level attributes of the resource.
+func (d *ResourceData) Attributes() []string {
+	names := make([]string, 0, len(d.types))
+	for name := range d.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetIdentity sets the attributes of identity that are set on the resource,
+// or on the identity returned by Identity.
+func (d *ResourceData) SetIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if identity == nil {
+		return diags
+	}
+	for name := range identity.Schema.GetAttributes() {
+		v, ok := d.GetOk(name)
+		if d.identity != nil {
+			if iv, iok := d.identity.GetOk(name); iok {
+				v, ok = iv, true
+			}
+		}
+		if ok {
+			diags.Append(identity.SetAttribute(ctx, path.Root(name), v)...)
+		}
+	}
+	return diags
+}
+
+// SetFromIdentity sets the attributes of the resource that are set on
+// identity, for resources imported by their identity.
+func (d *ResourceData) SetFromIdentity(identity *tfsdk.ResourceIdentity) error {
+	if identity == nil {
+		return nil
+	}
+	attrs, err := objectAttributes(identity.Raw)
+	if err != nil {
+		return err
+	}
+	for name, v := range attrs {
+		pv, err := fromTerraform(v)
+		if err != nil {
+			return err
+		}
+		if pv == nil {
+			continue
+		}
+		if err := d.Set(name, pv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *ResourceData) Id() string {
+	return d.id
+}
+
+func (d *ResourceData) SetId(id string) {
+	d.id = id
+}
+
+func (d *ResourceData) Get(key string) interface{} {
+	v, _ := d.get(d.current, key)
+	return v
+}
+
+func (d *ResourceData) GetOk(key string) (interface{}, bool) {
+	v, exists := d.get(d.current, key)
+	return v, exists && !isZero(v)
+}
+
+func (d *ResourceData) GetOkExists(key string) (interface{}, bool) {
+	return d.get(d.current, key)
+}
+
+func (d *ResourceData) GetChange(key string) (interface{}, interface{}) {
+	o, _ := d.get(d.prior, key)
+	n, _ := d.get(d.current, key)
+	return o, n
+}
+
+func (d *ResourceData) HasChange(key string) bool {
+	o, n := d.GetChange(key)
+	return !reflect.DeepEqual(plain(o), plain(n))
+}
+
+func (d *ResourceData) HasChanges(keys ...string) bool {
+	for _, key := range keys {
+		if d.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Set sets a top level attribute from a value in the shape used by the SDK.
+// Values that are empty and were null in the plan stay null.
+func (d *ResourceData) Set(key string, value interface{}) error {
+	t, ok := d.types[key]
+	if !ok {
+		return fmt.Errorf("unknown attribute %q", key)
+	}
+	v, err := d.toTerraform(t, value, d.planned[key])
+	if err != nil {
+		return fmt.Errorf("error setting %s: %w", key, err)
+	}
+	d.current[key] = v
+	return nil
+}
+
+// Partial makes State return the prior values of the resource when on, like
+// the SDK does for updates that fail partway.
+func (d *ResourceData) Partial(on bool) {
+	d.partial = on
+}
+
+// GetRawConfig returns the configuration set by SetConfig, or a null value of
+// the resource type.
+func (d *ResourceData) GetRawConfig() cty.Value {
+	if d.config.Type() == nil {
+		attrTypes := map[string]tftypes.Type{}
+		for name, t := range d.types {
+			attrTypes[name] = t.TerraformType(d.ctx)
+		}
+		return cty.NullVal(ctyType(tftypes.Object{AttributeTypes: attrTypes}))
+	}
+	v, err := toCty(d.config)
+	if err != nil {
+		log.Printf("[WARN] Error reading the configuration: %s", err)
+		return cty.NullVal(ctyType(d.config.Type()))
+	}
+	return v
+}
+
+// Identity returns the identity loaded by LoadIdentity. Values set on it are
+// saved by SetIdentity.
+func (d *ResourceData) Identity() (*schema.IdentityData, error) {
+	if d.identity != nil {
+		return d.identity, nil
+	}
+	if d.identitySchema == nil {
+		return nil, fmt.Errorf("resource does not have an identity schema")
+	}
+	r := &schema.Resource{
+		Identity: &schema.ResourceIdentity{
+			SchemaFunc: func() map[string]*schema.Schema {
+				return d.identitySchema
+			},
+		},
+	}
+	identity, err := r.Data(&terraform.InstanceState{ID: d.id, Identity: d.identityRaw}).Identity()
+	if err != nil {
+		return nil, err
+	}
+	d.identity = identity
+	return identity, nil
+}
+
+func (d *ResourceData) GetProviderMeta(dst interface{}) error {
+	if m, ok := dst.(*transport_tpg.ProviderMeta); ok {
+		m.ModuleName = d.moduleName
+	}
+	return nil
+}
+
+func (d *ResourceData) Timeout(key string) time.Duration {
+	return d.timeouts[key]
+}
+
+// HandleNotFoundError is the framework counterpart to transport_tpg.HandleNotFoundError:
+// it clears the ID of d when err is a 404, so that the resource is removed from state.
+func HandleNotFoundError(err error, d *ResourceData, resource string) error {
+	if transport_tpg.IsGoogleApiErrorWithCode(err, 404) {
+		log.Printf("[WARN] Removing %s because it's gone", resource)
+		d.SetId("")
+		return nil
+	}
+	return fmt.Errorf("Error when reading or editing %s: %w", resource, err)
+}
+
+// get returns the value at key, a dot separated path such as "config.0.name",
+// and whether it's set.
+func (d *ResourceData) get(values map[string]tftypes.Value, key string) (interface{}, bool) {
+	parts := strings.Split(key, ".")
+	t, ok := d.types[parts[0]]
+	if !ok {
+		return nil, false
+	}
+	raw, err := fromTerraform(values[parts[0]])
+	if err != nil {
+		log.Printf("[WARN] Error reading %s: %s", parts[0], err)
+	}
+	exists := raw != nil
+
+	// Whether t is the element type of a list or set, as opposed to the type
+	// of an attribute
+	inList := false
+	for _, part := range parts[1:] {
+		switch tt := t.(type) {
+		case basetypes.ListType:
+			t, inList = tt.ElemType, true
+		case basetypes.SetType:
+			t, inList = tt.ElemType, true
+		case basetypes.MapType:
+			t, inList = tt.ElemType, false
+		case attr.TypeWithAttributeTypes:
+			if _, err := strconv.Atoi(part); err == nil && !inList {
+				// The index into the single element list of a nested object
+				inList = true
+				continue
+			}
+			t, inList = tt.AttributeTypes()[part], false
+		}
+		if t == nil {
+			return nil, false
+		}
+		raw, exists = index(raw, part)
+	}
+	if ot, ok := t.(attr.TypeWithAttributeTypes); ok && inList {
+		return objectWithZeroValues(ot, raw), exists
+	}
+	return withZeroValues(t, raw), exists
+}
+
+func index(v interface{}, part string) (interface{}, bool) {
+	switch v := v.(type) {
+	case []interface{}:
+		i, err := strconv.Atoi(part)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return v[i], v[i] != nil
+	case map[string]interface{}:
+		e, ok := v[part]
+		return e, ok && e != nil
+	}
+	return nil, false
+}
+
+// fromTerraform converts v to plain Go values, with objects as maps and null
+// or unknown values as nil.
+func fromTerraform(v tftypes.Value) (interface{}, error) {
+	if v.Type() == nil || v.IsNull() || !v.IsKnown() {
+		return nil, nil
+	}
+	t := v.Type()
+	switch {
+	case t.Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case t.Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return b, err
+	case t.Is(tftypes.Number):
+		var f big.Float
+		if err := v.As(&f); err != nil {
+			return nil, err
+		}
+		if f.IsInt() {
+			i, _ := f.Int64()
+			return int(i), nil
+		}
+		n, _ := f.Float64()
+		return n, nil
+	case t.Is(tftypes.List{}), t.Is(tftypes.Set{}), t.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		l := make([]interface{}, 0, len(elems))
+		for _, e := range elems {
+			pe, err := fromTerraform(e)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, pe)
+		}
+		return l, nil
+	case t.Is(tftypes.Map{}), t.Is(tftypes.Object{}):
+		elems := map[string]tftypes.Value{}
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(elems))
+		for k, e := range elems {
+			pe, err := fromTerraform(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = pe
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// ctyType returns the cty type of the Terraform type t.
+func ctyType(t tftypes.Type) cty.Type {
+	switch t := t.(type) {
+	case tftypes.List:
+		return cty.List(ctyType(t.ElementType))
+	case tftypes.Set:
+		return cty.Set(ctyType(t.ElementType))
+	case tftypes.Map:
+		return cty.Map(ctyType(t.ElementType))
+	case tftypes.Object:
+		attrs := make(map[string]cty.Type, len(t.AttributeTypes))
+		for k, at := range t.AttributeTypes {
+			attrs[k] = ctyType(at)
+		}
+		return cty.Object(attrs)
+	case tftypes.Tuple:
+		elems := make([]cty.Type, 0, len(t.ElementTypes))
+		for _, et := range t.ElementTypes {
+			elems = append(elems, ctyType(et))
+		}
+		return cty.Tuple(elems)
+	}
+	switch {
+	case t.Is(tftypes.String):
+		return cty.String
+	case t.Is(tftypes.Number):
+		return cty.Number
+	case t.Is(tftypes.Bool):
+		return cty.Bool
+	}
+	return cty.DynamicPseudoType
+}
+
+// toCty converts v to the cty value GetRawConfig returns for SDK resources.
+func toCty(v tftypes.Value) (cty.Value, error) {
+	t := ctyType(v.Type())
+	if !v.IsKnown() {
+		return cty.UnknownVal(t), nil
+	}
+	if v.IsNull() {
+		return cty.NullVal(t), nil
+	}
+	switch {
+	case t.Equals(cty.String):
+		var s string
+		err := v.As(&s)
+		return cty.StringVal(s), err
+	case t.Equals(cty.Bool):
+		var b bool
+		err := v.As(&b)
+		return cty.BoolVal(b), err
+	case t.Equals(cty.Number):
+		var f big.Float
+		err := v.As(&f)
+		return cty.NumberVal(&f), err
+	case t.IsListType(), t.IsSetType(), t.IsTupleType():
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return cty.NilVal, err
+		}
+		vals := make([]cty.Value, 0, len(elems))
+		for _, e := range elems {
+			ev, err := toCty(e)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals = append(vals, ev)
+		}
+		switch {
+		case t.IsTupleType():
+			return cty.TupleVal(vals), nil
+		case len(vals) == 0 && t.IsListType():
+			return cty.ListValEmpty(t.ElementType()), nil
+		case len(vals) == 0:
+			return cty.SetValEmpty(t.ElementType()), nil
+		case t.IsListType():
+			return cty.ListVal(vals), nil
+		}
+		return cty.SetVal(vals), nil
+	case t.IsMapType(), t.IsObjectType():
+		elems := map[string]tftypes.Value{}
+		if err := v.As(&elems); err != nil {
+			return cty.NilVal, err
+		}
+		vals := make(map[string]cty.Value, len(elems))
+		for k, e := range elems {
+			ev, err := toCty(e)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[k] = ev
+		}
+		switch {
+		case t.IsObjectType():
+			return cty.ObjectVal(vals), nil
+		case len(vals) == 0:
+			return cty.MapValEmpty(t.ElementType()), nil
+		}
+		return cty.MapVal(vals), nil
+	}
+	return cty.NilVal, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// withZeroValues gives v, read by fromTerraform, the shape of the SDK value of
+// type t: nils are replaced by zero values, sets become *schema.Set and nested
+// objects become single element lists.
+func withZeroValues(t attr.Type, v interface{}) interface{} {
+	switch tt := t.(type) {
+	case basetypes.StringType:
+		if v == nil {
+			return ""
+		}
+	case basetypes.BoolType:
+		if v == nil {
+			return false
+		}
+	case basetypes.Int64Type:
+		if v == nil {
+			return 0
+		}
+		if f, ok := v.(float64); ok {
+			return int(f)
+		}
+	case basetypes.Float64Type, basetypes.NumberType:
+		switch n := v.(type) {
+		case nil:
+			return 0.0
+		case int:
+			return float64(n)
+		}
+	case basetypes.ListType:
+		return listWithZeroValues(tt.ElemType, v)
+	case basetypes.SetType:
+		return schema.NewSet(hashValue, listWithZeroValues(tt.ElemType, v))
+	case basetypes.MapType:
+		m := map[string]interface{}{}
+		if vm, ok := v.(map[string]interface{}); ok {
+			for k, e := range vm {
+				m[k] = withZeroValues(tt.ElemType, e)
+			}
+		}
+		return m
+	case attr.TypeWithAttributeTypes:
+		if _, ok := v.(map[string]interface{}); !ok {
+			return []interface{}{}
+		}
+		return []interface{}{objectWithZeroValues(tt, v)}
+	}
+	return v
+}
+
+func listWithZeroValues(elemType attr.Type, v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	ret := make([]interface{}, 0, len(l))
+	for _, e := range l {
+		if ot, ok := elemType.(attr.TypeWithAttributeTypes); ok {
+			ret = append(ret, objectWithZeroValues(ot, e))
+			continue
+		}
+		ret = append(ret, withZeroValues(elemType, e))
+	}
+	return ret
+}
+
+func objectWithZeroValues(t attr.TypeWithAttributeTypes, v interface{}) map[string]interface{} {
+	vm, _ := v.(map[string]interface{})
+	m := map[string]interface{}{}
+	for k, at := range t.AttributeTypes() {
+		m[k] = withZeroValues(at, vm[k])
+	}
+	return m
+}
+
+// toTerraform converts the SDK shaped value v to a value of type t. Empty
+// values stay null if they were null in planned.
+func (d *ResourceData) toTerraform(t attr.Type, v interface{}, planned tftypes.Value) (tftypes.Value, error) {
+	tt := t.TerraformType(d.ctx)
+	v = plain(v)
+	if v == nil || (isZero(v) && (planned.Type() == nil || planned.IsNull())) {
+		return tftypes.NewValue(tt, nil), nil
+	}
+
+	switch t := t.(type) {
+	case basetypes.StringType:
+		if s, ok := v.(string); ok {
+			return tftypes.NewValue(tt, s), nil
+		}
+		return tftypes.NewValue(tt, fmt.Sprint(v)), nil
+	case basetypes.BoolType:
+		if b, ok := v.(bool); ok {
+			return tftypes.NewValue(tt, b), nil
+		}
+		b, err := strconv.ParseBool(fmt.Sprint(v))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tt, b), nil
+	case basetypes.Int64Type:
+		i, err := toInt64(v)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tt, new(big.Float).SetInt64(i)), nil
+	case basetypes.Float64Type, basetypes.NumberType:
+		f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(tt, big.NewFloat(f)), nil
+	case basetypes.ListType:
+		return d.listToTerraform(tt, t.ElemType, v, planned, false)
+	case basetypes.SetType:
+		return d.listToTerraform(tt, t.ElemType, v, planned, true)
+	case basetypes.MapType:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected a map, got %T", v)
+		}
+		plannedElems, _ := objectAttributes(planned)
+		elems := make(map[string]tftypes.Value, len(m))
+		for k, e := range m {
+			ev, err := d.toTerraform(t.ElemType, e, plannedElems[k])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = ev
+		}
+		return tftypes.NewValue(tt, elems), nil
+	case attr.TypeWithAttributeTypes:
+		// Nested objects are single element lists in the SDK
+		if l, ok := v.([]interface{}); ok {
+			if len(l) == 0 {
+				return tftypes.NewValue(tt, nil), nil
+			}
+			v = l[0]
+		}
+		return d.objectToTerraform(t, v, planned)
+	}
+	return tftypes.Value{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func (d *ResourceData) listToTerraform(tt tftypes.Type, elemType attr.Type, v interface{}, planned tftypes.Value, isSet bool) (tftypes.Value, error) {
+	l, ok := v.([]interface{})
+	if !ok {
+		return tftypes.Value{}, fmt.Errorf("expected a list, got %T", v)
+	}
+	var plannedElems []tftypes.Value
+	if planned.Type() != nil && planned.IsKnown() && !planned.IsNull() {
+		if err := planned.As(&plannedElems); err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+
+	elems := make([]tftypes.Value, 0, len(l))
+	used := map[int]bool{}
+	for i, e := range l {
+		var p tftypes.Value
+		if isSet {
+			// Set elements are matched with the planned element they agree with
+			ev, err := d.toTerraform(elemType, e, tftypes.Value{})
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			for j, pe := range plannedElems {
+				if !used[j] && agrees(pe, ev) {
+					used[j] = true
+					p = pe
+					break
+				}
+			}
+		} else if i < len(plannedElems) {
+			p = plannedElems[i]
+		}
+		ev, err := d.toTerraform(elemType, e, p)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		elems = append(elems, ev)
+	}
+	return tftypes.NewValue(tt, elems), nil
+}
+
+func (d *ResourceData) objectToTerraform(t attr.TypeWithAttributeTypes, v interface{}, planned tftypes.Value) (tftypes.Value, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return tftypes.Value{}, fmt.Errorf("expected an object, got %T", v)
+	}
+	plannedAttrs, err := objectAttributes(planned)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	attrs := map[string]tftypes.Value{}
+	for k, at := range t.AttributeTypes() {
+		e, ok := m[k]
+		if !ok {
+			// Fields the flattener doesn't return, for example ignore_read
+			// fields, keep their planned value
+			if p, ok := plannedAttrs[k]; ok {
+				attrs[k] = p
+				continue
+			}
+		}
+		ev, err := d.toTerraform(at, e, plannedAttrs[k])
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("%s: %w", k, err)
+		}
+		attrs[k] = ev
+	}
+	return tftypes.NewValue(t.TerraformType(d.ctx), attrs), nil
+}
+
+// agrees reports whether every known, non-null part of planned equals v.
+func agrees(planned, v tftypes.Value) bool {
+	if planned.IsNull() || !planned.IsKnown() {
+		return true
+	}
+	if !planned.Type().Is(tftypes.Object{}) {
+		return planned.Equal(v)
+	}
+	p, err := objectAttributes(planned)
+	if err != nil {
+		return false
+	}
+	o, err := objectAttributes(v)
+	if err != nil {
+		return false
+	}
+	for k, pv := range p {
+		if !agrees(pv, o[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// plain converts the values returned by flatteners, which may use typed
+// slices and maps or *schema.Set, to []interface{} and map[string]interface{}.
+func plain(v interface{}) interface{} {
+	if s, ok := v.(*schema.Set); ok {
+		if s == nil {
+			return nil
+		}
+		v = s.List()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return plain(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		l := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			l = append(l, plain(rv.Index(i).Interface()))
+		}
+		return l
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = plain(iter.Value().Interface())
+		}
+		return m
+	}
+	return v
+}
+
+func isZero(v interface{}) bool {
+	rv := reflect.ValueOf(plain(v))
+	if !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func toInt64(v interface{}) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float()), nil
+	case reflect.String:
+		return strconv.ParseInt(rv.String(), 10, 64)
+	}
+	return 0, fmt.Errorf("expected an integer, got %T", v)
+}
+
+// hashValue hashes set elements by their printed value, since fmt prints map
+// keys in sorted order.
+func hashValue(v interface{}) int {
+	return tpgresource.Hashcode(fmt.Sprintf("%v", plain(v)))
+}
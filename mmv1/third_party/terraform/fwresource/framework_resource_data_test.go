@@ -0,0 +1,313 @@
+package fwresource
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdkschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var testResourceDataSchema = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Required: true,
+		},
+		"description": schema.StringAttribute{
+			Optional: true,
+		},
+		"size": schema.Int64Attribute{
+			Optional: true,
+		},
+		"tags": schema.SetAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"create_time": schema.StringAttribute{
+			Computed: true,
+		},
+	},
+	Blocks: map[string]schema.Block{
+		"config": schema.ListNestedBlock{
+			NestedObject: schema.NestedBlockObject{
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Optional: true,
+					},
+					"mode": schema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+		},
+	},
+}
+
+func testResourceDataValue(t *testing.T, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+	typ := testResourceDataSchema.Type().TerraformType(context.Background()).(tftypes.Object)
+	attrs := map[string]tftypes.Value{}
+	for name, at := range typ.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+			continue
+		}
+		attrs[name] = tftypes.NewValue(at, nil)
+	}
+	return tftypes.NewValue(typ, attrs)
+}
+
+func testConfigValue(enabled bool, mode interface{}) tftypes.Value {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"enabled": tftypes.Bool,
+		"mode":    tftypes.String,
+	}}
+	return tftypes.NewValue(tftypes.List{ElementType: objectType}, []tftypes.Value{
+		tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"enabled": tftypes.NewValue(tftypes.Bool, enabled),
+			"mode":    tftypes.NewValue(tftypes.String, mode),
+		}),
+	})
+}
+
+func TestResourceDataGet(t *testing.T) {
+	ctx := context.Background()
+	plan := testResourceDataValue(t, map[string]tftypes.Value{
+		"name":        tftypes.NewValue(tftypes.String, "foo"),
+		"size":        tftypes.NewValue(tftypes.Number, big.NewFloat(3)),
+		"tags":        tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "a")}),
+		"config":      testConfigValue(true, nil),
+		"create_time": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	d, err := NewResourceData(ctx, testResourceDataSchema.Type(), plan, testResourceDataValue(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := d.Get("name"); got != "foo" {
+		t.Errorf("Get(name) = %#v, want \"foo\"", got)
+	}
+	if got := d.Get("size"); got != 3 {
+		t.Errorf("Get(size) = %#v, want 3", got)
+	}
+	if got := d.Get("description"); got != "" {
+		t.Errorf("Get(description) = %#v, want \"\"", got)
+	}
+	if got := d.Get("create_time"); got != "" {
+		t.Errorf("Get(create_time) = %#v, want \"\"", got)
+	}
+	if tags, ok := d.Get("tags").(*sdkschema.Set); !ok || !reflect.DeepEqual(tags.List(), []interface{}{"a"}) {
+		t.Errorf("Get(tags) = %#v, want a set of \"a\"", d.Get("tags"))
+	}
+	wantConfig := []interface{}{map[string]interface{}{"enabled": true, "mode": ""}}
+	if got := d.Get("config"); !reflect.DeepEqual(got, wantConfig) {
+		t.Errorf("Get(config) = %#v, want %#v", got, wantConfig)
+	}
+	if got := d.Get("config.0.enabled"); got != true {
+		t.Errorf("Get(config.0.enabled) = %#v, want true", got)
+	}
+	if _, ok := d.GetOkExists("config.0.mode"); ok {
+		t.Errorf("GetOkExists(config.0.mode) is set, want unset")
+	}
+	if _, ok := d.GetOk("description"); ok {
+		t.Errorf("GetOk(description) is set, want unset")
+	}
+	if !d.HasChange("name") || d.HasChange("description") {
+		t.Errorf("HasChange(name) = %t, HasChange(description) = %t, want true and false", d.HasChange("name"), d.HasChange("description"))
+	}
+}
+
+func TestResourceDataState(t *testing.T) {
+	ctx := context.Background()
+	plan := testResourceDataValue(t, map[string]tftypes.Value{
+		"name":        tftypes.NewValue(tftypes.String, "foo"),
+		"config":      testConfigValue(true, nil),
+		"create_time": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"id":          tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	d, err := NewResourceData(ctx, testResourceDataSchema.Type(), plan, testResourceDataValue(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetId("projects/p/things/foo")
+	for k, v := range map[string]interface{}{
+		"name": "foo",
+		// Empty values of fields that weren't configured stay null
+		"description": "",
+		"tags":        []string{},
+		"config": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"mode":    "",
+		}},
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatalf("Set(%s): %s", k, err)
+		}
+	}
+
+	got, err := d.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := testResourceDataValue(t, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "projects/p/things/foo"),
+		"name":   tftypes.NewValue(tftypes.String, "foo"),
+		"config": testConfigValue(true, nil),
+	})
+	if !got.Equal(want) {
+		t.Errorf("State() = %s, want %s", got, want)
+	}
+}
+
+func TestResourceDataPartial(t *testing.T) {
+	ctx := context.Background()
+	state := testResourceDataValue(t, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "projects/p/things/foo"),
+		"name":        tftypes.NewValue(tftypes.String, "foo"),
+		"description": tftypes.NewValue(tftypes.String, "old"),
+	})
+	plan := testResourceDataValue(t, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "projects/p/things/foo"),
+		"name":        tftypes.NewValue(tftypes.String, "foo"),
+		"description": tftypes.NewValue(tftypes.String, "new"),
+	})
+
+	d, err := NewResourceData(ctx, testResourceDataSchema.Type(), plan, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Partial(true)
+	if err := d.Set("description", "newer"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(state) {
+		t.Errorf("State() in partial mode = %s, want the prior state %s", got, state)
+	}
+
+	d.Partial(false)
+	got, err = d.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := got.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	if want := tftypes.NewValue(tftypes.String, "newer"); !attrs["description"].Equal(want) {
+		t.Errorf("State() description = %s, want %s", attrs["description"], want)
+	}
+}
+
+func TestResourceDataGetRawConfig(t *testing.T) {
+	ctx := context.Background()
+	config := testResourceDataValue(t, map[string]tftypes.Value{
+		"name":   tftypes.NewValue(tftypes.String, "foo"),
+		"size":   tftypes.NewValue(tftypes.Number, 3),
+		"tags":   tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "a")}),
+		"config": testConfigValue(true, nil),
+	})
+
+	d, err := NewResourceData(ctx, testResourceDataSchema.Type(), config, testResourceDataValue(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.GetRawConfig(); !got.IsNull() || !got.Type().IsObjectType() {
+		t.Errorf("GetRawConfig() without a configuration = %#v, want a null object", got)
+	}
+
+	d.SetConfig(config)
+	got := d.GetRawConfig()
+	if v := got.GetAttr("name"); !v.RawEquals(cty.StringVal("foo")) {
+		t.Errorf("GetRawConfig() name = %#v, want foo", v)
+	}
+	if v := got.GetAttr("size"); !v.RawEquals(cty.NumberIntVal(3)) {
+		t.Errorf("GetRawConfig() size = %#v, want 3", v)
+	}
+	if v := got.GetAttr("description"); !v.IsNull() {
+		t.Errorf("GetRawConfig() description = %#v, want null", v)
+	}
+	if v := got.GetAttr("tags"); !v.RawEquals(cty.SetVal([]cty.Value{cty.StringVal("a")})) {
+		t.Errorf("GetRawConfig() tags = %#v, want [a]", v)
+	}
+	if v := got.GetAttr("config").Index(cty.NumberIntVal(0)).GetAttr("enabled"); !v.RawEquals(cty.True) {
+		t.Errorf("GetRawConfig() config.0.enabled = %#v, want true", v)
+	}
+}
+
+func TestResourceDataIdentity(t *testing.T) {
+	ctx := context.Background()
+	identitySchema := identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+			},
+			"size": identityschema.Int64Attribute{
+				OptionalForImport: true,
+			},
+		},
+	}
+	identityType := identitySchema.Type().TerraformType(ctx)
+	prior := &tfsdk.ResourceIdentity{
+		Schema: identitySchema,
+		Raw: tftypes.NewValue(identityType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "foo"),
+			"size": tftypes.NewValue(tftypes.Number, nil),
+		}),
+	}
+	state := testResourceDataValue(t, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "projects/p/things/foo"),
+		"name": tftypes.NewValue(tftypes.String, "foo"),
+	})
+
+	d, err := NewResourceData(ctx, testResourceDataSchema.Type(), state, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Identity(); err == nil {
+		t.Error("Identity() without an identity schema succeeded, want an error")
+	}
+	if err := d.LoadIdentity(prior); err != nil {
+		t.Fatal(err)
+	}
+	identity, err := d.Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := identity.Get("name"); got != "foo" {
+		t.Errorf("Identity() name = %v, want foo", got)
+	}
+	if err := identity.Set("size", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := &tfsdk.ResourceIdentity{
+		Schema: identitySchema,
+		Raw:    tftypes.NewValue(identityType, nil),
+	}
+	if diags := d.SetIdentity(ctx, resp); diags.HasError() {
+		t.Fatal(diags)
+	}
+	want := tftypes.NewValue(identityType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "foo"),
+		"size": tftypes.NewValue(tftypes.Number, 3),
+	})
+	if !resp.Raw.Equal(want) {
+		t.Errorf("SetIdentity() = %s, want %s", resp.Raw, want)
+	}
+}
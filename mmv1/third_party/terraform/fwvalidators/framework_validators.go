@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
 	"regexp"
 	"strings"
@@ -352,3 +353,63 @@ func (v TopicPrefixValidator) ValidateString(ctx context.Context, req validator.
 func NewTopicPrefixValidator() validator.String {
 	return TopicPrefixValidator{}
 }
+
+// IP CIDR Range Validator
+type ipCidrRangeValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipCidrRangeValidator) Description(_ context.Context) string {
+	return "value expected to be an IP CIDR range, such as 10.0.0.0/8"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipCidrRangeValidator) MarkdownDescription(ctx context.Context) string {
+	return "value expected to be an IP CIDR range, such as `10.0.0.0/8`"
+}
+
+// ValidateString performs the validation.
+func (v ipCidrRangeValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Invalid IP CIDR range", fmt.Sprintf("%q is not a valid IP CIDR range: %s", value, err))
+	}
+}
+
+func IpCidrRangeValidator() validator.String {
+	return ipCidrRangeValidator{}
+}
+
+// IP Address Validator
+type ipAddressValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipAddressValidator) Description(_ context.Context) string {
+	return "value expected to be an IPv4 or IPv6 address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v ipAddressValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+
+	if net.ParseIP(value) == nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Invalid IP address", fmt.Sprintf("could not parse %q to IP address", value))
+	}
+}
+
+func IpAddressValidator() validator.String {
+	return ipAddressValidator{}
+}
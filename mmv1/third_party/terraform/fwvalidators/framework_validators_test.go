@@ -539,3 +539,74 @@ func TestTopicPrefixValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestIpValidators(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		validator   validator.String
+		value       types.String
+		expectError bool
+	}
+
+	tests := map[string]testCase{
+		"valid IPv4 CIDR range": {
+			validator: fwvalidators.IpCidrRangeValidator(),
+			value:     types.StringValue("10.0.0.0/8"),
+		},
+		"valid IPv6 CIDR range": {
+			validator: fwvalidators.IpCidrRangeValidator(),
+			value:     types.StringValue("2001:db8::/32"),
+		},
+		"CIDR range without prefix length": {
+			validator:   fwvalidators.IpCidrRangeValidator(),
+			value:       types.StringValue("10.0.0.0"),
+			expectError: true,
+		},
+		"null CIDR range": {
+			validator: fwvalidators.IpCidrRangeValidator(),
+			value:     types.StringNull(),
+		},
+		"valid IPv4 address": {
+			validator: fwvalidators.IpAddressValidator(),
+			value:     types.StringValue("192.168.0.1"),
+		},
+		"valid IPv6 address": {
+			validator: fwvalidators.IpAddressValidator(),
+			value:     types.StringValue("2001:db8::1"),
+		},
+		"IP address with prefix length": {
+			validator:   fwvalidators.IpAddressValidator(),
+			value:       types.StringValue("192.168.0.1/32"),
+			expectError: true,
+		},
+		"unknown IP address": {
+			validator: fwvalidators.IpAddressValidator(),
+			value:     types.StringUnknown(),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := validator.StringRequest{
+				Path:           path.Root("test_ip"),
+				PathExpression: path.MatchRoot("test_ip"),
+				ConfigValue:    test.value,
+			}
+			response := validator.StringResponse{}
+
+			test.validator.ValidateString(context.Background(), request, &response)
+
+			if test.expectError && !response.Diagnostics.HasError() {
+				t.Errorf("expected error, got none for value: %q", test.value.ValueString())
+			}
+
+			if !test.expectError && response.Diagnostics.HasError() {
+				t.Errorf("got unexpected error for value: %q: %s", test.value.ValueString(), response.Diagnostics.Errors())
+			}
+		})
+	}
+}
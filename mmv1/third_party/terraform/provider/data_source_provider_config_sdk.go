@@ -67,6 +67,10 @@ func DataSourceGoogleProviderConfigSdk() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"request_log_file": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"request_timeout": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -141,6 +145,9 @@ func dataSourceClientConfigRead(d *schema.ResourceData, meta interface{}) error
 	if err := d.Set("request_reason", config.RequestReason); err != nil {
 		return fmt.Errorf("error setting request_reason: %s", err)
 	}
+	if err := d.Set("request_log_file", config.RequestLogFile); err != nil {
+		return fmt.Errorf("error setting request_log_file: %s", err)
+	}
 	if err := d.Set("request_timeout", config.RequestTimeout.String()); err != nil {
 		return fmt.Errorf("error setting request_timeout: %s", err)
 	}
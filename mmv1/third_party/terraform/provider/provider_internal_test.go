@@ -1770,3 +1770,97 @@ func TestProvider_ProviderConfigure_batching(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_ProviderConfigure_rateLimit(t *testing.T) {
+	cases := map[string]struct {
+		ConfigValues       map[string]interface{}
+		ExpectError        bool
+		ExpectedRateLimits []transport_tpg.RateLimitConfig
+	}{
+		"rate limits can be configured per product": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"rate_limit": []interface{}{
+					map[string]interface{}{
+						"product":             "compute",
+						"requests_per_second": 20.0,
+					},
+					map[string]interface{}{
+						"product":             "artifact_registry",
+						"requests_per_second": 0.5,
+						"burst":               5,
+					},
+				},
+			},
+			ExpectedRateLimits: []transport_tpg.RateLimitConfig{
+				{Product: "compute", RequestsPerSecond: 20},
+				{Product: "artifact_registry", RequestsPerSecond: 0.5, Burst: 5},
+			},
+		},
+		"rate limits are unset by default": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+			},
+		},
+		// Error states
+		"if rate_limit is configured with an unknown product, there's an error": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"rate_limit": []interface{}{
+					map[string]interface{}{
+						"product":             "not_a_product",
+						"requests_per_second": 20.0,
+					},
+				},
+			},
+			ExpectError: true,
+		},
+		"if rate_limit is configured with requests_per_second as zero, there's an error": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"rate_limit": []interface{}{
+					map[string]interface{}{
+						"product":             "compute",
+						"requests_per_second": 0.0,
+					},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+
+			// Arrange
+			ctx := context.Background()
+			acctest.UnsetTestProviderConfigEnvs(t)
+			p := provider.Provider()
+			d := tpgresource.SetupTestResourceDataFromConfigMap(t, p.Schema, tc.ConfigValues)
+
+			// Act
+			c, diags := provider.ProviderConfigure(ctx, d, p)
+
+			// Assert
+			if diags.HasError() && !tc.ExpectError {
+				t.Fatalf("unexpected error(s): %#v", diags)
+			}
+			if !diags.HasError() && tc.ExpectError {
+				t.Fatal("expected error(s) but got none")
+			}
+			if diags.HasError() {
+				return
+			}
+
+			config := c.(*transport_tpg.Config)
+			if len(config.RateLimits) != len(tc.ExpectedRateLimits) {
+				t.Fatalf("expected %d rate limits, got %#v", len(tc.ExpectedRateLimits), config.RateLimits)
+			}
+			for i, want := range tc.ExpectedRateLimits {
+				if config.RateLimits[i] != want {
+					t.Fatalf("expected rate limit %d to be %#v, got %#v", i, want, config.RateLimits[i])
+				}
+			}
+		})
+	}
+}
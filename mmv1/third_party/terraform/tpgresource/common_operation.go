@@ -1,12 +1,14 @@
 package tpgresource
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+	"go.opentelemetry.io/otel/attribute"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 )
 
@@ -52,6 +54,12 @@ type Waiter interface {
 	TargetStates() []string
 }
 
+// TracedWaiter is a Waiter whose polls are traced under the span of
+// TraceContext, such as the span of the resource operation waiting on it.
+type TracedWaiter interface {
+	TraceContext() context.Context
+}
+
 type CommonOperationWaiter struct {
 	Op CommonOperation
 }
@@ -136,15 +144,32 @@ func CommonRefreshFunc(w Waiter) retry.StateRefreshFunc {
 	}
 }
 
-func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) (err error) {
 	if OperationDone(w) {
 		return w.Error()
 	}
 
+	ctx := context.Background()
+	if tw, ok := w.(TracedWaiter); ok {
+		ctx = tw.TraceContext()
+	}
+	ctx, span := transport_tpg.StartSpan(ctx, "OperationWait",
+		attribute.String("operation.activity", activity),
+		attribute.String("operation.name", w.OpName()),
+	)
+	defer func() { transport_tpg.EndSpan(span, err) }()
+
+	refresh := CommonRefreshFunc(w)
 	c := &retry.StateChangeConf{
-		Pending:      w.PendingStates(),
-		Target:       w.TargetStates(),
-		Refresh:      CommonRefreshFunc(w),
+		Pending: w.PendingStates(),
+		Target:  w.TargetStates(),
+		Refresh: func() (interface{}, string, error) {
+			_, pollSpan := transport_tpg.StartSpan(ctx, "OperationWait.Poll")
+			op, state, err := refresh()
+			pollSpan.SetAttributes(attribute.String("operation.state", state))
+			transport_tpg.EndSpan(pollSpan, err)
+			return op, state, err
+		},
 		Timeout:      timeout,
 		MinTimeout:   2 * time.Second,
 		PollInterval: pollInterval,
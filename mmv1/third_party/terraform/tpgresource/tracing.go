@@ -0,0 +1,85 @@
+package tpgresource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TraceResources returns the resources or data sources with a span around each
+// call of their CRUD functions when tracing is enabled. The requests, polls and locks of a
+// call are traced under its span, through the config passed to the function.
+func TraceResources(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	if !transport_tpg.TracingEnabled() {
+		return resources
+	}
+	traced := make(map[string]*schema.Resource, len(resources))
+	for name, r := range resources {
+		traced[name] = traceResource(name, r)
+	}
+	return traced
+}
+
+func traceResource(name string, r *schema.Resource) *schema.Resource {
+	traced := *r
+	traced.Create = traceCrudFunc(name+".Create", r.Create)
+	traced.Read = traceCrudFunc(name+".Read", r.Read)
+	traced.Update = traceCrudFunc(name+".Update", r.Update)
+	traced.Delete = traceCrudFunc(name+".Delete", r.Delete)
+	traced.CreateContext = traceCrudContextFunc(name+".Create", r.CreateContext)
+	traced.ReadContext = traceCrudContextFunc(name+".Read", r.ReadContext)
+	traced.UpdateContext = traceCrudContextFunc(name+".Update", r.UpdateContext)
+	traced.DeleteContext = traceCrudContextFunc(name+".Delete", r.DeleteContext)
+	traced.CreateWithoutTimeout = traceCrudContextFunc(name+".Create", r.CreateWithoutTimeout)
+	traced.ReadWithoutTimeout = traceCrudContextFunc(name+".Read", r.ReadWithoutTimeout)
+	traced.UpdateWithoutTimeout = traceCrudContextFunc(name+".Update", r.UpdateWithoutTimeout)
+	traced.DeleteWithoutTimeout = traceCrudContextFunc(name+".Delete", r.DeleteWithoutTimeout)
+	return &traced
+}
+
+func traceCrudFunc(name string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) (err error) {
+		ctx, span := transport_tpg.StartSpan(context.Background(), name, attribute.String("terraform.resource_id", d.Id()))
+		defer func() { transport_tpg.EndSpan(span, err) }()
+
+		return f(d, metaWithTraceContext(ctx, meta))
+	}
+}
+
+func traceCrudContextFunc(name string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, span := transport_tpg.StartSpan(ctx, name, attribute.String("terraform.resource_id", d.Id()))
+		diags := f(ctx, d, metaWithTraceContext(ctx, meta))
+		var err error
+		for _, diagnostic := range diags {
+			if diagnostic.Severity == diag.Error {
+				err = diagnosticError(diagnostic)
+				break
+			}
+		}
+		transport_tpg.EndSpan(span, err)
+		return diags
+	}
+}
+
+type diagnosticError diag.Diagnostic
+
+func (d diagnosticError) Error() string {
+	return d.Summary
+}
+
+func metaWithTraceContext(ctx context.Context, meta interface{}) interface{} {
+	if config, ok := meta.(*transport_tpg.Config); ok {
+		return config.WithTraceContext(ctx)
+	}
+	return meta
+}
@@ -0,0 +1,57 @@
+package tpgresource
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceResources(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"google_test_resource": {
+			Schema: map[string]*schema.Schema{},
+			Create: func(d *schema.ResourceData, meta interface{}) error {
+				config := meta.(*transport_tpg.Config)
+				if !trace.SpanFromContext(config.TraceContext()).SpanContext().IsValid() {
+					return fmt.Errorf("the config passed to Create has no span")
+				}
+				return fmt.Errorf("error creating the resource")
+			},
+		},
+	}
+
+	if traced := TraceResources(resources); traced["google_test_resource"] != resources["google_test_resource"] {
+		t.Fatalf("TraceResources() wrapped the resources with tracing disabled")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	t.Setenv(transport_tpg.TracingEndpointEnvVar, "http://localhost:4318")
+
+	r := TraceResources(resources)["google_test_resource"]
+	d := r.TestResourceData()
+	err := r.Create(d, &transport_tpg.Config{})
+	if err == nil || err.Error() != "error creating the resource" {
+		t.Fatalf("Create() returned %v, want the error of the resource", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name() != "google_test_resource.Create" {
+		t.Errorf("span name = %q, want %q", spans[0].Name(), "google_test_resource.Create")
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("span status = %v, want an error", spans[0].Status().Code)
+	}
+}
@@ -1,12 +1,14 @@
 package transport
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type (
@@ -34,18 +36,28 @@ func SuccessPollResult() PollResult {
 
 func PollingWaitTime(pollF PollReadFunc, checkResponse PollCheckResponseFunc, activity string,
 	timeout time.Duration, targetOccurrences int) error {
+	return PollingWaitTimeContext(context.Background(), pollF, checkResponse, activity, timeout, targetOccurrences)
+}
+
+// PollingWaitTimeContext is PollingWaitTime tracing each poll under the span of ctx.
+func PollingWaitTimeContext(ctx context.Context, pollF PollReadFunc, checkResponse PollCheckResponseFunc, activity string,
+	timeout time.Duration, targetOccurrences int) (err error) {
+	ctx, span := StartSpan(ctx, "PollingWaitTime", attribute.String("polling.activity", activity))
+	defer func() { EndSpan(span, err) }()
+
+	poll := func() *retry.RetryError {
+		_, pollSpan := StartSpan(ctx, "PollingWaitTime.Poll")
+		defer pollSpan.End()
+		readResp, readErr := pollF()
+		return checkResponse(readResp, readErr)
+	}
+
 	log.Printf("[DEBUG] %s: Polling until expected state is read", activity)
 	log.Printf("[DEBUG] Target occurrences: %d", targetOccurrences)
 	if targetOccurrences == 1 {
-		return retry.Retry(timeout, func() *retry.RetryError {
-			readResp, readErr := pollF()
-			return checkResponse(readResp, readErr)
-		})
+		return retry.Retry(timeout, poll)
 	}
-	return RetryWithTargetOccurrences(timeout, targetOccurrences, func() *retry.RetryError {
-		readResp, readErr := pollF()
-		return checkResponse(readResp, readErr)
-	})
+	return RetryWithTargetOccurrences(timeout, targetOccurrences, poll)
 }
 
 // RetryWithTargetOccurrences is a basic wrapper around StateChangeConf that will retry
@@ -1,8 +1,11 @@
 package transport
 
 import (
+	"context"
 	"log"
 	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
@@ -19,6 +22,15 @@ type MutexKV struct {
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
+	m.LockContext(context.Background(), key)
+}
+
+// Locks the mutex for the given key, tracing the wait for the lock under the
+// span of ctx. Caller is responsible for calling Unlock for the same key
+func (m *MutexKV) LockContext(ctx context.Context, key string) {
+	_, span := StartSpan(ctx, "MutexKV.Lock", attribute.String("mutex.key", key))
+	defer span.End()
+
 	log.Printf("[DEBUG] Locking %q", key)
 	m.get(key).Lock()
 	log.Printf("[DEBUG] Locked %q", key)
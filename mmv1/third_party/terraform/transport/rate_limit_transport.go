@@ -0,0 +1,300 @@
+// A http.RoundTripper that limits the rate of the requests sent to the API of
+// each product, so that large applies stay under per-minute quotas instead of
+// relying on retries of quota errors.
+//
+// Each product gets a token bucket keyed by its base URL, without the API
+// version, so that requests to the different versions of a service share a
+// bucket. The rate of a bucket is set per product in the rate_limit blocks of
+// the provider config, and is unlimited otherwise.
+//
+// Buckets adapt to quota errors: a 429, or a 403 for a per-minute quota, halves
+// the rate of the bucket of the service that returned it, starting from the
+// rate requests were sent at if the bucket was unlimited. Once no quota error
+// has been returned for a minute, the rate grows back by a quarter every minute
+// up to the configured rate, or until the bucket is unlimited again.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-google/google/registry"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minAdaptiveRequestsPerSecond is the rate quota errors never slow a
+	// service down past.
+	minAdaptiveRequestsPerSecond = 0.1
+	// rateLimitThrottleInterval is how long quota errors are ignored for after
+	// halving the rate, so that the requests in flight at the old rate don't
+	// halve it again.
+	rateLimitThrottleInterval = 10 * time.Second
+	// rateLimitRecoveryInterval is how long the rate stays at a value before it
+	// grows back. Most quotas are enforced per minute.
+	rateLimitRecoveryInterval = time.Minute
+	rateLimitRecoveryFactor   = 1.25
+)
+
+var templateDirectiveRegex = regexp.MustCompile(`{{[^}]*}}`)
+
+// RateLimitConfig limits the rate of the requests sent to the API of a
+// product.
+type RateLimitConfig struct {
+	// Product is the name of the product as used in its custom endpoint
+	// field, e.g. "compute" or "artifact_registry".
+	Product           string
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type rateLimitTransport struct {
+	internal http.RoundTripper
+	services []*serviceRateLimiter
+}
+
+// NewTransportWithRateLimits constructs a transport limiting the rate of the
+// requests sent to the API of each registered product, using the base URLs
+// and rate limits of the config.
+func NewTransportWithRateLimits(t http.RoundTripper, config *Config) *rateLimitTransport {
+	configured := make(map[string]RateLimitConfig)
+	for _, rl := range config.RateLimits {
+		configured[rl.Product] = rl
+	}
+
+	byBaseUrl := make(map[string]*serviceRateLimiter)
+	for _, p := range registry.ListProducts() {
+		baseUrl := RemoveBasePathVersion(BaseUrl(p, config))
+		if !strings.HasPrefix(baseUrl, "http") {
+			continue
+		}
+		s, ok := byBaseUrl[baseUrl]
+		if !ok {
+			s = newServiceRateLimiter(baseUrl)
+			byBaseUrl[baseUrl] = s
+		}
+		if rl, ok := configured[strings.TrimSuffix(p.CustomEndpointField, "_custom_endpoint")]; ok {
+			// Products sharing a base URL share the lowest of their limits.
+			s.configure(rl)
+		}
+	}
+
+	services := make([]*serviceRateLimiter, 0, len(byBaseUrl))
+	for _, s := range byBaseUrl {
+		services = append(services, s)
+	}
+	// Match the most specific base URL first.
+	sort.Slice(services, func(i, j int) bool {
+		if len(services[i].baseUrl) != len(services[j].baseUrl) {
+			return len(services[i].baseUrl) > len(services[j].baseUrl)
+		}
+		return services[i].baseUrl < services[j].baseUrl
+	})
+
+	return &rateLimitTransport{
+		internal: t,
+		services: services,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s := t.serviceFor(req.URL)
+	if s == nil {
+		return t.internal.RoundTrip(req)
+	}
+
+	if err := s.wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("error waiting for the rate limit of %s: %w", s.baseUrl, err)
+	}
+
+	resp, err := t.internal.RoundTrip(req)
+	if err == nil && isQuotaErrorResponse(resp) {
+		s.throttle()
+	}
+	return resp, err
+}
+
+func (t *rateLimitTransport) serviceFor(u *url.URL) *serviceRateLimiter {
+	reqUrl := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+	for _, s := range t.services {
+		if s.pattern.MatchString(reqUrl) {
+			return s
+		}
+	}
+	return nil
+}
+
+func isQuotaErrorResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	err, checkErr := responseError(resp)
+	if err == nil || checkErr != nil {
+		return false
+	}
+	if isQuota, _ := Is429QuotaError(err); isQuota {
+		return true
+	}
+	isQuota, _ := is403QuotaExceededPerMinuteError(err)
+	return isQuota
+}
+
+type serviceRateLimiter struct {
+	baseUrl string
+	pattern *regexp.Regexp
+
+	mu      sync.Mutex
+	limiter *rate.Limiter
+	// ceiling is the rate the limit grows back to after quota errors. It's the
+	// configured rate, or the rate requests were sent at when the limit was
+	// unlimited.
+	ceiling    rate.Limit
+	configured bool
+	burst      int
+	// adjustedAt is when the limit was last changed by a quota error or by
+	// growing back.
+	adjustedAt  time.Time
+	throttledAt time.Time
+
+	// Requests are counted per minute to find the rate they're sent at.
+	minuteStart            time.Time
+	minuteRequests         int
+	previousMinuteRequests int
+
+	now func() time.Time
+}
+
+func newServiceRateLimiter(baseUrl string) *serviceRateLimiter {
+	parts := templateDirectiveRegex.Split(baseUrl, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return &serviceRateLimiter{
+		baseUrl: baseUrl,
+		pattern: regexp.MustCompile("^" + strings.Join(parts, "[^/]+")),
+		limiter: rate.NewLimiter(rate.Inf, 1),
+		ceiling: rate.Inf,
+		burst:   1,
+		now:     time.Now,
+	}
+}
+
+func (s *serviceRateLimiter) configure(rl RateLimitConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := rate.Limit(rl.RequestsPerSecond)
+	if s.configured && limit >= s.ceiling {
+		return
+	}
+	burst := rl.Burst
+	if burst == 0 {
+		burst = int(math.Max(1, math.Ceil(rl.RequestsPerSecond)))
+	}
+	s.configured = true
+	s.ceiling = limit
+	s.burst = burst
+	s.limiter.SetLimit(limit)
+	s.limiter.SetBurst(burst)
+}
+
+// wait blocks until a request can be sent to the service, or ctx is done.
+func (s *serviceRateLimiter) wait(ctx context.Context) error {
+	s.mu.Lock()
+	now := s.now()
+	s.countRequest(now)
+	s.recover(now)
+	s.mu.Unlock()
+
+	return s.limiter.Wait(ctx)
+}
+
+// throttle halves the rate of the service after a quota error.
+func (s *serviceRateLimiter) throttle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.throttledAt) < rateLimitThrottleInterval {
+		return
+	}
+	s.throttledAt = now
+	s.adjustedAt = now
+
+	limit := s.limiter.Limit()
+	if limit == rate.Inf {
+		limit = rate.Limit(s.sentRate(now))
+		s.ceiling = limit
+	}
+	limit = rate.Limit(math.Max(float64(limit)/2, minAdaptiveRequestsPerSecond))
+	log.Printf("[DEBUG] Rate Limit Transport: quota error from %s, limiting requests to %.2f per second", s.baseUrl, float64(limit))
+	s.limiter.SetLimit(limit)
+	s.limiter.SetBurst(s.burst)
+}
+
+// recover grows the rate of the service back once no quota error has been
+// returned for a while.
+func (s *serviceRateLimiter) recover(now time.Time) {
+	limit := s.limiter.Limit()
+	if limit >= s.ceiling || now.Sub(s.adjustedAt) < rateLimitRecoveryInterval {
+		return
+	}
+	s.adjustedAt = now
+
+	limit = limit * rateLimitRecoveryFactor
+	if limit >= s.ceiling {
+		limit = s.ceiling
+		if !s.configured {
+			limit = rate.Inf
+			s.ceiling = rate.Inf
+		}
+	}
+	log.Printf("[DEBUG] Rate Limit Transport: no quota error from %s for %s, limiting requests to %.2f per second", s.baseUrl, rateLimitRecoveryInterval, float64(limit))
+	s.limiter.SetLimit(limit)
+}
+
+func (s *serviceRateLimiter) countRequest(now time.Time) {
+	elapsed := now.Sub(s.minuteStart)
+	switch {
+	case elapsed >= 2*time.Minute:
+		s.previousMinuteRequests = 0
+		s.minuteRequests = 0
+		s.minuteStart = now
+	case elapsed >= time.Minute:
+		s.previousMinuteRequests = s.minuteRequests
+		s.minuteRequests = 0
+		s.minuteStart = s.minuteStart.Add(time.Minute)
+	}
+	s.minuteRequests++
+}
+
+// sentRate estimates the rate requests were sent at over the last minute,
+// weighing the requests of the previous minute by how much of it is part of
+// the last minute.
+func (s *serviceRateLimiter) sentRate(now time.Time) float64 {
+	elapsed := now.Sub(s.minuteStart)
+	if elapsed >= 2*time.Minute {
+		return minAdaptiveRequestsPerSecond
+	}
+	previous, current := float64(s.previousMinuteRequests), float64(s.minuteRequests)
+	if elapsed >= time.Minute {
+		previous, current = current, 0
+		elapsed -= time.Minute
+	}
+	overlap := 1 - elapsed.Seconds()/time.Minute.Seconds()
+	return math.Max((previous*overlap+current)/time.Minute.Seconds(), minAdaptiveRequestsPerSecond)
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitTransportServiceFor(t *testing.T) {
+	transport := &rateLimitTransport{
+		services: []*serviceRateLimiter{
+			newServiceRateLimiter("https://{{location}}-aiplatform.googleapis.com/"),
+			newServiceRateLimiter("https://compute.googleapis.com/compute/"),
+		},
+	}
+
+	cases := map[string]string{
+		"https://compute.googleapis.com/compute/v1/projects/my-project/global/networks/my-network":   "https://compute.googleapis.com/compute/",
+		"https://compute.googleapis.com/compute/beta/projects/my-project?alt=json":                   "https://compute.googleapis.com/compute/",
+		"https://us-central1-aiplatform.googleapis.com/v1/projects/my-project/locations/us-central1": "https://{{location}}-aiplatform.googleapis.com/",
+		"https://storage.googleapis.com/storage/v1/b/my-bucket":                                      "",
+	}
+	for rawUrl, want := range cases {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if s := transport.serviceFor(u); s != nil {
+			got = s.baseUrl
+		}
+		if got != want {
+			t.Errorf("serviceFor(%q) = %q, want %q", rawUrl, got, want)
+		}
+	}
+}
+
+func TestServiceRateLimiterAdaptsToQuotaErrors(t *testing.T) {
+	now := time.Now()
+	s := newServiceRateLimiter("https://compute.googleapis.com/compute/")
+	s.now = func() time.Time { return now }
+
+	// 120 requests over a minute are sent at 2 per second.
+	for i := 0; i < 120; i++ {
+		s.countRequest(now)
+		now = now.Add(500 * time.Millisecond)
+	}
+	now = now.Add(-500 * time.Millisecond)
+
+	s.throttle()
+	if got := s.limiter.Limit(); got != 1 {
+		t.Fatalf("limit after a quota error = %v, want 1", got)
+	}
+
+	// Quota errors of requests sent at the old rate don't halve it again.
+	now = now.Add(time.Second)
+	s.throttle()
+	if got := s.limiter.Limit(); got != 1 {
+		t.Fatalf("limit after a second quota error = %v, want 1", got)
+	}
+
+	now = now.Add(30 * time.Second)
+	s.recover(now)
+	if got := s.limiter.Limit(); got != 1 {
+		t.Fatalf("limit before the recovery interval = %v, want 1", got)
+	}
+
+	wants := []rate.Limit{1.25, 1.5625, 1.953125, rate.Inf}
+	for _, want := range wants {
+		now = now.Add(rateLimitRecoveryInterval)
+		s.recover(now)
+		if got := s.limiter.Limit(); got != want {
+			t.Fatalf("limit after recovering = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestServiceRateLimiterConfigured(t *testing.T) {
+	now := time.Now()
+	s := newServiceRateLimiter("https://compute.googleapis.com/compute/")
+	s.now = func() time.Time { return now }
+	s.configure(RateLimitConfig{Product: "compute", RequestsPerSecond: 10})
+	// Products sharing a base URL share the lowest of their limits.
+	s.configure(RateLimitConfig{Product: "compute_beta", RequestsPerSecond: 20})
+
+	if got := s.limiter.Limit(); got != 10 {
+		t.Fatalf("configured limit = %v, want 10", got)
+	}
+	if got := s.limiter.Burst(); got != 10 {
+		t.Fatalf("configured burst = %d, want 10", got)
+	}
+
+	s.throttle()
+	if got := s.limiter.Limit(); got != 5 {
+		t.Fatalf("limit after a quota error = %v, want 5", got)
+	}
+	for _, want := range []rate.Limit{6.25, 7.8125, 9.765625, 10, 10} {
+		now = now.Add(rateLimitRecoveryInterval)
+		s.recover(now)
+		if got := s.limiter.Limit(); got != want {
+			t.Fatalf("limit after recovering = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/quota" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error": {"code": 429, "message": "Quota exceeded", "status": "RESOURCE_EXHAUSTED"}}`)
+			return
+		}
+		if r.URL.Path == "/v1/permission" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"code": 403, "message": "Permission denied", "status": "PERMISSION_DENIED"}}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	s := newServiceRateLimiter(ts.URL + "/")
+	transport := &rateLimitTransport{
+		internal: http.DefaultTransport,
+		services: []*serviceRateLimiter{s},
+	}
+	client := &http.Client{Transport: transport}
+
+	for _, path := range []string{"/v1/ok", "/v1/permission"} {
+		resp, err := client.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s returned an error: %s", path, err)
+		}
+		resp.Body.Close()
+		if got := s.limiter.Limit(); got != rate.Inf {
+			t.Fatalf("limit after GET %s = %v, want unlimited", path, got)
+		}
+	}
+
+	resp, err := client.Get(ts.URL + "/v1/quota")
+	if err != nil {
+		t.Fatalf("GET /v1/quota returned an error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+	if got := s.limiter.Limit(); got == rate.Inf {
+		t.Errorf("limit after a quota error is unlimited")
+	}
+	if requests != 3 {
+		t.Errorf("the server received %d requests, want 3", requests)
+	}
+}
@@ -0,0 +1,387 @@
+// A http.RoundTripper that writes a structured log of the requests sent to
+// Google APIs, for auditing which API calls a plan or apply made.
+//
+// Each attempt of a request is written as a JSON line to the file set in the
+// request_log_file provider field, with the method, URL, status, latency and
+// attempt number of the request, and the resource operation that sent it.
+// Auth headers, API keys and the values of sensitive and write-only fields of
+// the resource are redacted from the logged requests and responses. Fields
+// that commonly hold credentials, such as private keys and access tokens, are
+// redacted from every request, as handwritten resources and client libraries
+// don't describe their sensitive fields.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RequestLogRedacted replaces redacted values in the request log.
+const RequestLogRedacted = "REDACTED"
+
+// Bodies larger than this are not written to the request log.
+const maxRequestLogBodyBytes = 1 << 20
+
+var requestLogRedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+	"X-Goog-Iam-Authorization-Token",
+}
+
+var requestLogRedactedQueryParams = []string{
+	"access_token",
+	"key",
+}
+
+// requestLogRedactedKeys are the names of the fields redacted from every body,
+// compared ignoring case and underscores.
+var requestLogRedactedKeys = map[string]bool{
+	"accesstoken":    true,
+	"apikey":         true,
+	"clientsecret":   true,
+	"credentials":    true,
+	"idtoken":        true,
+	"keystring":      true,
+	"password":       true,
+	"privatekey":     true,
+	"privatekeydata": true,
+	"refreshtoken":   true,
+	"secret":         true,
+	"signedblob":     true,
+	"signedjwt":      true,
+	"token":          true,
+}
+
+// requestLogRedactedFields are the dotted API paths of the fields redacted from
+// every body, for fields whose names are too common to redact by name alone.
+var requestLogRedactedFields = []string{
+	// Secret Manager secret versions
+	"payload.data",
+}
+
+// RequestLogInfo describes the resource operation a request is sent for, and
+// is written to the request log with each attempt of the request.
+type RequestLogInfo struct {
+	// Operation is the resource operation sending the request, such as Create.
+	Operation string
+	// ResourceType is the Terraform type of the resource, such as
+	// google_pubsub_topic. Terraform doesn't pass providers the address of a
+	// resource in the configuration, so the type and id identify it instead.
+	ResourceType string
+	// ResourceId is the Terraform id of the resource, which is empty until the
+	// resource is created.
+	ResourceId string
+	// RedactFields are the dotted API paths of the fields whose values are
+	// redacted from the request and response bodies, where "*" matches any map
+	// key. A path matches the end of the path of a field, so fields are also
+	// redacted from bodies wrapping the resource.
+	RedactFields []string
+}
+
+// requestLogContext is attached to the context of a request to count its
+// attempts across retries.
+type requestLogContext struct {
+	info     RequestLogInfo
+	attempts int32
+}
+
+type requestLogContextKey struct{}
+
+func contextWithRequestLog(ctx context.Context, info RequestLogInfo) context.Context {
+	return context.WithValue(ctx, requestLogContextKey{}, &requestLogContext{info: info})
+}
+
+func requestLogFromContext(ctx context.Context) *requestLogContext {
+	rlc, _ := ctx.Value(requestLogContextKey{}).(*requestLogContext)
+	return rlc
+}
+
+type requestAttemptContextKey struct{}
+
+// requestWithAttempt returns req with the number of its attempt, counting a
+// new attempt unless a transport wrapping this one counted it already.
+func requestWithAttempt(req *http.Request) (*http.Request, int) {
+	if attempt, ok := req.Context().Value(requestAttemptContextKey{}).(int); ok {
+		return req, attempt
+	}
+	attempt := 1
+	if rlc := requestLogFromContext(req.Context()); rlc != nil {
+		attempt = int(atomic.AddInt32(&rlc.attempts, 1))
+	}
+	return req.WithContext(context.WithValue(req.Context(), requestAttemptContextKey{}, attempt)), attempt
+}
+
+// RequestLogEntry is a line of the request log.
+type RequestLogEntry struct {
+	Time           time.Time       `json:"time"`
+	Method         string          `json:"method"`
+	URL            string          `json:"url"`
+	Status         int             `json:"status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	LatencyMs      int64           `json:"latency_ms"`
+	Attempt        int             `json:"attempt"`
+	Operation      string          `json:"operation,omitempty"`
+	ResourceType   string          `json:"resource_type,omitempty"`
+	ResourceId     string          `json:"resource_id,omitempty"`
+	RequestHeaders http.Header     `json:"request_headers,omitempty"`
+	RequestBody    json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody   json.RawMessage `json:"response_body,omitempty"`
+}
+
+// RequestLogger writes entries to a request log as JSON lines.
+type RequestLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var (
+	requestLoggersMu sync.Mutex
+	requestLoggers   = make(map[string]*RequestLogger)
+)
+
+// NewRequestLogger returns the logger appending to the request log at path,
+// creating the file if needed. Loggers are shared by the configs logging to
+// the same file, so that their lines aren't interleaved.
+func NewRequestLogger(path string) (*RequestLogger, error) {
+	requestLoggersMu.Lock()
+	defer requestLoggersMu.Unlock()
+
+	if l, ok := requestLoggers[path]; ok {
+		return l, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening request log file %q: %w", path, err)
+	}
+	l := &RequestLogger{w: f}
+	requestLoggers[path] = l
+	return l, nil
+}
+
+// Log writes the entry as a line of the request log.
+func (l *RequestLogger) Log(entry RequestLogEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[WARN] Request Log Transport: error encoding request log entry: %v", err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(b, '\n')); err != nil {
+		log.Printf("[WARN] Request Log Transport: error writing request log entry: %v", err)
+	}
+}
+
+type requestLogTransport struct {
+	logger   *RequestLogger
+	internal http.RoundTripper
+}
+
+// NewTransportWithRequestLog constructs a transport writing each request sent
+// through it to the request log of logger.
+func NewTransportWithRequestLog(t http.RoundTripper, logger *RequestLogger) *requestLogTransport {
+	return &requestLogTransport{
+		logger:   logger,
+		internal: t,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *requestLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, attempt := requestWithAttempt(req)
+	var info RequestLogInfo
+	if rlc := requestLogFromContext(req.Context()); rlc != nil {
+		info = rlc.info
+	}
+
+	entry := RequestLogEntry{
+		Time:           time.Now().UTC(),
+		Method:         req.Method,
+		URL:            redactRequestLogURL(req.URL),
+		Attempt:        attempt,
+		Operation:      info.Operation,
+		ResourceType:   info.ResourceType,
+		ResourceId:     info.ResourceId,
+		RequestHeaders: redactRequestLogHeaders(req.Header),
+		RequestBody:    redactRequestLogBody(requestLogBody(req), info.RedactFields),
+	}
+
+	resp, err := t.internal.RoundTrip(req)
+	entry.LatencyMs = time.Since(entry.Time).Milliseconds()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+		entry.ResponseBody = redactRequestLogBody(responseLogBody(resp), info.RedactFields)
+	}
+	t.logger.Log(entry)
+
+	return resp, err
+}
+
+// requestLogBody returns a copy of the JSON body of req, leaving the body of
+// req unread.
+func requestLogBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return nil
+	}
+	if req.ContentLength > maxRequestLogBodyBytes {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := io.ReadAll(io.LimitReader(body, maxRequestLogBodyBytes+1))
+	if err != nil || len(b) > maxRequestLogBodyBytes {
+		return nil
+	}
+	return b
+}
+
+// responseLogBody reads the JSON body of resp and replaces it with a reader
+// of the same bytes. Bodies which aren't JSON, such as object downloads, and
+// large bodies are left unread.
+func responseLogBody(resp *http.Response) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") || resp.ContentLength > maxRequestLogBodyBytes {
+		return nil
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxRequestLogBodyBytes+1))
+	if err != nil || len(b) > maxRequestLogBodyBytes {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+		return nil
+	}
+	resp.Body = readCloser{bytes.NewReader(b), resp.Body}
+	return b
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func redactRequestLogURL(u *url.URL) string {
+	redacted := *u
+	q := redacted.Query()
+	for _, param := range requestLogRedactedQueryParams {
+		if q.Has(param) {
+			q.Set(param, RequestLogRedacted)
+		}
+	}
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+func redactRequestLogHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range requestLogRedactedHeaders {
+		if _, ok := redacted[name]; ok {
+			redacted.Set(name, RequestLogRedacted)
+		}
+	}
+	return redacted
+}
+
+// redactRequestLogBody returns the JSON body with the values of the fields
+// matching redactFields or the fields redacted from every body redacted.
+// Bodies which aren't valid JSON are not logged, as they can't be redacted.
+func redactRequestLogBody(body []byte, redactFields []string) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+	var paths [][]string
+	for _, field := range append(redactFields[:len(redactFields):len(redactFields)], requestLogRedactedFields...) {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	b, err := json.Marshal(redactRequestLogValue(v, nil, paths))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func redactRequestLogValue(v interface{}, path []string, redactPaths [][]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			itemPath := append(path[:len(path):len(path)], k)
+			if requestLogRedactedKey(k) || requestLogPathMatches(itemPath, redactPaths) {
+				v[k] = RequestLogRedacted
+			} else {
+				v[k] = redactRequestLogValue(item, itemPath, redactPaths)
+			}
+		}
+	case []interface{}:
+		// Items of arrays are at the path of the array.
+		for i, item := range v {
+			v[i] = redactRequestLogValue(item, path, redactPaths)
+		}
+	case string:
+		// Bodies sent through the RPC proxy are JSON encoded in a string field.
+		if !strings.HasPrefix(v, "{") {
+			return v
+		}
+		var obj interface{}
+		if err := json.Unmarshal([]byte(v), &obj); err != nil {
+			return v
+		}
+		b, err := json.Marshal(redactRequestLogValue(obj, nil, redactPaths))
+		if err != nil {
+			return RequestLogRedacted
+		}
+		return string(b)
+	}
+	return v
+}
+
+// requestLogRedactedKey returns whether the field named key is redacted from
+// every body.
+func requestLogRedactedKey(key string) bool {
+	return requestLogRedactedKeys[strings.ToLower(strings.ReplaceAll(key, "_", ""))]
+}
+
+// requestLogPathMatches returns whether any of redactPaths matches the end of
+// path.
+func requestLogPathMatches(path []string, redactPaths [][]string) bool {
+	for _, redactPath := range redactPaths {
+		if len(redactPath) > len(path) {
+			continue
+		}
+		tail := path[len(path)-len(redactPath):]
+		matches := true
+		for i, segment := range redactPath {
+			if segment != "*" && segment != tail[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
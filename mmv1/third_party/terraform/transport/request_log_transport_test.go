@@ -0,0 +1,164 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogTransport(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		if requests == 1 {
+			w.WriteHeader(testRetryTransportCodeRetry)
+			fmt.Fprint(w, `{"error": {"code": 500, "message": "try again"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"name": "my-user", "password": "hunter2", "settings": {"token": "abc", "region": "us-central1"}}`)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := ts.Client()
+	client.Transport = &retryTransport{
+		internal:        NewTransportWithRequestLog(http.DefaultTransport, &RequestLogger{w: &buf}),
+		retryPredicates: []RetryErrorPredicateFunc{testRetryTransportRetryPredicate},
+	}
+
+	headers := make(http.Header)
+	headers.Set("Authorization", "Bearer my-token")
+	_, err := SendRequest(SendRequestOptions{
+		Config:    &Config{Client: client},
+		Method:    "POST",
+		RawURL:    ts.URL + "/users?key=my-api-key",
+		UserAgent: "test",
+		Body:      map[string]any{"name": "my-user", "password": "hunter2", "settings": map[string]any{"token": "abc"}},
+		Headers:   headers,
+		RequestLog: RequestLogInfo{
+			Operation:    "Create",
+			ResourceType: "google_sql_user",
+			RedactFields: []string{"password", "settings.token"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendRequest() returned an error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines in the request log, want 2:\n%s", len(lines), buf.String())
+	}
+	if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "my-token") || strings.Contains(buf.String(), "my-api-key") {
+		t.Errorf("the request log contains redacted values:\n%s", buf.String())
+	}
+
+	for i, wantStatus := range []int{testRetryTransportCodeRetry, testRetryTransportCodeSuccess} {
+		var entry RequestLogEntry
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("error decoding line %d of the request log: %s", i, err)
+		}
+		if entry.Attempt != i+1 {
+			t.Errorf("line %d: attempt = %d, want %d", i, entry.Attempt, i+1)
+		}
+		if entry.Status != wantStatus {
+			t.Errorf("line %d: status = %d, want %d", i, entry.Status, wantStatus)
+		}
+		if entry.Method != "POST" || entry.Operation != "Create" || entry.ResourceType != "google_sql_user" {
+			t.Errorf("line %d: got method %q, operation %q and resource type %q, want POST, Create and google_sql_user", i, entry.Method, entry.Operation, entry.ResourceType)
+		}
+		if got := entry.RequestHeaders.Get("Authorization"); got != RequestLogRedacted {
+			t.Errorf("line %d: Authorization header = %q, want %q", i, got, RequestLogRedacted)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(entry.RequestBody, &body); err != nil {
+			t.Fatalf("error decoding the request body on line %d: %s", i, err)
+		}
+		wantBody := map[string]interface{}{"name": "my-user", "password": RequestLogRedacted, "settings": map[string]interface{}{"token": RequestLogRedacted}}
+		if !reflect.DeepEqual(body, wantBody) {
+			t.Errorf("line %d: request body = %v, want %v", i, body, wantBody)
+		}
+	}
+
+	var last RequestLogEntry
+	if err := json.Unmarshal([]byte(lines[1]), &last); err != nil {
+		t.Fatalf("error decoding the request log: %s", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(last.ResponseBody, &body); err != nil {
+		t.Fatalf("error decoding the response body: %s", err)
+	}
+	wantBody := map[string]interface{}{"name": "my-user", "password": RequestLogRedacted, "settings": map[string]interface{}{"token": RequestLogRedacted, "region": "us-central1"}}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("response body = %v, want %v", body, wantBody)
+	}
+}
+
+func TestRedactRequestLogBody(t *testing.T) {
+	cases := map[string]struct {
+		body         string
+		redactFields []string
+		want         string
+	}{
+		"nested in arrays": {
+			body:         `{"users": [{"name": "a", "password": "x"}, {"name": "b", "password": "y"}]}`,
+			redactFields: []string{"password"},
+			want:         `{"users": [{"name": "a", "password": "REDACTED"}, {"name": "b", "password": "REDACTED"}]}`,
+		},
+		"map keys": {
+			body:         `{"secrets": {"a": {"value": "x", "version": 1}}}`,
+			redactFields: []string{"secrets.*.value"},
+			want:         `{"secrets": {"a": {"value": "REDACTED", "version": 1}}}`,
+		},
+		"wrapped resource": {
+			body:         `{"instance": {"settings": {"pin": "x"}}, "pin": "y"}`,
+			redactFields: []string{"settings.pin"},
+			want:         `{"instance": {"settings": {"pin": "REDACTED"}}, "pin": "y"}`,
+		},
+		"credentials without redact fields": {
+			body: `{"name": "k", "privateKeyData": "x", "accessToken": "y", "data": {"private_key": "z", "Password": "w"}}`,
+			want: `{"name": "k", "privateKeyData": "REDACTED", "accessToken": "REDACTED", "data": {"private_key": "REDACTED", "Password": "REDACTED"}}`,
+		},
+		"secret payload": {
+			body: `{"name": "v", "payload": {"data": "x", "dataCrc32c": "1"}, "data": "y"}`,
+			want: `{"name": "v", "payload": {"data": "REDACTED", "dataCrc32c": "1"}, "data": "y"}`,
+		},
+		"json encoded request": {
+			body:         `{"methodName": "Create", "requestJson": "{\"password\":\"x\"}"}`,
+			redactFields: []string{"password"},
+			want:         `{"methodName": "Create", "requestJson": "{\"password\":\"REDACTED\"}"}`,
+		},
+		"not json": {
+			body: `password=x`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := redactRequestLogBody([]byte(tc.body), tc.redactFields)
+			if tc.want == "" {
+				if got != nil {
+					t.Errorf("redactRequestLogBody() = %s, want nil", got)
+				}
+				return
+			}
+			var gotV, wantV interface{}
+			if err := json.Unmarshal(got, &gotV); err != nil {
+				t.Fatalf("error decoding %s: %s", got, err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &wantV); err != nil {
+				t.Fatalf("error decoding %s: %s", tc.want, err)
+			}
+			if !reflect.DeepEqual(gotV, wantV) {
+				t.Errorf("redactRequestLogBody() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -93,6 +93,12 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 		}()
 	}
 
+	// Count the attempts of requests not sent by SendRequest in the request log
+	// as well.
+	if requestLogFromContext(req.Context()) == nil {
+		req = req.WithContext(contextWithRequestLog(req.Context(), RequestLogInfo{}))
+	}
+
 	attempts := 0
 	backoff := time.Millisecond * 500
 	nextBackoff := time.Millisecond * 500
@@ -200,20 +206,11 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 	if respErr != nil {
 		errToCheck = respErr
 	} else {
-		respToCheck := *resp
-		// The RoundTrip contract states that the HTTP response/response error
-		// returned cannot be edited. We need to consume the Body to check for
-		// errors, so we need to create a copy if the Response has a body.
-		if resp.Body != nil && resp.Body != http.NoBody {
-			// Use httputil.DumpResponse since the only important info is
-			// error code and messages in the response body.
-			dumpBytes, err := httputil.DumpResponse(resp, true)
-			if err != nil {
-				return retry.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err))
-			}
-			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(dumpBytes))
+		var err error
+		errToCheck, err = responseError(resp)
+		if err != nil {
+			return retry.NonRetryableError(err)
 		}
-		errToCheck = googleapi.CheckResponse(&respToCheck)
 	}
 
 	if errToCheck == nil {
@@ -224,3 +221,23 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 	}
 	return retry.NonRetryableError(errToCheck)
 }
+
+// responseError uses the googleapi.CheckResponse util to return the error in
+// the response, if any, without consuming its body. err is set if the body
+// couldn't be read.
+func responseError(resp *http.Response) (respErr error, err error) {
+	respToCheck := *resp
+	// The RoundTrip contract states that the HTTP response/response error
+	// returned cannot be edited. We need to consume the Body to check for
+	// errors, so we need to create a copy if the Response has a body.
+	if resp.Body != nil && resp.Body != http.NoBody {
+		// Use httputil.DumpResponse since the only important info is
+		// error code and messages in the response body.
+		dumpBytes, err := httputil.DumpResponse(resp, true)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check response for error: %v", err)
+		}
+		respToCheck.Body = ioutil.NopCloser(bytes.NewReader(dumpBytes))
+	}
+	return googleapi.CheckResponse(&respToCheck), nil
+}
@@ -0,0 +1,145 @@
+// OpenTelemetry tracing of the provider, for finding where the time of a plan
+// or apply goes.
+//
+// Tracing is enabled by setting the GOOGLE_TRACING_OTLP_ENDPOINT environment
+// variable to the URL of an OTLP/HTTP collector, such as
+// http://localhost:4318. The other OTEL_EXPORTER_OTLP_* environment variables,
+// such as OTEL_EXPORTER_OTLP_HEADERS, configure the exporter as well.
+//
+// Spans cover the CRUD functions of resources and data sources, the operations
+// of plugin framework resources, data sources, ephemeral resources and actions,
+// each attempt of an HTTP request, each poll of an operation or of the state of
+// a resource, and each acquisition of a MutexKV lock.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TracingEndpointEnvVar = "GOOGLE_TRACING_OTLP_ENDPOINT"
+
+const tracerName = "github.com/hashicorp/terraform-provider-google"
+
+var (
+	configureTracingOnce sync.Once
+	configureTracingErr  error
+)
+
+// TracingEnabled returns whether spans are exported to a collector.
+func TracingEnabled() bool {
+	return os.Getenv(TracingEndpointEnvVar) != ""
+}
+
+// ConfigureTracing sets up the export of spans to the collector at the
+// endpoint set in the environment, if any. Spans are exported as they end, so
+// that none are lost when Terraform stops the provider.
+func ConfigureTracing(ctx context.Context, version string) error {
+	if !TracingEnabled() {
+		return nil
+	}
+	configureTracingOnce.Do(func() {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(os.Getenv(TracingEndpointEnvVar)))
+		if err != nil {
+			configureTracingErr = fmt.Errorf("error creating the OTLP trace exporter: %w", err)
+			return
+		}
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithSyncer(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(
+				semconv.ServiceName("terraform-provider-google"),
+				semconv.ServiceVersion(version),
+			)),
+		))
+	})
+	return configureTracingErr
+}
+
+// StartSpan starts a span of the provider, which is a no-op unless tracing is
+// configured.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span, recording err if it isn't nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WithTraceContext returns a shallow copy of the config whose requests are
+// traced under the span of ctx, or the config itself if ctx holds no span.
+func (c *Config) WithTraceContext(ctx context.Context) *Config {
+	if c == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return c
+	}
+	copied := *c
+	copied.traceContext = ctx
+	return &copied
+}
+
+// TraceContext returns a context holding the span the requests of the config
+// are traced under, without the deadline or cancellation of the context the
+// span was started with.
+func (c *Config) TraceContext() context.Context {
+	if c == nil || c.traceContext == nil {
+		return context.Background()
+	}
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(c.traceContext))
+}
+
+type tracingTransport struct {
+	internal http.RoundTripper
+}
+
+// NewTransportWithTracing constructs a transport tracing each attempt of the
+// requests sent through it.
+func NewTransportWithTracing(t http.RoundTripper) *tracingTransport {
+	return &tracingTransport{
+		internal: t,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, attempt := requestWithAttempt(req)
+	ctx, span := StartSpan(req.Context(), "HTTP "+req.Method,
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(redactRequestLogURL(req.URL)),
+		semconv.HTTPRequestResendCount(attempt-1),
+	)
+	if rlc := requestLogFromContext(ctx); rlc != nil && rlc.info.ResourceType != "" {
+		span.SetAttributes(
+			attribute.String("terraform.operation", rlc.info.Operation),
+			attribute.String("terraform.resource_type", rlc.info.ResourceType),
+			attribute.String("terraform.resource_id", rlc.info.ResourceId),
+		)
+	}
+
+	resp, err := t.internal.RoundTrip(req.WithContext(ctx))
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if err == nil && resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	EndSpan(span, err)
+
+	return resp, err
+}
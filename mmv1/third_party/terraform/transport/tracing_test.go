@@ -0,0 +1,161 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setUpTracingSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func testTracingSpanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingTransport(t *testing.T) {
+	recorder := setUpTracingSpanRecorder(t)
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(testRetryTransportCodeRetry)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = &retryTransport{
+		internal:        NewTransportWithTracing(http.DefaultTransport),
+		retryPredicates: []RetryErrorPredicateFunc{testRetryTransportRetryPredicate},
+	}
+
+	ctx, parent := StartSpan(context.Background(), "google_sql_user.Create")
+	config := (&Config{Client: client}).WithTraceContext(ctx)
+	_, err := SendRequest(SendRequestOptions{
+		Config:    config,
+		Method:    "GET",
+		RawURL:    ts.URL + "/users",
+		UserAgent: "test",
+		RequestLog: RequestLogInfo{
+			Operation:    "Create",
+			ResourceType: "google_sql_user",
+		},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("SendRequest() returned an error: %s", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	for i, wantStatus := range []int64{testRetryTransportCodeRetry, testRetryTransportCodeSuccess} {
+		span := spans[i]
+		if span.Name() != "HTTP GET" {
+			t.Errorf("span %d: name = %q, want %q", i, span.Name(), "HTTP GET")
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d: not a child of the span of the operation", i)
+		}
+		if got := testTracingSpanAttribute(span, "http.request.resend_count").AsInt64(); got != int64(i) {
+			t.Errorf("span %d: resend count = %d, want %d", i, got, i)
+		}
+		if got := testTracingSpanAttribute(span, "http.response.status_code").AsInt64(); got != wantStatus {
+			t.Errorf("span %d: status code = %d, want %d", i, got, wantStatus)
+		}
+		if got := testTracingSpanAttribute(span, "terraform.resource_type").AsString(); got != "google_sql_user" {
+			t.Errorf("span %d: resource type = %q, want %q", i, got, "google_sql_user")
+		}
+	}
+}
+
+func TestTracingPollsAndLocks(t *testing.T) {
+	recorder := setUpTracingSpanRecorder(t)
+
+	ctx, parent := StartSpan(context.Background(), "google_sql_user.Create")
+	mutexKV := NewMutexKV()
+	mutexKV.LockContext(ctx, "my-key")
+	mutexKV.Unlock("my-key")
+
+	polls := 0
+	err := PollingWaitTimeContext(ctx, func() (map[string]interface{}, error) {
+		polls++
+		return nil, nil
+	}, func(map[string]interface{}, error) PollResult {
+		if polls < 2 {
+			return retry.RetryableError(fmt.Errorf("not ready"))
+		}
+		return nil
+	}, "Creating User", time.Minute, 1)
+	parent.End()
+	if err != nil {
+		t.Fatalf("PollingWaitTimeContext() returned an error: %s", err)
+	}
+
+	counts := make(map[string]int)
+	for _, span := range recorder.Ended() {
+		counts[span.Name()]++
+	}
+	want := map[string]int{"MutexKV.Lock": 1, "PollingWaitTime.Poll": 2, "PollingWaitTime": 1, "google_sql_user.Create": 1}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("got %d %q spans, want %d", counts[name], name, n)
+		}
+	}
+}
+
+func TestConfigureTracing(t *testing.T) {
+	var mu sync.Mutex
+	exported := 0
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			mu.Lock()
+			exported++
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer collector.Close()
+
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	t.Setenv(TracingEndpointEnvVar, collector.URL)
+
+	if err := ConfigureTracing(context.Background(), "test"); err != nil {
+		t.Fatalf("ConfigureTracing() returned an error: %s", err)
+	}
+	_, span := StartSpan(context.Background(), "google_sql_user.Create")
+	span.End()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if exported != 1 {
+		t.Errorf("the collector received %d exports, want 1", exported)
+	}
+}
@@ -30,6 +30,9 @@ type SendRequestOptions struct {
 	// RPC related opts
 	Product    string
 	RPCService string
+	// RequestLog describes the resource operation sending the request in the
+	// request log
+	RequestLog RequestLogInfo
 }
 
 func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
@@ -60,6 +63,9 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 		opt.Timeout = DefaultRequestTimeout
 	}
 
+	// The attempts of the request are counted across retries in the request log.
+	ctx := contextWithRequestLog(opt.Config.TraceContext(), opt.RequestLog)
+
 	var res *http.Response
 	err := Retry(RetryOptions{
 		RetryFunc: func() error {
@@ -75,7 +81,7 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 			if err != nil {
 				return err
 			}
-			req, err := http.NewRequest(opt.Method, u, &buf)
+			req, err := http.NewRequestWithContext(ctx, opt.Method, u, &buf)
 			if err != nil {
 				return err
 			}
@@ -140,6 +146,9 @@ func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
 		opt.Timeout = DefaultRequestTimeout
 	}
 
+	// The attempts of the request are counted across retries in the request log.
+	ctx := contextWithRequestLog(opt.Config.TraceContext(), opt.RequestLog)
+
 	var res *http.Response
 	err := Retry(RetryOptions{
 		RetryFunc: func() error {
@@ -168,7 +177,7 @@ func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
 				}
 			}
 
-			req, err := http.NewRequest("POST", opt.Config.RPCClients[opt.Product].ProxyAddress+"/handleRPC", &buf)
+			req, err := http.NewRequestWithContext(ctx, "POST", opt.Config.RPCClients[opt.Product].ProxyAddress+"/handleRPC", &buf)
 			if err != nil {
 				return err
 			}
@@ -315,8 +324,11 @@ type ListPagesOptions struct {
 	UserAgent      string
 	ItemName       string
 	Filter         string
+	OrderBy        string
 	Flattener      func(item map[string]interface{}, d *schema.ResourceData, config *Config) error
 	Callback       func(rd *schema.ResourceData) error
+	// RequestLog describes the data source listing the pages in the request log.
+	RequestLog RequestLogInfo
 }
 
 // ListPages performs a paginated GET request against ListURL and processes each item in the
@@ -324,12 +336,16 @@ type ListPagesOptions struct {
 //
 // On each page the function extracts the array at the JSON key ItemName (default "items"),
 // calls Flattener to write each element into TempData, then invokes Callback for further
-// processing of each item.
+// processing of each item. Filter and OrderBy, when set, are sent as the filter and orderBy
+// query parameters.
 func ListPages(opt ListPagesOptions) error {
 	params := make(map[string]string)
 	if opt.Filter != "" {
 		params["filter"] = opt.Filter
 	}
+	if opt.OrderBy != "" {
+		params["orderBy"] = opt.OrderBy
+	}
 
 	for {
 		// Depending on previous iterations, params might contain a pageToken param
@@ -348,6 +364,7 @@ func ListPages(opt ListPagesOptions) error {
 			Headers:   headers,
 			// ErrorRetryPredicates used to allow retrying if rate limits are hit when requesting multiple pages in a row
 			ErrorRetryPredicates: []RetryErrorPredicateFunc{Is429RetryableQuotaError},
+			RequestLog:           opt.RequestLog,
 		})
 		if err != nil {
 			return HandleListGoogleApiError(err, url)
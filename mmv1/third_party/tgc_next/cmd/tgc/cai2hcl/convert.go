@@ -43,12 +43,15 @@ tgc cai2hcl convert ./example/caiassets.json
 `
 
 type convertOptions struct {
-	rootOptions *common.RootOptions
-	outputPath  string
-	dryRun      bool
+	rootOptions  *common.RootOptions
+	outputPath   string
+	iamMembers   bool
+	importBlocks bool
+	references   bool
+	dryRun       bool
 }
 
-var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Logger) ([]byte, error) {
+var origConvertFunc = func(ctx context.Context, path string, options *cai2hcl.Options) ([]byte, error) {
 	assetPayload, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %s", path, err)
@@ -59,9 +62,7 @@ var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Lo
 		return nil, err
 	}
 
-	return cai2hcl.Convert(assets, &cai2hcl.Options{
-		ErrorLogger: errorLogger,
-	})
+	return cai2hcl.Convert(assets, options)
 }
 
 var convertFunc = origConvertFunc
@@ -88,6 +89,9 @@ func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&o.outputPath, "output-path", "", "If specified, write the convert result into the specified output file")
+	cmd.Flags().BoolVar(&o.iamMembers, "iam-members", false, "If specified, convert IAM policies into iam_member resources instead of iam_binding resources")
+	cmd.Flags().BoolVar(&o.importBlocks, "import-blocks", false, "If specified, add import blocks importing the converted resources")
+	cmd.Flags().BoolVar(&o.references, "references", false, "If specified, replace the ids and self-links of converted resources with references to them")
 	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only parse & validate args")
 	cmd.Flags().MarkHidden("dry-run")
 
@@ -104,7 +108,12 @@ func (o *convertOptions) validateArgs(args []string) error {
 func (o *convertOptions) run(path string) error {
 	ctx := context.Background()
 
-	hclBlocks, err := convertFunc(ctx, path, o.rootOptions.ErrorLogger)
+	hclBlocks, err := convertFunc(ctx, path, &cai2hcl.Options{
+		ErrorLogger:  o.rootOptions.ErrorLogger,
+		IamMembers:   o.iamMembers,
+		ImportBlocks: o.importBlocks,
+		References:   o.references,
+	})
 	if err != nil {
 		return err
 	}
@@ -8,9 +8,9 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/common"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl"
 
 	"github.com/stretchr/testify/assert"
-	"go.uber.org/zap"
 )
 
 func testHCLBlocks() []byte {
@@ -29,7 +29,7 @@ resource "google_compute_forwarding_rule" "test-2" {
 	return []byte(testBlock)
 }
 
-func mockConvertHCL(ctx context.Context, path string, errorLogger *zap.Logger) ([]byte, error) {
+func mockConvertHCL(ctx context.Context, path string, options *cai2hcl.Options) ([]byte, error) {
 	return testHCLBlocks(), nil
 }
 
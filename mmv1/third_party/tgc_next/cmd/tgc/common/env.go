@@ -0,0 +1,31 @@
+package common
+
+import "os"
+
+// MultiEnvSearch returns the value of the first set environment variable of ks.
+func MultiEnvSearch(ks []string) string {
+	for _, k := range ks {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// DefaultZone returns the zone set in the environment, if any.
+func DefaultZone() string {
+	return MultiEnvSearch([]string{
+		"GOOGLE_ZONE",
+		"GCLOUD_ZONE",
+		"CLOUDSDK_COMPUTE_ZONE",
+	})
+}
+
+// DefaultRegion returns the region set in the environment, if any.
+func DefaultRegion() string {
+	return MultiEnvSearch([]string{
+		"GOOGLE_REGION",
+		"GCLOUD_REGION",
+		"CLOUDSDK_COMPUTE_REGION",
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/cai2hcl"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/common"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/tfplan2cai"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/validate"
 )
 
 const rootCmdDesc = `
@@ -78,6 +79,7 @@ func newRootCmd() (*cobra.Command, *common.RootOptions, error) {
 
 	cmd.AddCommand(tfplan2cai.NewCmd(o))
 	cmd.AddCommand(cai2hcl.NewCmd(o))
+	cmd.AddCommand(validate.NewCmd(o))
 
 	return cmd, o, nil
 }
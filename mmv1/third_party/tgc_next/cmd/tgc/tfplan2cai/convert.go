@@ -42,27 +42,22 @@ Note:
 Example:
 tgc tfplan2cai convert ./example/terraform.tfplan --project my-project \
     --ancestry organization/my-org/folder/my-folder
-`
 
-func multiEnvSearch(ks []string) string {
-	for _, k := range ks {
-		if v := os.Getenv(k); v != "" {
-			return v
-		}
-	}
-	return ""
-}
+tgc tfplan2cai convert ./example/terraform.tfplan --project my-project \
+    --offline --ancestry-file ./example/cai_export.jsonl
+`
 
 type convertOptions struct {
-	project     string
-	ancestry    string
-	offline     bool
-	rootOptions *common.RootOptions
-	outputPath  string
-	dryRun      bool
+	project      string
+	ancestry     string
+	ancestryFile string
+	offline      bool
+	rootOptions  *common.RootOptions
+	outputPath   string
+	dryRun       bool
 }
 
-var origConvertFunc = func(ctx context.Context, path, project, zone, region string, ancestry map[string]string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.Asset, error) {
+var origConvertFunc = func(ctx context.Context, path, project, zone, region string, ancestry map[string]string, ancestryFile string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.Asset, error) {
 	jsonPlan, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %s", path, err)
@@ -76,6 +71,7 @@ var origConvertFunc = func(ctx context.Context, path, project, zone, region stri
 		DefaultZone:    zone,
 		UserAgent:      userAgent,
 		AncestryCache:  ancestry,
+		AncestryFile:   ancestryFile,
 	})
 }
 
@@ -103,6 +99,7 @@ func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 
 	cmd.Flags().StringVar(&o.project, "project", "", "Provider project override (override the default project configuration assigned to the google terraform provider when converting resources)")
 	cmd.Flags().StringVar(&o.ancestry, "ancestry", "", "Override the ancestry location of the project when validating resources")
+	cmd.Flags().StringVar(&o.ancestryFile, "ancestry-file", "", "Resolve the ancestry of resources from a Cloud Asset Inventory export (JSON or JSONL) or a YAML org hierarchy file")
 	cmd.Flags().BoolVar(&o.offline, "offline", false, "Do not make network requests")
 	cmd.Flags().StringVar(&o.outputPath, "output-path", "", "If specified, write the convert result into the specified output file")
 	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only parse & validate args")
@@ -115,8 +112,8 @@ func (o *convertOptions) validateArgs(args []string) error {
 	if len(args) != 1 {
 		return errors.New("missing required argument TFPLAN_JSON")
 	}
-	if o.offline && o.ancestry == "" {
-		return errors.New("please set ancestry via --ancestry in offline mode")
+	if o.offline && o.ancestry == "" && o.ancestryFile == "" {
+		return errors.New("please set ancestry via --ancestry or --ancestry-file in offline mode")
 	}
 	return nil
 }
@@ -127,19 +124,10 @@ func (o *convertOptions) run(plan string) error {
 	if o.project != "" {
 		ancestryCache[o.project] = o.ancestry
 	}
-	zone := multiEnvSearch([]string{
-		"GOOGLE_ZONE",
-		"GCLOUD_ZONE",
-		"CLOUDSDK_COMPUTE_ZONE",
-	})
-
-	region := multiEnvSearch([]string{
-		"GOOGLE_REGION",
-		"GCLOUD_REGION",
-		"CLOUDSDK_COMPUTE_REGION",
-	})
+	zone := common.DefaultZone()
+	region := common.DefaultRegion()
 	userAgent := "tfplan2cai"
-	assets, err := convertFunc(ctx, plan, o.project, zone, region, ancestryCache, o.offline, o.rootOptions.ErrorLogger, userAgent)
+	assets, err := convertFunc(ctx, plan, o.project, zone, region, ancestryCache, o.ancestryFile, o.offline, o.rootOptions.ErrorLogger, userAgent)
 	if err != nil {
 		return err
 	}
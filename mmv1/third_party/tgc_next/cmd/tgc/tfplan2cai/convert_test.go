@@ -59,7 +59,7 @@ func testAssets(path, project, zone, region string, ancestry map[string]string,
 	}
 }
 
-func mockConvertAssets(ctx context.Context, path, project, zone, region string, ancestry map[string]string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.Asset, error) {
+func mockConvertAssets(ctx context.Context, path, project, zone, region string, ancestry map[string]string, ancestryFile string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.Asset, error) {
 	return testAssets(path, project, zone, region, ancestry, offline, errorLogger, userAgent), nil
 }
 
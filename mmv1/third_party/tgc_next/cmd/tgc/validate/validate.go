@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/common"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/validator"
+)
+
+const cmdDesc = `
+This command will convert a Terraform plan json file into Cloud Asset Inventory(CAI) assets,
+evaluate the Rego and CEL constraints of a directory against them, and report the violations
+per Terraform resource address.
+
+Constraints:
+  .rego files are Rego v1 modules. Each package with a deny rule is a constraint,
+  whose violations are the strings or {"msg", "severity"} objects in deny.
+  .yaml and .yml files are CEL constraints, with a deny expression of the asset
+  and optional name, description, severity, asset_types and message fields.
+  Both get the asset as input, including its ancestors and its ancestry_path.
+
+The command fails if any violation has the error severity. A constraint that
+fails to evaluate against an asset, e.g. a deny object without a msg, is a
+violation with the error severity.
+
+Example:
+tgc validate ./example/terraform.tfplan --policy-path ./policies --project my-project \
+    --ancestry organization/my-org/folder/my-folder --format sarif
+`
+
+var allowedFormats = map[string]struct{}{
+	"text":  {},
+	"json":  {},
+	"sarif": {},
+}
+
+type validateOptions struct {
+	project      string
+	ancestry     string
+	ancestryFile string
+	offline      bool
+	policyPath   string
+	format       string
+	rootOptions  *common.RootOptions
+	outputPath   string
+	dryRun       bool
+}
+
+var origConvertFunc = func(ctx context.Context, path, project, zone, region string, ancestry map[string]string, ancestryFile string, offline bool, errorLogger *zap.Logger) ([]caiasset.Asset, error) {
+	jsonPlan, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %s", path, err)
+	}
+
+	return tfplan2cai.Convert(ctx, jsonPlan, &tfplan2cai.Options{
+		ErrorLogger:    errorLogger,
+		Offline:        offline,
+		DefaultProject: project,
+		DefaultRegion:  region,
+		DefaultZone:    zone,
+		UserAgent:      "tgc-validate",
+		AncestryCache:  ancestry,
+		AncestryFile:   ancestryFile,
+	})
+}
+
+var convertFunc = origConvertFunc
+
+func NewCmd(rootOptions *common.RootOptions) *cobra.Command {
+	o := &validateOptions{
+		rootOptions: rootOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "validate TFPLAN_JSON",
+		Short: "Evaluate constraints against the CAI assets of a Terraform plan",
+		Long:  cmdDesc,
+		PreRunE: func(c *cobra.Command, args []string) error {
+			return o.validateArgs(args)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if o.dryRun {
+				return nil
+			}
+			return o.run(args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&o.policyPath, "policy-path", "", "Directory of the Rego and CEL constraints to evaluate")
+	cmd.Flags().StringVar(&o.format, "format", "text", "Format of the violations. One of: text, json, sarif.")
+	cmd.Flags().StringVar(&o.project, "project", "", "Provider project override (override the default project configuration assigned to the google terraform provider when converting resources)")
+	cmd.Flags().StringVar(&o.ancestry, "ancestry", "", "Override the ancestry location of the project when validating resources")
+	cmd.Flags().StringVar(&o.ancestryFile, "ancestry-file", "", "Resolve the ancestry of resources from a Cloud Asset Inventory export (JSON or JSONL) or a YAML org hierarchy file")
+	cmd.Flags().BoolVar(&o.offline, "offline", false, "Do not make network requests")
+	cmd.Flags().StringVar(&o.outputPath, "output-path", "", "If specified, write the violations into the specified output file")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only parse & validate args")
+	cmd.Flags().MarkHidden("dry-run")
+
+	return cmd
+}
+
+func (o *validateOptions) validateArgs(args []string) error {
+	if len(args) != 1 {
+		return errors.New("missing required argument TFPLAN_JSON")
+	}
+	if o.policyPath == "" {
+		return errors.New("please set the constraints directory via --policy-path")
+	}
+	if _, ok := allowedFormats[o.format]; !ok {
+		return errors.New("format must be one of: text, json, sarif")
+	}
+	if o.offline && o.ancestry == "" && o.ancestryFile == "" {
+		return errors.New("please set ancestry via --ancestry or --ancestry-file in offline mode")
+	}
+	return nil
+}
+
+func (o *validateOptions) run(plan string) error {
+	ctx := context.Background()
+
+	v, err := validator.NewValidator(o.policyPath, o.rootOptions.ErrorLogger)
+	if err != nil {
+		return err
+	}
+
+	ancestryCache := map[string]string{}
+	if o.project != "" {
+		ancestryCache[o.project] = o.ancestry
+	}
+	assets, err := convertFunc(ctx, plan, o.project, common.DefaultZone(), common.DefaultRegion(), ancestryCache, o.ancestryFile, o.offline, o.rootOptions.ErrorLogger)
+	if err != nil {
+		return err
+	}
+
+	violations, err := v.Validate(ctx, assets)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if len(o.outputPath) > 0 {
+		f, err := os.OpenFile(o.outputPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch o.format {
+	case "json":
+		err = validator.WriteJSON(w, violations)
+	case "sarif":
+		err = validator.WriteSARIF(w, plan, violations)
+	default:
+		err = validator.WriteText(w, violations)
+	}
+	if err != nil {
+		return fmt.Errorf("writing violations: %w", err)
+	}
+
+	errorCount := 0
+	for _, violation := range violations {
+		if violation.Severity == validator.SeverityError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("found %d violations with the error severity", errorCount)
+	}
+	return nil
+}
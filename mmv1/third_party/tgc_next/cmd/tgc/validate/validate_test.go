@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/cmd/tgc/common"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/validator"
+)
+
+const diskRego = `package gcp.compute.disk_type
+
+deny contains msg if {
+	input.asset_type == "compute.googleapis.com/Disk"
+	endswith(input.resource.data.type, "/pd-standard")
+	msg := "disks must not use pd-standard"
+}
+`
+
+func mockConvertAssets(ctx context.Context, path, project, zone, region string, ancestry map[string]string, ancestryFile string, offline bool, errorLogger *zap.Logger) ([]caiasset.Asset, error) {
+	diskType := "pd-ssd"
+	if project == "non-compliant" {
+		diskType = "pd-standard"
+	}
+	return []caiasset.Asset{
+		{
+			Name: "//compute.googleapis.com/projects/my-project/zones/us-central1-a/disks/test-disk",
+			Type: "compute.googleapis.com/Disk",
+			Resource: &caiasset.AssetResource{
+				Data: map[string]interface{}{
+					"name": "test-disk",
+					"type": "projects/my-project/zones/us-central1-a/diskTypes/" + diskType,
+				},
+			},
+			TfplanAddress: []string{"google_compute_disk.test"},
+		},
+	}, nil
+}
+
+func newTestOptions(t *testing.T, project string) *validateOptions {
+	policyPath := t.TempDir()
+	if err := os.WriteFile(path.Join(policyPath, "disk.rego"), []byte(diskRego), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errorLogger, _ := common.NewTestErrorLogger("debug", false)
+	outputLogger, _ := common.NewTestOutputLogger()
+	return &validateOptions{
+		project:    project,
+		policyPath: policyPath,
+		format:     "json",
+		outputPath: path.Join(t.TempDir(), "violations.json"),
+		rootOptions: &common.RootOptions{
+			Verbosity:    "debug",
+			ErrorLogger:  errorLogger,
+			OutputLogger: outputLogger,
+		},
+	}
+}
+
+func readViolations(t *testing.T, outputPath string) []validator.Violation {
+	b, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Unable to read file %s: %s", outputPath, err)
+	}
+	var violations []validator.Violation
+	if err := json.Unmarshal(b, &violations); err != nil {
+		t.Fatalf("Failed to unmarshal file %s: %s", outputPath, err)
+	}
+	return violations
+}
+
+func TestValidateRun(t *testing.T) {
+	convertFunc = mockConvertAssets
+	defer func() {
+		convertFunc = origConvertFunc
+	}()
+	a := assert.New(t)
+
+	o := newTestOptions(t, "compliant")
+	a.Nil(o.run("/path/to/plan"))
+	a.Empty(readViolations(t, o.outputPath))
+
+	o = newTestOptions(t, "non-compliant")
+	err := o.run("/path/to/plan")
+	a.EqualError(err, "found 1 violations with the error severity")
+	violations := readViolations(t, o.outputPath)
+	if a.Len(violations, 1) {
+		a.Equal("google_compute_disk.test", violations[0].Address)
+		a.Equal("gcp.compute.disk_type", violations[0].Constraint)
+		a.Equal("disks must not use pd-standard", violations[0].Message)
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	cases := map[string]struct {
+		options validateOptions
+		args    []string
+		wantErr bool
+	}{
+		"valid": {
+			options: validateOptions{policyPath: "policies", format: "sarif"},
+			args:    []string{"plan.json"},
+		},
+		"missing plan": {
+			options: validateOptions{policyPath: "policies", format: "text"},
+			wantErr: true,
+		},
+		"missing policy path": {
+			options: validateOptions{format: "text"},
+			args:    []string{"plan.json"},
+			wantErr: true,
+		},
+		"unknown format": {
+			options: validateOptions{policyPath: "policies", format: "yaml"},
+			args:    []string{"plan.json"},
+			wantErr: true,
+		},
+		"offline without ancestry": {
+			options: validateOptions{policyPath: "policies", format: "text", offline: true},
+			args:    []string{"plan.json"},
+			wantErr: true,
+		},
+		"offline with ancestry file": {
+			options: validateOptions{policyPath: "policies", format: "text", offline: true, ancestryFile: "hierarchy.yaml"},
+			args:    []string{"plan.json"},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.options.validateArgs(c.args)
+			if (err != nil) != c.wantErr {
+				t.Errorf("validateArgs() = %v, want error: %t", err, c.wantErr)
+			}
+		})
+	}
+}
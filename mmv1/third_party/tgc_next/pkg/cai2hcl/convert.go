@@ -3,9 +3,11 @@ package cai2hcl
 import (
 	"bytes"
 	"fmt"
+	"slices"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/resolvers"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
 	"go.uber.org/zap"
 )
@@ -15,6 +17,13 @@ import (
 type Options struct {
 	ErrorLogger     *zap.Logger
 	AreNewResources bool
+	// Splits IAM policies into iam_member blocks instead of iam_binding blocks.
+	IamMembers bool
+	// Emits Terraform 1.5+ import blocks importing the converted resources.
+	ImportBlocks bool
+	// Replaces the literal ids and self-links of converted resources, e.g. the
+	// network of a subnetwork, with references to their resource blocks.
+	References bool
 }
 
 // Converts CAI Assets into HCL string.
@@ -27,18 +36,58 @@ func Convert(assets []caiasset.Asset, options *Options) ([]byte, error) {
 		AreNewResources: options.AreNewResources,
 	}
 
+	iamPolicyResolver := resolvers.NewIamPolicyResolver(options.ErrorLogger, options.IamMembers)
+	orgPolicyResolver := resolvers.NewOrgPolicyResolver(options.ErrorLogger)
+
+	refs := make(references)
 	var allResourceBytes [][]byte
-	// TODO: add resolvers to resolve the assets into single resource assets
-	for _, asset := range assets {
-		resourceBytes, err := converters.ConvertResource([]caiasset.Asset{asset}, converterOptions)
-		if err != nil {
-			return nil, err
+	for _, asset := range resolvers.NewAssetResolver(options.ErrorLogger).Resolve(assets) {
+		var resourceBlocks []*models.TerraformResourceBlock
+		// Assets of the IAM policy or org policy content types have no resource.
+		if asset.Resource != nil {
+			var err error
+			resourceBlocks, err = converters.ConvertResourceBlocks([]caiasset.Asset{asset}, converterOptions)
+			if err != nil {
+				return nil, err
+			}
+			if len(resourceBlocks) > 0 {
+				refs.add(asset, resourceBlocks[0].Labels)
+			}
 		}
 
-		if resourceBytes != nil {
-			allResourceBytes = append(allResourceBytes, resourceBytes)
+		policyBlocks := iamPolicyResolver.Resolve(asset, converterOptions)
+		policyBlocks = append(policyBlocks, orgPolicyResolver.Resolve(asset, converterOptions)...)
+
+		if options.ImportBlocks {
+			var importBlocks []*models.TerraformImportBlock
+			for _, block := range slices.Concat(resourceBlocks, policyBlocks) {
+				importBlock, err := importBlock(block, asset)
+				if err != nil {
+					options.ErrorLogger.Debug(fmt.Sprintf("%s: %s, skipping import block", asset.Name, err))
+					continue
+				}
+				importBlocks = append(importBlocks, importBlock)
+			}
+			if len(importBlocks) > 0 {
+				allResourceBytes = append(allResourceBytes, models.HclWriteImportBlocks(importBlocks))
+			}
+		}
+
+		for _, blocks := range [][]*models.TerraformResourceBlock{resourceBlocks, policyBlocks} {
+			if len(blocks) == 0 {
+				continue
+			}
+			blockBytes, err := models.HclWriteBlocks(blocks)
+			if err != nil {
+				return nil, err
+			}
+			allResourceBytes = append(allResourceBytes, blockBytes)
 		}
 	}
 
-	return bytes.Join(allResourceBytes, []byte("\n")), nil
+	hclBytes := bytes.Join(allResourceBytes, []byte("\n"))
+	if options.References && len(refs) > 0 {
+		return rewriteReferences(hclBytes, refs)
+	}
+	return hclBytes, nil
 }
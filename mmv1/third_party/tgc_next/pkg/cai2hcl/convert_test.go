@@ -6,6 +6,7 @@ import (
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"go.uber.org/zap"
 )
 
 func TestConvertWithResourceName(t *testing.T) {
@@ -77,3 +78,173 @@ func TestConvertWithoutResourceName(t *testing.T) {
 		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
 	}
 }
+
+func TestConvertWithIamPolicy(t *testing.T) {
+	assets := []caiasset.Asset{
+		{
+			Name: "//cloudresourcemanager.googleapis.com/projects/example-project",
+			Type: "cloudresourcemanager.googleapis.com/Project",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Project",
+				Parent:               "//cloudresourcemanager.googleapis.com/folders/456",
+				Data: map[string]interface{}{
+					"name":      "My Project",
+					"projectId": "example-project",
+				},
+			},
+		},
+		{
+			Name: "//cloudresourcemanager.googleapis.com/projects/example-project",
+			Type: "cloudresourcemanager.googleapis.com/Project",
+			IAMPolicy: &caiasset.IAMPolicy{
+				Bindings: []caiasset.IAMBinding{
+					{
+						Role:    "roles/viewer",
+						Members: []string{"user:alice@example.com"},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := Convert(assets, &Options{
+		ErrorLogger: zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "google_project" "example-project" {
+  folder_id  = "456"
+  name       = "My Project"
+  project_id = "example-project"
+}
+
+resource "google_project_iam_binding" "example-project_viewer" {
+  members = ["user:alice@example.com"]
+  project = "example-project"
+  role    = "roles/viewer"
+}
+`
+	if string(got) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
+	}
+}
+
+func TestConvertWithImportBlocksAndReferences(t *testing.T) {
+	assets := []caiasset.Asset{
+		{
+			Name: "//compute.googleapis.com/projects/my-project/global/networks/my-network",
+			Type: "compute.googleapis.com/Network",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Network",
+				Data: map[string]interface{}{
+					"name":                  "my-network",
+					"autoCreateSubnetworks": false,
+				},
+			},
+		},
+		{
+			Name: "//compute.googleapis.com/projects/my-project/regions/us-central1/subnetworks/my-subnetwork",
+			Type: "compute.googleapis.com/Subnetwork",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Subnetwork",
+				Data: map[string]interface{}{
+					"name":        "my-subnetwork",
+					"ipCidrRange": "10.0.0.0/24",
+					"network":     "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-network",
+					"region":      "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1",
+				},
+			},
+		},
+		{
+			Name: "//cloudresourcemanager.googleapis.com/projects/my-project",
+			Type: "cloudresourcemanager.googleapis.com/Project",
+			IAMPolicy: &caiasset.IAMPolicy{
+				Bindings: []caiasset.IAMBinding{
+					{
+						Role:    "roles/compute.networkUser",
+						Members: []string{"user:alice@example.com"},
+					},
+					{
+						Role:    "roles/compute.networkUser",
+						Members: []string{"user:bob@example.com"},
+						Condition: &caiasset.Expr{
+							Title:       "business hours",
+							Description: "Only during business hours",
+							Expression:  "request.time.getHours(\"Europe/Berlin\") < 17",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := Convert(assets, &Options{
+		ErrorLogger:  zap.NewNop(),
+		ImportBlocks: true,
+		References:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = google_compute_network.my-network
+  id = "projects/my-project/global/networks/my-network"
+}
+
+resource "google_compute_network" "my-network" {
+  auto_create_subnetworks = false
+  name                    = "my-network"
+  project                 = "my-project"
+}
+
+import {
+  to = google_compute_subnetwork.my-subnetwork
+  id = "projects/my-project/regions/us-central1/subnetworks/my-subnetwork"
+}
+
+resource "google_compute_subnetwork" "my-subnetwork" {
+  ip_cidr_range = "10.0.0.0/24"
+  name          = "my-subnetwork"
+  network       = google_compute_network.my-network.id
+  project       = "my-project"
+  region        = "us-central1"
+}
+
+import {
+  to = google_project_iam_binding.my-project_compute_networkUser
+  id = "my-project roles/compute.networkUser"
+}
+import {
+  to = google_project_iam_binding.my-project_compute_networkUser_business_hours
+  id = "my-project roles/compute.networkUser business hours"
+}
+
+resource "google_project_iam_binding" "my-project_compute_networkUser" {
+  members = ["user:alice@example.com"]
+  project = "my-project"
+  role    = "roles/compute.networkUser"
+}
+resource "google_project_iam_binding" "my-project_compute_networkUser_business_hours" {
+  condition {
+    description = "Only during business hours"
+    expression  = "request.time.getHours(\"Europe/Berlin\") < 17"
+    title       = "business hours"
+  }
+  members = ["user:bob@example.com"]
+  project = "my-project"
+  role    = "roles/compute.networkUser"
+}
+`
+	if string(got) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
+	}
+}
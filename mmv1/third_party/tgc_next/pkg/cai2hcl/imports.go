@@ -0,0 +1,83 @@
+package cai2hcl
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters/utils"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/resolvers"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+var importIdFieldRegex = regexp.MustCompile(`{{(\w+)}}`)
+
+// importBlock returns the import block importing the resource of a block
+// converted from the asset, using the import id format of the resource.
+func importBlock(block *models.TerraformResourceBlock, asset caiasset.Asset) (*models.TerraformImportBlock, error) {
+	terraformName := block.Labels[0]
+
+	values := make(map[string]any)
+	var format string
+	var resolved bool
+	if resourceImport, ok := converters.ResourceImportMap[terraformName]; ok {
+		format = resourceImport.ImportIdFormat
+		if resourceImport.AssetNameTemplate != "" {
+			utils.ParseUrlParamValuesFromAssetName(asset.Name, resourceImport.AssetNameTemplate, map[string]struct{}{}, values)
+		}
+	} else if format, resolved = resolvers.ImportIdFormat(terraformName); !resolved {
+		return nil, fmt.Errorf("no import id format for %s", terraformName)
+	}
+
+	// Values missing from the asset name are taken from the attributes.
+	if block.Value.Type().IsObjectType() {
+		for k := range block.Value.Type().AttributeTypes() {
+			v := block.Value.GetAttr(k)
+			if _, ok := values[k]; !ok && v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+				values[k] = v.AsString()
+			}
+		}
+	}
+
+	var missing []string
+	id := importIdFieldRegex.ReplaceAllStringFunc(format, func(field string) string {
+		name := importIdFieldRegex.FindStringSubmatch(field)[1]
+		if v, ok := values[name].(string); ok && v != "" {
+			return v
+		}
+		missing = append(missing, name)
+		return field
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no values of %v for the import id %s of %s", missing, format, terraformName)
+	}
+
+	// Conditional IAM bindings and members are imported by the title of their
+	// condition as well.
+	if title := conditionTitle(block.Value); resolved && title != "" {
+		id += " " + title
+	}
+
+	return &models.TerraformImportBlock{
+		To: block.Labels,
+		Id: id,
+	}, nil
+}
+
+// conditionTitle returns the title of the condition block of the value, if any.
+func conditionTitle(val cty.Value) string {
+	if !val.Type().IsObjectType() || !val.Type().HasAttribute("condition") {
+		return ""
+	}
+	condition := val.GetAttr("condition")
+	if condition.IsNull() || !condition.Type().IsObjectType() || !condition.Type().HasAttribute("title") {
+		return ""
+	}
+	if title := condition.GetAttr("title"); title.Type() == cty.String && title.IsKnown() && !title.IsNull() {
+		return title.AsString()
+	}
+	return ""
+}
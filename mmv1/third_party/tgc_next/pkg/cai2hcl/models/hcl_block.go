@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -27,6 +28,29 @@ func HclWriteBlocks(blocks []*TerraformResourceBlock) ([]byte, error) {
 	return hclwrite.Format(f.Bytes()), nil
 }
 
+// TerraformImportBlock imports an existing resource into a resource block.
+type TerraformImportBlock struct {
+	// To is the labels of the resource block.
+	To []string
+	Id string
+}
+
+func HclWriteImportBlocks(blocks []*TerraformImportBlock) []byte {
+	f := hclwrite.NewFile()
+	rootBody := f.Body()
+
+	for _, importBlock := range blocks {
+		body := rootBody.AppendNewBlock("import", nil).Body()
+		body.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: importBlock.To[0]},
+			hcl.TraverseAttr{Name: importBlock.To[1]},
+		})
+		body.SetAttributeValue("id", cty.StringVal(importBlock.Id))
+	}
+
+	return hclwrite.Format(f.Bytes())
+}
+
 func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
 	if val.IsNull() {
 		return nil
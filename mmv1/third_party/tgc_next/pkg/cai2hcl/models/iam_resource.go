@@ -0,0 +1,13 @@
+package models
+
+// IamResource identifies the Terraform IAM resources of a cai asset type.
+type IamResource struct {
+	// TerraformName is the name of the IAM resources without the
+	// "_member"/"_binding" suffix, e.g. "google_pubsub_topic_iam".
+	TerraformName string
+	// AssetNameTemplate parses the parent attributes of the IAM resources from
+	// the asset name, e.g. "//pubsub.googleapis.com/{{topic}}".
+	AssetNameTemplate string
+	// Conditions is whether the IAM resources have a condition block.
+	Conditions bool
+}
@@ -0,0 +1,13 @@
+package models
+
+// ResourceImport identifies the ids importing a Terraform resource.
+type ResourceImport struct {
+	// ImportIdFormat is the format of the import ids, e.g.
+	// "projects/{{project}}/topics/{{name}}".
+	ImportIdFormat string
+	// AssetNameTemplate parses the values of the import id format from the
+	// asset name, e.g. "//pubsub.googleapis.com/projects/{{project}}/topics/{{name}}".
+	// Values missing from the asset name are taken from the attributes of the
+	// resource.
+	AssetNameTemplate string
+}
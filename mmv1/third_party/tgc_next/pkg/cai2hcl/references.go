@@ -0,0 +1,113 @@
+package cai2hcl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// references maps the relative names of converted assets, e.g.
+// "projects/my-project/global/networks/my-network", to the labels of the
+// resource blocks they were converted to.
+type references map[string][]string
+
+// add adds the resource block converted from the asset. Assets with a
+// single-segment relative name, such as buckets, are skipped: any string
+// equal to their name would be taken for a reference.
+func (r references) add(asset caiasset.Asset, labels []string) {
+	_, relativeName, ok := strings.Cut(strings.TrimPrefix(asset.Name, "//"), "/")
+	if !ok || !strings.Contains(relativeName, "/") {
+		return
+	}
+	if _, ok := r[relativeName]; !ok {
+		r[relativeName] = labels
+	}
+}
+
+// lookup returns the labels of the resource block a literal string refers
+// to, as an asset name, a relative name or a self-link.
+func (r references) lookup(s string) ([]string, bool) {
+	if rest, ok := strings.CutPrefix(s, "//"); ok {
+		_, relativeName, _ := strings.Cut(rest, "/")
+		labels, ok := r[relativeName]
+		return labels, ok
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "https" {
+		labels, ok := r[s]
+		return labels, ok
+	}
+
+	// Self-links start with the service path and version, e.g.
+	// "https://www.googleapis.com/compute/v1/projects/...".
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	for i := range segments {
+		if labels, ok := r[strings.Join(segments[i:], "/")]; ok {
+			return labels, true
+		}
+	}
+	return nil, false
+}
+
+// rewriteReferences replaces the literal strings of the resource blocks of
+// hclBytes referring to other converted resources with references to their
+// ids, e.g. google_compute_network.my-network.id.
+func rewriteReferences(hclBytes []byte, refs references) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(hclBytes, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("error parsing the converted resources: %s", diags.Error())
+	}
+
+	for _, block := range f.Body().Blocks() {
+		if block.Type() != "resource" {
+			continue
+		}
+		rewriteBodyReferences(block.Body(), refs, block.Labels())
+	}
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+func rewriteBodyReferences(body *hclwrite.Body, refs references, self []string) {
+	for name, attr := range body.Attributes() {
+		if tokens, ok := rewriteTokenReferences(attr.Expr().BuildTokens(nil), refs, self); ok {
+			body.SetAttributeRaw(name, tokens)
+		}
+	}
+	for _, block := range body.Blocks() {
+		rewriteBodyReferences(block.Body(), refs, self)
+	}
+}
+
+// rewriteTokenReferences replaces the quoted literal strings of tokens, made
+// of an opening quote, a literal and a closing quote, referring to other
+// converted resources.
+func rewriteTokenReferences(tokens hclwrite.Tokens, refs references, self []string) (hclwrite.Tokens, bool) {
+	var rewritten hclwrite.Tokens
+	changed := false
+	for i := 0; i < len(tokens); i++ {
+		if i+2 < len(tokens) && tokens[i].Type == hclsyntax.TokenOQuote && tokens[i+1].Type == hclsyntax.TokenQuotedLit && tokens[i+2].Type == hclsyntax.TokenCQuote {
+			labels, ok := refs.lookup(string(tokens[i+1].Bytes))
+			if ok && !(labels[0] == self[0] && labels[1] == self[1]) {
+				reference := hclwrite.TokensForTraversal(hcl.Traversal{
+					hcl.TraverseRoot{Name: labels[0]},
+					hcl.TraverseAttr{Name: labels[1]},
+					hcl.TraverseAttr{Name: "id"},
+				})
+				reference[0].SpacesBefore = tokens[i].SpacesBefore
+				rewritten = append(rewritten, reference...)
+				changed = true
+				i += 2
+				continue
+			}
+		}
+		rewritten = append(rewritten, tokens[i])
+	}
+	return rewritten, changed
+}
@@ -0,0 +1,29 @@
+package cai2hcl
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+func TestReferencesLookup(t *testing.T) {
+	refs := make(references)
+	refs.add(caiasset.Asset{Name: "//compute.googleapis.com/projects/my-project/global/networks/my-network"}, []string{"google_compute_network", "my-network"})
+	refs.add(caiasset.Asset{Name: "//storage.googleapis.com/my-bucket"}, []string{"google_storage_bucket", "my-bucket"})
+
+	cases := map[string]bool{
+		"projects/my-project/global/networks/my-network":                                             true,
+		"//compute.googleapis.com/projects/my-project/global/networks/my-network":                    true,
+		"https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-network":       true,
+		"https://compute.googleapis.com/compute/beta/projects/my-project/global/networks/my-network": true,
+		"my-network": false,
+		"projects/other-project/global/networks/my-network": false,
+		"my-bucket":                          false,
+		"//storage.googleapis.com/my-bucket": false,
+	}
+	for s, want := range cases {
+		if _, got := refs.lookup(s); got != want {
+			t.Errorf("lookup(%q) found a reference = %t, want %t", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package resolvers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// childAsset identifies the assets represented by their parent asset, which
+// are converted along with the parent.
+type childAsset struct {
+	parentType string
+	// The field listing the children in the resource data of the parent.
+	parentField string
+}
+
+// Children that are part of the parent asset in CAI, such as the NATs of a
+// router, have no assets of their own and need no entry.
+var childAssets = map[string]childAsset{
+	// The node pools of a cluster are converted to its node_pool blocks.
+	"container.googleapis.com/NodePool": {
+		parentType:  "container.googleapis.com/Cluster",
+		parentField: "nodePools",
+	},
+}
+
+type AssetResolver struct {
+	// For logging error / status information that doesn't warrant an outright failure
+	errorLogger *zap.Logger
+}
+
+func NewAssetResolver(errorLogger *zap.Logger) *AssetResolver {
+	return &AssetResolver{
+		errorLogger: errorLogger,
+	}
+}
+
+// Resolve resolves the assets into one asset per resource. It merges the
+// assets of the same resource, e.g. the assets of the resource and of its IAM
+// policy exported separately, and drops the assets represented by their
+// parent asset.
+func (r *AssetResolver) Resolve(assets []caiasset.Asset) []caiasset.Asset {
+	return r.dropChildAssets(r.mergeAssets(assets))
+}
+
+func (r *AssetResolver) mergeAssets(assets []caiasset.Asset) []caiasset.Asset {
+	var merged []caiasset.Asset
+	indexes := make(map[string]int)
+	for _, asset := range assets {
+		key := asset.Type + asset.Name
+		i, ok := indexes[key]
+		if !ok {
+			indexes[key] = len(merged)
+			merged = append(merged, asset)
+			continue
+		}
+
+		m := &merged[i]
+		if asset.Resource != nil {
+			if m.Resource != nil {
+				r.errorLogger.Debug(fmt.Sprintf("%s: multiple resources, ignoring all but the first", asset.Name))
+			} else {
+				m.Resource = asset.Resource
+			}
+		}
+		if asset.IAMPolicy != nil {
+			if m.IAMPolicy != nil {
+				r.errorLogger.Debug(fmt.Sprintf("%s: multiple IAM policies, ignoring all but the first", asset.Name))
+			} else {
+				m.IAMPolicy = asset.IAMPolicy
+			}
+		}
+		m.OrgPolicy = slices.Concat(m.OrgPolicy, asset.OrgPolicy)
+		m.V2OrgPolicies = slices.Concat(m.V2OrgPolicies, asset.V2OrgPolicies)
+		if len(m.Ancestors) == 0 {
+			m.Ancestors = asset.Ancestors
+		}
+	}
+	return merged
+}
+
+func (r *AssetResolver) dropChildAssets(assets []caiasset.Asset) []caiasset.Asset {
+	byName := make(map[string]caiasset.Asset, len(assets))
+	for _, asset := range assets {
+		byName[asset.Name] = asset
+	}
+
+	var resolved []caiasset.Asset
+	for _, asset := range assets {
+		if child, ok := childAssets[asset.Type]; ok {
+			parentName, childId := parentAssetName(asset.Name)
+			if parent, ok := byName[parentName]; ok && parent.Type == child.parentType && listsChild(parent, child.parentField, childId) {
+				r.errorLogger.Debug(fmt.Sprintf("%s: represented by %s, skipping", asset.Name, parentName))
+				continue
+			}
+		}
+		resolved = append(resolved, asset)
+	}
+	return resolved
+}
+
+// parentAssetName splits a child asset name, e.g.
+// "//container.googleapis.com/projects/p/locations/l/clusters/c/nodePools/np",
+// into the name of its parent asset and the id of the child.
+func parentAssetName(name string) (string, string) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 {
+		return "", name
+	}
+	return strings.Join(parts[:len(parts)-2], "/"), parts[len(parts)-1]
+}
+
+func listsChild(parent caiasset.Asset, field, childId string) bool {
+	if parent.Resource == nil {
+		return false
+	}
+	children, _ := parent.Resource.Data[field].([]interface{})
+	for _, c := range children {
+		if m, ok := c.(map[string]interface{}); ok && m["name"] == childId {
+			return true
+		}
+	}
+	return false
+}
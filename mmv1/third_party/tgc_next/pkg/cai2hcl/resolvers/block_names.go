@@ -0,0 +1,73 @@
+package resolvers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+var (
+	digitRegex       = regexp.MustCompile(`^\d+$`)
+	nameValidator    = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_-]*$")
+	invalidNameRegex = regexp.MustCompile("[^a-zA-Z0-9_-]+")
+	rolePrefixRegex  = regexp.MustCompile("^.*roles/")
+)
+
+// resourceBlockName returns the name the resource converters give the block
+// of the asset's resource.
+func resourceBlockName(asset caiasset.Asset, options *models.ResourceConverterOptions) string {
+	if options != nil && options.ResourceName != "" {
+		return options.ResourceName
+	}
+
+	assetNameParts := strings.Split(asset.Name, "/")
+	name := assetNameParts[len(assetNameParts)-1]
+	if digitRegex.MatchString(name) || !nameValidator.MatchString(name) {
+		hasher := sha256.New()
+		hasher.Write([]byte(name))
+		fullHash := hex.EncodeToString(hasher.Sum(nil))
+		name = fmt.Sprintf("resource%s", fullHash[:8])
+	}
+	return name
+}
+
+// blockNames makes unique block names out of the block name of a resource
+// and the values identifying the blocks split out of it.
+type blockNames struct {
+	resourceName string
+	counts       map[string]int
+}
+
+func newBlockNames(resourceName string) *blockNames {
+	return &blockNames{
+		resourceName: resourceName,
+		counts:       make(map[string]int),
+	}
+}
+
+// next returns a block name of the resource name followed by the parts,
+// e.g. "my_topic_pubsub_viewer_user_alice_example_com".
+func (n *blockNames) next(parts ...string) string {
+	name := n.resourceName
+	for _, part := range parts {
+		if part = strings.Trim(invalidNameRegex.ReplaceAllString(part, "_"), "_"); part != "" {
+			name += "_" + part
+		}
+	}
+	n.counts[name]++
+	if count := n.counts[name]; count > 1 {
+		return fmt.Sprintf("%s_%d", name, count)
+	}
+	return name
+}
+
+// roleName returns the role without its prefix, e.g. "pubsub.viewer" for
+// "roles/pubsub.viewer" and "myRole" for "projects/p/roles/myRole".
+func roleName(role string) string {
+	return rolePrefixRegex.ReplaceAllString(role, "")
+}
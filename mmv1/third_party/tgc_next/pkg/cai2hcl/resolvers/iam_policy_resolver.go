@@ -0,0 +1,133 @@
+package resolvers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters/utils"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+type IamPolicyResolver struct {
+	// The IAM resources of the asset types, indexed by asset type.
+	iamResources map[string]models.IamResource
+
+	// Whether to split the IAM policies into iam_member blocks instead of
+	// iam_binding blocks.
+	members bool
+
+	// For logging error / status information that doesn't warrant an outright failure
+	errorLogger *zap.Logger
+}
+
+func NewIamPolicyResolver(errorLogger *zap.Logger, members bool) *IamPolicyResolver {
+	return &IamPolicyResolver{
+		iamResources: converters.IamResourceMap,
+		members:      members,
+		errorLogger:  errorLogger,
+	}
+}
+
+// Resolve splits the IAM policy of the asset into one iam_binding block per
+// role and condition, or one iam_member block per member of a role and
+// condition. The IAM policies of asset types without IAM resources are
+// skipped, as are those with conditions the IAM resources can't express, so as
+// not to grant their roles unconditionally.
+func (r *IamPolicyResolver) Resolve(asset caiasset.Asset, options *models.ResourceConverterOptions) []*models.TerraformResourceBlock {
+	if asset.IAMPolicy == nil || len(asset.IAMPolicy.Bindings) == 0 {
+		return nil
+	}
+
+	iamResource, ok := r.iamResources[asset.Type]
+	if !ok {
+		r.errorLogger.Debug(fmt.Sprintf("%s: no IAM resources for asset type %s, skipping IAM policy", asset.Name, asset.Type))
+		return nil
+	}
+
+	parentData := make(map[string]any)
+	utils.ParseUrlParamValuesFromAssetName(asset.Name, iamResource.AssetNameTemplate, map[string]struct{}{}, parentData)
+	parent := make(map[string]cty.Value, len(parentData))
+	for k, v := range parentData {
+		if v == "" {
+			r.errorLogger.Debug(fmt.Sprintf("%s: no %s in the asset name, skipping IAM policy", asset.Name, k))
+			return nil
+		}
+		parent[k] = cty.StringVal(v.(string))
+	}
+
+	for _, binding := range asset.IAMPolicy.Bindings {
+		if binding.Condition == nil {
+			continue
+		}
+		if !iamResource.Conditions {
+			r.errorLogger.Error(fmt.Sprintf("%s: %s has no IAM conditions, skipping IAM policy with a condition on %s", asset.Name, iamResource.TerraformName, binding.Role))
+			return nil
+		}
+		if binding.Condition.Title == "" || binding.Condition.Expression == "" {
+			r.errorLogger.Error(fmt.Sprintf("%s: condition on %s has no title or expression, skipping IAM policy", asset.Name, binding.Role))
+			return nil
+		}
+	}
+
+	names := newBlockNames(resourceBlockName(asset, options))
+	var blocks []*models.TerraformResourceBlock
+	for _, binding := range asset.IAMPolicy.Bindings {
+		if len(binding.Members) == 0 {
+			continue
+		}
+		members := append([]string(nil), binding.Members...)
+		sort.Strings(members)
+		var conditionTitle string
+		if binding.Condition != nil {
+			conditionTitle = binding.Condition.Title
+		}
+
+		if !r.members {
+			blocks = append(blocks, iamBlock(iamResource.TerraformName+"_binding", names.next(roleName(binding.Role), conditionTitle), parent, binding.Condition, map[string]cty.Value{
+				"role":    cty.StringVal(binding.Role),
+				"members": stringListVal(members),
+			}))
+			continue
+		}
+		for _, member := range members {
+			blocks = append(blocks, iamBlock(iamResource.TerraformName+"_member", names.next(roleName(binding.Role), member, conditionTitle), parent, binding.Condition, map[string]cty.Value{
+				"role":   cty.StringVal(binding.Role),
+				"member": cty.StringVal(member),
+			}))
+		}
+	}
+	return blocks
+}
+
+func iamBlock(terraformName, name string, parent map[string]cty.Value, condition *caiasset.Expr, attributes map[string]cty.Value) *models.TerraformResourceBlock {
+	for k, v := range parent {
+		attributes[k] = v
+	}
+	if condition != nil {
+		conditionAttributes := map[string]cty.Value{
+			"title":      cty.StringVal(condition.Title),
+			"expression": cty.StringVal(condition.Expression),
+		}
+		if condition.Description != "" {
+			conditionAttributes["description"] = cty.StringVal(condition.Description)
+		}
+		attributes["condition"] = cty.ObjectVal(conditionAttributes)
+	}
+	return &models.TerraformResourceBlock{
+		Labels: []string{terraformName, name},
+		Value:  cty.ObjectVal(attributes),
+	}
+}
+
+func stringListVal(values []string) cty.Value {
+	vals := make([]cty.Value, 0, len(values))
+	for _, v := range values {
+		vals = append(vals, cty.StringVal(v))
+	}
+	return cty.ListVal(vals)
+}
@@ -0,0 +1,50 @@
+package resolvers
+
+import (
+	"regexp"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+)
+
+var (
+	templateFieldRegex = regexp.MustCompile(`{{\w+}}`)
+	assetNameHostRegex = regexp.MustCompile(`^//[^/]+/`)
+)
+
+// ImportIdFormat returns the format of the ids importing the resources split
+// out of assets by the resolvers, e.g. "{{topic}} {{role}}" for
+// google_pubsub_topic_iam_binding.
+func ImportIdFormat(terraformName string) (string, bool) {
+	for _, resource := range orgPolicyResources {
+		if resource.terraformName == terraformName {
+			return resource.importIdFormat, true
+		}
+	}
+
+	return iamImportIdFormat(terraformName, converters.IamResourceMap)
+}
+
+// iamImportIdFormat returns the format of the ids importing the IAM resources,
+// whose parent is the field of their asset name template, or the relative name
+// of the template if it has several fields, e.g.
+// "projects/{{project}}/locations/{{location}}/services/{{service}} {{role}}".
+func iamImportIdFormat(terraformName string, iamResources map[string]models.IamResource) (string, bool) {
+	for _, resource := range iamResources {
+		fields := templateFieldRegex.FindAllString(resource.AssetNameTemplate, -1)
+		if len(fields) == 0 {
+			continue
+		}
+		parent := fields[0]
+		if len(fields) > 1 {
+			parent = assetNameHostRegex.ReplaceAllString(resource.AssetNameTemplate, "")
+		}
+		switch terraformName {
+		case resource.TerraformName + "_binding":
+			return parent + " {{role}}", true
+		case resource.TerraformName + "_member":
+			return parent + " {{role}} {{member}}", true
+		}
+	}
+	return "", false
+}
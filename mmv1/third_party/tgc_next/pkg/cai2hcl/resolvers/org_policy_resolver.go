@@ -0,0 +1,134 @@
+package resolvers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters/utils"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// The values of caiasset.ListPolicyAllValues.
+const (
+	listPolicyAllowAll caiasset.ListPolicyAllValues = 1
+	listPolicyDenyAll  caiasset.ListPolicyAllValues = 2
+)
+
+// orgPolicyResource identifies the Terraform resource of the org policies of
+// an asset type.
+type orgPolicyResource struct {
+	terraformName string
+	// Parses the parent attribute of the resource from the asset name.
+	assetNameTemplate string
+	importIdFormat    string
+}
+
+var orgPolicyResources = map[string]orgPolicyResource{
+	"cloudresourcemanager.googleapis.com/Project": {
+		terraformName:     "google_project_organization_policy",
+		assetNameTemplate: "//cloudresourcemanager.googleapis.com/projects/{{project}}",
+		importIdFormat:    "{{project}}:{{constraint}}",
+	},
+	"cloudresourcemanager.googleapis.com/Folder": {
+		terraformName:     "google_folder_organization_policy",
+		assetNameTemplate: "//cloudresourcemanager.googleapis.com/{{folder}}",
+		importIdFormat:    "{{folder}}/{{constraint}}",
+	},
+	"cloudresourcemanager.googleapis.com/Organization": {
+		terraformName:     "google_organization_policy",
+		assetNameTemplate: "//cloudresourcemanager.googleapis.com/organizations/{{org_id}}",
+		importIdFormat:    "{{org_id}}/{{constraint}}",
+	},
+}
+
+type OrgPolicyResolver struct {
+	// For logging error / status information that doesn't warrant an outright failure
+	errorLogger *zap.Logger
+}
+
+func NewOrgPolicyResolver(errorLogger *zap.Logger) *OrgPolicyResolver {
+	return &OrgPolicyResolver{
+		errorLogger: errorLogger,
+	}
+}
+
+// Resolve splits the org policies of the asset into one organization_policy
+// block per constraint. The v2 org policies of the asset are skipped.
+func (r *OrgPolicyResolver) Resolve(asset caiasset.Asset, options *models.ResourceConverterOptions) []*models.TerraformResourceBlock {
+	if len(asset.V2OrgPolicies) > 0 {
+		r.errorLogger.Debug(fmt.Sprintf("%s: v2 org policies are not supported, skipping them", asset.Name))
+	}
+	if len(asset.OrgPolicy) == 0 {
+		return nil
+	}
+
+	resource, ok := orgPolicyResources[asset.Type]
+	if !ok {
+		r.errorLogger.Debug(fmt.Sprintf("%s: no org policy resource for asset type %s, skipping org policies", asset.Name, asset.Type))
+		return nil
+	}
+
+	parentData := make(map[string]any)
+	utils.ParseUrlParamValuesFromAssetName(asset.Name, resource.assetNameTemplate, map[string]struct{}{}, parentData)
+
+	names := newBlockNames(resourceBlockName(asset, options))
+	var blocks []*models.TerraformResourceBlock
+	for _, policy := range asset.OrgPolicy {
+		if policy == nil || policy.Constraint == "" {
+			continue
+		}
+
+		attributes := map[string]cty.Value{
+			"constraint": cty.StringVal(policy.Constraint),
+		}
+		for k, v := range parentData {
+			attributes[k] = cty.StringVal(v.(string))
+		}
+		switch {
+		case policy.BooleanPolicy != nil:
+			attributes["boolean_policy"] = cty.ObjectVal(map[string]cty.Value{
+				"enforced": cty.BoolVal(policy.BooleanPolicy.Enforced),
+			})
+		case policy.ListPolicy != nil:
+			attributes["list_policy"] = listPolicyVal(policy.ListPolicy)
+		case policy.RestoreDefault != nil:
+			attributes["restore_policy"] = cty.ObjectVal(map[string]cty.Value{
+				"default": cty.True,
+			})
+		default:
+			r.errorLogger.Debug(fmt.Sprintf("%s: org policy %s sets no policy, skipping it", asset.Name, policy.Constraint))
+			continue
+		}
+
+		blocks = append(blocks, &models.TerraformResourceBlock{
+			Labels: []string{resource.terraformName, names.next(strings.TrimPrefix(policy.Constraint, "constraints/"))},
+			Value:  cty.ObjectVal(attributes),
+		})
+	}
+	return blocks
+}
+
+func listPolicyVal(policy *caiasset.ListPolicy) cty.Value {
+	attributes := map[string]cty.Value{}
+	switch {
+	case policy.AllValues == listPolicyAllowAll:
+		attributes["allow"] = cty.ObjectVal(map[string]cty.Value{"all": cty.True})
+	case policy.AllValues == listPolicyDenyAll:
+		attributes["deny"] = cty.ObjectVal(map[string]cty.Value{"all": cty.True})
+	case len(policy.AllowedValues) > 0:
+		attributes["allow"] = cty.ObjectVal(map[string]cty.Value{"values": stringListVal(policy.AllowedValues)})
+	case len(policy.DeniedValues) > 0:
+		attributes["deny"] = cty.ObjectVal(map[string]cty.Value{"values": stringListVal(policy.DeniedValues)})
+	}
+	if policy.SuggestedValue != "" {
+		attributes["suggested_value"] = cty.StringVal(policy.SuggestedValue)
+	}
+	if policy.InheritFromParent {
+		attributes["inherit_from_parent"] = cty.True
+	}
+	return cty.ObjectVal(attributes)
+}
@@ -0,0 +1,259 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+func TestAssetResolver(t *testing.T) {
+	clusterName := "//container.googleapis.com/projects/my-project/locations/us-central1/clusters/my-cluster"
+	cluster := &caiasset.AssetResource{
+		Data: map[string]interface{}{
+			"name":      "my-cluster",
+			"nodePools": []interface{}{map[string]interface{}{"name": "default-pool"}},
+		},
+	}
+	iamPolicy := &caiasset.IAMPolicy{
+		Bindings: []caiasset.IAMBinding{{Role: "roles/container.viewer", Members: []string{"user:alice@example.com"}}},
+	}
+	assets := []caiasset.Asset{
+		{Name: clusterName, Type: "container.googleapis.com/Cluster", Resource: cluster},
+		{Name: clusterName + "/nodePools/default-pool", Type: "container.googleapis.com/NodePool", Resource: &caiasset.AssetResource{}},
+		{Name: clusterName + "/nodePools/other-pool", Type: "container.googleapis.com/NodePool", Resource: &caiasset.AssetResource{}},
+		{Name: clusterName, Type: "container.googleapis.com/Cluster", IAMPolicy: iamPolicy},
+	}
+
+	got := NewAssetResolver(zap.NewNop()).Resolve(assets)
+
+	want := []caiasset.Asset{
+		{Name: clusterName, Type: "container.googleapis.com/Cluster", Resource: cluster, IAMPolicy: iamPolicy},
+		{Name: clusterName + "/nodePools/other-pool", Type: "container.googleapis.com/NodePool", Resource: &caiasset.AssetResource{}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Resolve() returned unexpected assets (-want +got):\n%s", diff)
+	}
+}
+
+func TestIamPolicyResolver(t *testing.T) {
+	asset := caiasset.Asset{
+		Name: "//pubsub.googleapis.com/projects/my-project/topics/my-topic",
+		Type: "pubsub.googleapis.com/Topic",
+		IAMPolicy: &caiasset.IAMPolicy{
+			Bindings: []caiasset.IAMBinding{
+				{Role: "roles/pubsub.publisher", Members: []string{"user:bob@example.com", "group:team@example.com"}},
+				{Role: "projects/my-project/roles/customViewer", Members: []string{"user:bob@example.com"}},
+				{
+					Role:    "roles/pubsub.publisher",
+					Members: []string{"user:carol@example.com"},
+					Condition: &caiasset.Expr{
+						Title:      "expires",
+						Expression: `request.time < timestamp("2027-01-01T00:00:00Z")`,
+					},
+				},
+			},
+		},
+	}
+	iamResources := map[string]models.IamResource{
+		"pubsub.googleapis.com/Topic": {
+			TerraformName:     "google_pubsub_topic_iam",
+			AssetNameTemplate: "//pubsub.googleapis.com/{{topic}}",
+			Conditions:        true,
+		},
+	}
+
+	cases := []struct {
+		name    string
+		members bool
+		want    string
+	}{
+		{
+			name: "bindings",
+			want: `resource "google_pubsub_topic_iam_binding" "my-topic_pubsub_publisher" {
+  members = ["group:team@example.com", "user:bob@example.com"]
+  role    = "roles/pubsub.publisher"
+  topic   = "projects/my-project/topics/my-topic"
+}
+resource "google_pubsub_topic_iam_binding" "my-topic_customViewer" {
+  members = ["user:bob@example.com"]
+  role    = "projects/my-project/roles/customViewer"
+  topic   = "projects/my-project/topics/my-topic"
+}
+resource "google_pubsub_topic_iam_binding" "my-topic_pubsub_publisher_expires" {
+  condition {
+    expression = "request.time < timestamp(\"2027-01-01T00:00:00Z\")"
+    title      = "expires"
+  }
+  members = ["user:carol@example.com"]
+  role    = "roles/pubsub.publisher"
+  topic   = "projects/my-project/topics/my-topic"
+}
+`,
+		},
+		{
+			name:    "members",
+			members: true,
+			want: `resource "google_pubsub_topic_iam_member" "my-topic_pubsub_publisher_group_team_example_com" {
+  member = "group:team@example.com"
+  role   = "roles/pubsub.publisher"
+  topic  = "projects/my-project/topics/my-topic"
+}
+resource "google_pubsub_topic_iam_member" "my-topic_pubsub_publisher_user_bob_example_com" {
+  member = "user:bob@example.com"
+  role   = "roles/pubsub.publisher"
+  topic  = "projects/my-project/topics/my-topic"
+}
+resource "google_pubsub_topic_iam_member" "my-topic_customViewer_user_bob_example_com" {
+  member = "user:bob@example.com"
+  role   = "projects/my-project/roles/customViewer"
+  topic  = "projects/my-project/topics/my-topic"
+}
+resource "google_pubsub_topic_iam_member" "my-topic_pubsub_publisher_user_carol_example_com_expires" {
+  condition {
+    expression = "request.time < timestamp(\"2027-01-01T00:00:00Z\")"
+    title      = "expires"
+  }
+  member = "user:carol@example.com"
+  role   = "roles/pubsub.publisher"
+  topic  = "projects/my-project/topics/my-topic"
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewIamPolicyResolver(zap.NewNop(), c.members)
+			r.iamResources = iamResources
+
+			got, err := models.HclWriteBlocks(r.Resolve(asset, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.want, string(got)); diff != "" {
+				t.Errorf("Resolve() returned unexpected blocks (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("conditions unsupported", func(t *testing.T) {
+		r := NewIamPolicyResolver(zap.NewNop(), false)
+		r.iamResources = map[string]models.IamResource{
+			"pubsub.googleapis.com/Topic": {
+				TerraformName:     "google_pubsub_topic_iam",
+				AssetNameTemplate: "//pubsub.googleapis.com/{{topic}}",
+			},
+		}
+
+		if got := r.Resolve(asset, nil); len(got) != 0 {
+			t.Errorf("Resolve() returned %d blocks, want the IAM policy with a condition skipped", len(got))
+		}
+	})
+}
+
+func TestOrgPolicyResolver(t *testing.T) {
+	asset := caiasset.Asset{
+		Name: "//cloudresourcemanager.googleapis.com/folders/123",
+		Type: "cloudresourcemanager.googleapis.com/Folder",
+		OrgPolicy: []*caiasset.OrgPolicy{
+			{Constraint: "constraints/compute.disableSerialPortAccess", BooleanPolicy: &caiasset.BooleanPolicy{Enforced: true}},
+			{Constraint: "constraints/gcp.resourceLocations", ListPolicy: &caiasset.ListPolicy{AllowedValues: []string{"in:us-locations"}, InheritFromParent: true}},
+			{Constraint: "constraints/iam.allowedPolicyMemberDomains", ListPolicy: &caiasset.ListPolicy{AllValues: listPolicyDenyAll}},
+			{Constraint: "constraints/compute.vmExternalIpAccess", RestoreDefault: &caiasset.RestoreDefault{}},
+		},
+	}
+
+	got, err := models.HclWriteBlocks(NewOrgPolicyResolver(zap.NewNop()).Resolve(asset, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `resource "google_folder_organization_policy" "resourcea665a459_compute_disableSerialPortAccess" {
+  boolean_policy {
+    enforced = true
+  }
+  constraint = "constraints/compute.disableSerialPortAccess"
+  folder     = "folders/123"
+}
+resource "google_folder_organization_policy" "resourcea665a459_gcp_resourceLocations" {
+  constraint = "constraints/gcp.resourceLocations"
+  folder     = "folders/123"
+  list_policy {
+    allow {
+      values = ["in:us-locations"]
+    }
+    inherit_from_parent = true
+  }
+}
+resource "google_folder_organization_policy" "resourcea665a459_iam_allowedPolicyMemberDomains" {
+  constraint = "constraints/iam.allowedPolicyMemberDomains"
+  folder     = "folders/123"
+  list_policy {
+    deny {
+      all = true
+    }
+  }
+}
+resource "google_folder_organization_policy" "resourcea665a459_compute_vmExternalIpAccess" {
+  constraint = "constraints/compute.vmExternalIpAccess"
+  folder     = "folders/123"
+  restore_policy {
+    default = true
+  }
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Resolve() returned unexpected blocks (-want +got):\n%s", diff)
+	}
+}
+
+func TestIamImportIdFormat(t *testing.T) {
+	iamResources := map[string]models.IamResource{
+		"pubsub.googleapis.com/Topic": {
+			TerraformName:     "google_pubsub_topic_iam",
+			AssetNameTemplate: "//pubsub.googleapis.com/{{topic}}",
+		},
+		"run.googleapis.com/Service": {
+			TerraformName:     "google_cloud_run_v2_service_iam",
+			AssetNameTemplate: "//run.googleapis.com/projects/{{project}}/locations/{{location}}/services/{{name}}",
+		},
+	}
+
+	cases := []struct {
+		terraformName string
+		want          string
+	}{
+		{
+			terraformName: "google_pubsub_topic_iam_binding",
+			want:          "{{topic}} {{role}}",
+		},
+		{
+			terraformName: "google_cloud_run_v2_service_iam_binding",
+			want:          "projects/{{project}}/locations/{{location}}/services/{{name}} {{role}}",
+		},
+		{
+			terraformName: "google_cloud_run_v2_service_iam_member",
+			want:          "projects/{{project}}/locations/{{location}}/services/{{name}} {{role}} {{member}}",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.terraformName, func(t *testing.T) {
+			got, ok := iamImportIdFormat(c.terraformName, iamResources)
+			if !ok {
+				t.Fatalf("iamImportIdFormat() found no format for %s", c.terraformName)
+			}
+			if got != c.want {
+				t.Errorf("iamImportIdFormat() = %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	if _, ok := iamImportIdFormat("google_pubsub_topic_iam_policy", iamResources); ok {
+		t.Errorf("iamImportIdFormat() found a format for google_pubsub_topic_iam_policy")
+	}
+}
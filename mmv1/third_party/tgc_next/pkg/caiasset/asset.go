@@ -25,10 +25,11 @@ type IAMPolicy struct {
 	Bindings []IAMBinding `json:"bindings"`
 }
 
-// IAMBinding binds a role to a set of members.
+// IAMBinding binds a role to a set of members, if the condition holds.
 type IAMBinding struct {
-	Role    string   `json:"role"`
-	Members []string `json:"members"`
+	Role      string   `json:"role"`
+	Members   []string `json:"members"`
+	Condition *Expr    `json:"condition,omitempty"`
 }
 
 // AssetResource is nested within the Asset type.
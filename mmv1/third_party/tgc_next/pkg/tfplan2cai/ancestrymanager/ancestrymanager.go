@@ -46,6 +46,8 @@ type manager struct {
 	resourceManagerV3 *crmv3.Service
 	resourceManagerV1 *crmv1.Service
 	storageClient     *storage.Service
+	// The hierarchy loaded from a file, if any.
+	hierarchy *Hierarchy
 	// Cache to prevent multiple network calls for looking up the same
 	// resource's ancestry. The map key is the resource itself, in the format of
 	// "<type>/<id>", ancestors are sorted from closest to furthest.
@@ -58,9 +60,17 @@ type manager struct {
 // `folders/`, it will be considered as a project. If offline is true, resource
 // manager API requests for ancestry will be disabled.
 func New(cfg *transport_tpg.Config, offline bool, entries map[string]string, errorLogger *zap.Logger) (AncestryManager, error) {
+	return NewWithHierarchy(cfg, offline, entries, nil, errorLogger)
+}
+
+// NewWithHierarchy returns AncestryManager that resolves ancestry from the
+// hierarchy, if not nil, after the entries. Ancestry missing from both is
+// fetched from the resource manager API, unless offline is true.
+func NewWithHierarchy(cfg *transport_tpg.Config, offline bool, entries map[string]string, hierarchy *Hierarchy, errorLogger *zap.Logger) (AncestryManager, error) {
 	am := &manager{
 		ancestorCache: map[string][]string{},
 		errorLogger:   errorLogger,
+		hierarchy:     hierarchy,
 	}
 	if !offline {
 		am.resourceManagerV1 = rmClient.NewClient(cfg, cfg.UserAgent)
@@ -71,6 +81,11 @@ func New(cfg *transport_tpg.Config, offline bool, entries map[string]string, err
 	if err != nil {
 		return nil, err
 	}
+	if hierarchy != nil {
+		if err := am.initAncestryCache(hierarchy.entries); err != nil {
+			return nil, err
+		}
+	}
 	return am, nil
 }
 
@@ -234,6 +249,9 @@ func (m *manager) getAncestorsWithCache(key string) ([]string, error) {
 			break
 		}
 		if m.resourceManagerV3 == nil || m.resourceManagerV1 == nil {
+			if m.hierarchy != nil {
+				return nil, fmt.Errorf("ancestry of %s not found in %s", cur, m.hierarchy.source)
+			}
 			return nil, fmt.Errorf("resourceManager required to fetch ancestry for %s from the API", cur)
 		}
 		if strings.HasPrefix(cur, projectPrefix) {
@@ -372,6 +390,11 @@ func (m *manager) getProjectFromResource(d tpgresource.TerraformResourceData, co
 		m.errorLogger.Warn(fmt.Sprintf("Failed to retrieve project_id for %s from cai resource", cai.Name))
 
 		bucketField, ok := d.GetOk("bucket")
+		if ok && m.hierarchy != nil {
+			if project, ok := m.hierarchy.bucketProjects[bucketField.(string)]; ok {
+				return project, nil
+			}
+		}
 		if ok && m.storageClient != nil {
 			bucket := bucketField.(string)
 			resp, err := m.storageClient.Buckets.Get(bucket).Do()
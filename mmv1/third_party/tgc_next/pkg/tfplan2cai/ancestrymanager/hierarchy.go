@@ -0,0 +1,235 @@
+package ancestrymanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// Hierarchy is the ancestry of projects, folders and buckets loaded from a
+// file, resolving ancestry without calling the resource manager API.
+type Hierarchy struct {
+	// The file the hierarchy was loaded from.
+	source string
+	// Ancestry paths (like organizations/123/folders/456/projects/789) keyed by
+	// projects/<number>, projects/<id> or folders/<id>.
+	entries map[string]string
+	// Project numbers, or ids if the number is unknown, keyed by bucket name.
+	bucketProjects map[string]string
+}
+
+// hierarchyFile is the format of YAML org hierarchy files:
+//
+//	organizations:
+//	- id: "123"
+//	  folders:
+//	  - id: "456"
+//	    projects:
+//	    - id: my-project
+//	      number: "789"
+//	      buckets:
+//	      - my-bucket
+//	  projects: []
+//	# Projects without an organization
+//	projects: []
+type hierarchyFile struct {
+	Organizations []hierarchyFolder  `yaml:"organizations"`
+	Projects      []hierarchyProject `yaml:"projects"`
+}
+
+type hierarchyFolder struct {
+	Id       string             `yaml:"id"`
+	Folders  []hierarchyFolder  `yaml:"folders"`
+	Projects []hierarchyProject `yaml:"projects"`
+}
+
+type hierarchyProject struct {
+	Id      string   `yaml:"id"`
+	Number  string   `yaml:"number"`
+	Buckets []string `yaml:"buckets"`
+}
+
+// LoadHierarchy loads the hierarchy from a Cloud Asset Inventory export, as a
+// JSON array or JSONL file of assets with their ancestors, or from a YAML org
+// hierarchy file (.yaml or .yml).
+func LoadHierarchy(path string) (*Hierarchy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ancestry file %s: %w", path, err)
+	}
+
+	h := &Hierarchy{
+		source:         path,
+		entries:        make(map[string]string),
+		bucketProjects: make(map[string]string),
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = h.loadYAML(b)
+	default:
+		err = h.loadAssets(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading ancestry file %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Entries returns the ancestry paths of the hierarchy, in the format of the
+// entries of New.
+func (h *Hierarchy) Entries() map[string]string {
+	entries := make(map[string]string, len(h.entries))
+	for k, v := range h.entries {
+		entries[k] = v
+	}
+	return entries
+}
+
+func (h *Hierarchy) loadYAML(b []byte) error {
+	var f hierarchyFile
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	if err := d.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	for _, org := range f.Organizations {
+		id := strings.TrimPrefix(org.Id, orgPrefix)
+		if id == "" {
+			return fmt.Errorf("organization without an id")
+		}
+		if err := h.addFolder(orgPrefix+id, org); err != nil {
+			return err
+		}
+	}
+	for _, project := range f.Projects {
+		if err := h.addProject("", project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFolder adds the folders and projects under the folder or organization at
+// path.
+func (h *Hierarchy) addFolder(path string, folder hierarchyFolder) error {
+	for _, f := range folder.Folders {
+		id := strings.TrimPrefix(f.Id, folderPrefix)
+		if id == "" {
+			return fmt.Errorf("folder without an id in %s", path)
+		}
+		folderPath := path + "/" + folderPrefix + id
+		h.entries[folderPrefix+id] = folderPath
+		if err := h.addFolder(folderPath, f); err != nil {
+			return err
+		}
+	}
+	for _, project := range folder.Projects {
+		if err := h.addProject(path, project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Hierarchy) addProject(parentPath string, project hierarchyProject) error {
+	id := strings.TrimPrefix(project.Id, projectPrefix)
+	number := strings.TrimPrefix(project.Number, projectPrefix)
+	if id == "" && number == "" {
+		return fmt.Errorf("project without an id or number in %s", parentPath)
+	}
+
+	// CAI ancestors use the project number, so that is preferred if it is
+	// available.
+	key := number
+	if key == "" {
+		key = id
+	}
+	path := projectPrefix + key
+	if parentPath != "" {
+		path = parentPath + "/" + path
+	}
+	if number != "" {
+		h.entries[projectPrefix+number] = path
+	}
+	if id != "" {
+		h.entries[projectPrefix+id] = path
+	}
+	for _, bucket := range project.Buckets {
+		h.bucketProjects[bucket] = key
+	}
+	return nil
+}
+
+func (h *Hierarchy) loadAssets(b []byte) error {
+	var assets []caiasset.Asset
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &assets); err != nil {
+			return err
+		}
+	} else {
+		d := json.NewDecoder(bytes.NewReader(b))
+		for {
+			var asset caiasset.Asset
+			if err := d.Decode(&asset); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return err
+			}
+			assets = append(assets, asset)
+		}
+	}
+
+	for _, asset := range assets {
+		if len(asset.Ancestors) == 0 {
+			continue
+		}
+		ancestors := make([]string, len(asset.Ancestors))
+		for i, ancestor := range asset.Ancestors {
+			ancestors[i] = normalizeAncestry(ancestor)
+		}
+		last := ancestors[len(ancestors)-1]
+		if strings.HasPrefix(last, folderPrefix) {
+			return fmt.Errorf("the ancestors of %s end at %s, missing its organization", asset.Name, last)
+		}
+
+		// ancestors are sorted from closest to furthest.
+		path := make([]string, len(ancestors))
+		for i, ancestor := range ancestors {
+			path[len(ancestors)-1-i] = ancestor
+		}
+		for i := range ancestors {
+			if strings.HasPrefix(ancestors[i], orgPrefix) {
+				break
+			}
+			if _, ok := h.entries[ancestors[i]]; !ok {
+				h.entries[ancestors[i]] = strings.Join(path[:len(path)-i], "/")
+			}
+		}
+
+		var data map[string]interface{}
+		if asset.Resource != nil {
+			data = asset.Resource.Data
+		}
+		switch asset.Type {
+		case "cloudresourcemanager.googleapis.com/Project":
+			if id, ok := data["projectId"].(string); ok && id != "" && strings.HasPrefix(ancestors[0], projectPrefix) {
+				h.entries[projectPrefix+id] = strings.Join(path, "/")
+			}
+		case "storage.googleapis.com/Bucket":
+			if bucket, ok := data["name"].(string); ok && bucket != "" && strings.HasPrefix(ancestors[0], projectPrefix) {
+				h.bucketProjects[bucket] = strings.TrimPrefix(ancestors[0], projectPrefix)
+			}
+		}
+	}
+	return nil
+}
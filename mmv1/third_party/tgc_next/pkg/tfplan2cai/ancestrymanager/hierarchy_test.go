@@ -0,0 +1,286 @@
+package ancestrymanager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+const (
+	projectAsset = `{"name": "//cloudresourcemanager.googleapis.com/projects/789", "asset_type": "cloudresourcemanager.googleapis.com/Project", "resource": {"data": {"projectId": "my-project", "projectNumber": "789"}}, "ancestors": ["projects/789", "folders/456", "organizations/123"]}`
+	bucketAsset  = `{"name": "//storage.googleapis.com/my-bucket", "asset_type": "storage.googleapis.com/Bucket", "resource": {"data": {"name": "my-bucket"}}, "ancestors": ["projects/789", "folders/456", "organizations/123"]}`
+)
+
+func writeHierarchyFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadHierarchy(t *testing.T) {
+	cases := []struct {
+		name               string
+		file               string
+		content            string
+		wantEntries        map[string]string
+		wantBucketProjects map[string]string
+	}{
+		{
+			name:    "CAI export JSON",
+			file:    "assets.json",
+			content: "[" + projectAsset + ", " + bucketAsset + "]",
+			wantEntries: map[string]string{
+				"projects/789":        "organizations/123/folders/456/projects/789",
+				"projects/my-project": "organizations/123/folders/456/projects/789",
+				"folders/456":         "organizations/123/folders/456",
+			},
+			wantBucketProjects: map[string]string{"my-bucket": "789"},
+		},
+		{
+			name:    "CAI export JSONL",
+			file:    "assets.jsonl",
+			content: projectAsset + "\n" + bucketAsset + "\n",
+			wantEntries: map[string]string{
+				"projects/789":        "organizations/123/folders/456/projects/789",
+				"projects/my-project": "organizations/123/folders/456/projects/789",
+				"folders/456":         "organizations/123/folders/456",
+			},
+			wantBucketProjects: map[string]string{"my-bucket": "789"},
+		},
+		{
+			name:    "CAI export folder chain",
+			file:    "assets.json",
+			content: `[{"name": "//cloudresourcemanager.googleapis.com/projects/789", "asset_type": "cloudresourcemanager.googleapis.com/Project", "ancestors": ["projects/789", "folders/3", "folders/2", "folders/1", "organizations/123"]}]`,
+			wantEntries: map[string]string{
+				"projects/789": "organizations/123/folders/1/folders/2/folders/3/projects/789",
+				"folders/3":    "organizations/123/folders/1/folders/2/folders/3",
+				"folders/2":    "organizations/123/folders/1/folders/2",
+				"folders/1":    "organizations/123/folders/1",
+			},
+			wantBucketProjects: map[string]string{},
+		},
+		{
+			name:               "CAI export assets without ancestors",
+			file:               "assets.json",
+			content:            `[{"name": "//storage.googleapis.com/my-bucket", "asset_type": "storage.googleapis.com/Bucket"}]`,
+			wantEntries:        map[string]string{},
+			wantBucketProjects: map[string]string{},
+		},
+		{
+			name: "YAML org hierarchy",
+			file: "hierarchy.yaml",
+			content: `
+organizations:
+- id: "123"
+  folders:
+  - id: "456"
+    folders:
+    - id: folders/457
+      projects:
+      - id: nested-project
+        number: "790"
+    projects:
+    - id: my-project
+      number: "789"
+      buckets:
+      - my-bucket
+  projects:
+  - id: org-project
+projects:
+- number: "111"
+  buckets:
+  - orphan-bucket
+`,
+			wantEntries: map[string]string{
+				"folders/456":             "organizations/123/folders/456",
+				"folders/457":             "organizations/123/folders/456/folders/457",
+				"projects/790":            "organizations/123/folders/456/folders/457/projects/790",
+				"projects/nested-project": "organizations/123/folders/456/folders/457/projects/790",
+				"projects/789":            "organizations/123/folders/456/projects/789",
+				"projects/my-project":     "organizations/123/folders/456/projects/789",
+				"projects/org-project":    "organizations/123/projects/org-project",
+				"projects/111":            "projects/111",
+			},
+			wantBucketProjects: map[string]string{"my-bucket": "789", "orphan-bucket": "111"},
+		},
+		{
+			name:               "empty YAML",
+			file:               "hierarchy.yml",
+			wantEntries:        map[string]string{},
+			wantBucketProjects: map[string]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := LoadHierarchy(writeHierarchyFile(t, tc.file, tc.content))
+			if err != nil {
+				t.Fatalf("LoadHierarchy() returned an error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantEntries, h.Entries()); diff != "" {
+				t.Errorf("Entries() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantBucketProjects, h.bucketProjects); diff != "" {
+				t.Errorf("bucket projects mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadHierarchyErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "CAI export missing organization",
+			file:    "assets.json",
+			content: `[{"name": "//cloudresourcemanager.googleapis.com/projects/789", "ancestors": ["projects/789", "folders/456"]}]`,
+			wantErr: "the ancestors of //cloudresourcemanager.googleapis.com/projects/789 end at folders/456, missing its organization",
+		},
+		{
+			name:    "malformed JSON",
+			file:    "assets.json",
+			content: `[{"name": "//cloudresourcemanager.googleapis.com/projects/789",`,
+			wantErr: "unexpected end of JSON input",
+		},
+		{
+			name:    "malformed JSONL",
+			file:    "assets.jsonl",
+			content: projectAsset + "\nnot json\n",
+			wantErr: "invalid character",
+		},
+		{
+			name:    "malformed YAML",
+			file:    "hierarchy.yaml",
+			content: "organizations: [",
+			wantErr: "yaml:",
+		},
+		{
+			name:    "unknown YAML field",
+			file:    "hierarchy.yaml",
+			content: "organizations:\n- id: \"123\"\n  parent: \"1\"\n",
+			wantErr: "field parent not found",
+		},
+		{
+			name:    "organization without an id",
+			file:    "hierarchy.yaml",
+			content: "organizations:\n- folders: []\n",
+			wantErr: "organization without an id",
+		},
+		{
+			name:    "folder without an id",
+			file:    "hierarchy.yaml",
+			content: "organizations:\n- id: \"123\"\n  folders:\n  - projects: []\n",
+			wantErr: "folder without an id in organizations/123",
+		},
+		{
+			name:    "project without an id or number",
+			file:    "hierarchy.yaml",
+			content: "organizations:\n- id: \"123\"\n  projects:\n  - buckets: [my-bucket]\n",
+			wantErr: "project without an id or number in organizations/123",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeHierarchyFile(t, tc.file, tc.content)
+			_, err := LoadHierarchy(path)
+			if err == nil {
+				t.Fatalf("LoadHierarchy() succeeded, want an error containing %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) || !strings.Contains(err.Error(), path) {
+				t.Errorf("LoadHierarchy() = %v, want an error for %s containing %q", err, path, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadHierarchyMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	if _, err := LoadHierarchy(path); err == nil || !strings.Contains(err.Error(), "error reading ancestry file") {
+		t.Errorf("LoadHierarchy() = %v, want an error reading %s", err, path)
+	}
+}
+
+func TestNewWithHierarchy(t *testing.T) {
+	path := writeHierarchyFile(t, "hierarchy.yaml", `
+organizations:
+- id: "123"
+  folders:
+  - id: "456"
+    folders:
+    - id: "457"
+      projects:
+      - id: my-project
+        number: "789"
+`)
+	h, err := LoadHierarchy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]string{"other-project": "organizations/999/projects/111"}
+	m, err := NewWithHierarchy(nil, true, entries, h, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWithHierarchy() returned an error: %v", err)
+	}
+	am := m.(*manager)
+
+	cases := []struct {
+		key     string
+		want    []string
+		wantErr string
+	}{
+		{
+			key:  "projects/my-project",
+			want: []string{"projects/789", "folders/457", "folders/456", "organizations/123"},
+		},
+		{
+			key:  "projects/789",
+			want: []string{"projects/789", "folders/457", "folders/456", "organizations/123"},
+		},
+		{
+			key:  "folders/457",
+			want: []string{"folders/457", "folders/456", "organizations/123"},
+		},
+		{
+			key:  "projects/other-project",
+			want: []string{"projects/111", "organizations/999"},
+		},
+		{
+			key:     "projects/missing-project",
+			wantErr: "ancestry of projects/missing-project not found in " + path,
+		},
+		{
+			key:     "folders/1",
+			wantErr: "ancestry of folders/1 not found in " + path,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			got, err := am.getAncestorsWithCache(tc.key)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("getAncestorsWithCache() = %v, %v, want error %q", got, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getAncestorsWithCache() returned an error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("getAncestorsWithCache() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -33,6 +33,10 @@ type Options struct {
 	// Map hierarchy resource (like projects/<number> or folders/<number>)
 	// to an ancestry path (like organizations/123/folders/456/projects/789)
 	AncestryCache map[string]string
+	// Path of a Cloud Asset Inventory export (JSON or JSONL) or a YAML org
+	// hierarchy file, resolving the ancestry missing from AncestryCache
+	// without calling the resource manager API.
+	AncestryFile string
 
 	// If true, the ancestry manager will be a no-op.
 	NoOpAncestryManager bool
@@ -87,7 +91,14 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 
 	var ancestryManager ancestrymanager.AncestryManager
 	if !o.NoOpAncestryManager {
-		ancestryManager, err = ancestrymanager.New(cfg, o.Offline, o.AncestryCache, o.ErrorLogger)
+		var hierarchy *ancestrymanager.Hierarchy
+		if o.AncestryFile != "" {
+			hierarchy, err = ancestrymanager.LoadHierarchy(o.AncestryFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ancestryManager, err = ancestrymanager.NewWithHierarchy(cfg, o.Offline, o.AncestryCache, hierarchy, o.ErrorLogger)
 		if err != nil {
 			return nil, fmt.Errorf("building ancestry manager: %w", err)
 		}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/ancestrymanager"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/tfplan"
 	legacytfplan2cai "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/tfplan2cai"
 	legacy "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/tfplan2cai/converters/google/resources"
@@ -46,6 +48,17 @@ func Convert(ctx context.Context, jsonPlan []byte, o *tfplan2cai.Options, migrat
 		return nil, fmt.Errorf("converting migrated resources: %w", err)
 	}
 
+	// The legacy converter resolves ancestry from the cache only.
+	ancestryCache := o.AncestryCache
+	if o.AncestryFile != "" {
+		hierarchy, err := ancestrymanager.LoadHierarchy(o.AncestryFile)
+		if err != nil {
+			return nil, err
+		}
+		ancestryCache = hierarchy.Entries()
+		maps.Copy(ancestryCache, o.AncestryCache)
+	}
+
 	legacyOptions := &legacytfplan2cai.Options{
 		ErrorLogger:    o.ErrorLogger,
 		Offline:        o.Offline,
@@ -54,7 +67,7 @@ func Convert(ctx context.Context, jsonPlan []byte, o *tfplan2cai.Options, migrat
 		DefaultZone:    o.DefaultZone,
 		UserAgent:      o.UserAgent,
 		HTTPClient:     o.HTTPClient,
-		AncestryCache:  o.AncestryCache,
+		AncestryCache:  ancestryCache,
 	}
 
 	legacyAssets, err := legacytfplan2cai.ConvertChanges(ctx, legacyChanges, legacyOptions)
@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/ext"
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// celConstraintFile is the format of CEL constraint files:
+//
+//	name: uniform-bucket-level-access
+//	description: Buckets must enable uniform bucket-level access.
+//	# One of error (the default), warning or note.
+//	severity: warning
+//	# Evaluate the constraint against assets of these types only. All assets
+//	# if empty.
+//	asset_types:
+//	- storage.googleapis.com/Bucket
+//	# The asset violates the constraint if deny is true.
+//	deny: "!asset.resource.data.iamConfiguration.uniformBucketLevelAccess.enabled"
+//	message: Uniform bucket-level access is disabled
+//
+// The name defaults to the file name without its extension.
+type celConstraintFile struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Severity    string   `yaml:"severity"`
+	AssetTypes  []string `yaml:"asset_types"`
+	Deny        string   `yaml:"deny"`
+	Message     string   `yaml:"message"`
+}
+
+type celConstraint struct {
+	constraintName string
+	file           string
+	severity       string
+	assetTypes     []string
+	message        string
+	program        cel.Program
+}
+
+func loadCelConstraint(path string) (constraint, error) {
+	b, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f celConstraintFile
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil {
+		return nil, fmt.Errorf("error parsing constraint %s: %w", path, err)
+	}
+	if f.Deny == "" {
+		return nil, fmt.Errorf("constraint %s has no deny expression", path)
+	}
+	if !validSeverity(f.Severity) {
+		return nil, fmt.Errorf("constraint %s has an unknown severity %q", path, f.Severity)
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("asset", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(f.Deny)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("error compiling constraint %s: %w", path, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("constraint %s has a deny expression of type %s, want bool", path, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling constraint %s: %w", path, err)
+	}
+
+	name := f.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	message := f.Message
+	if message == "" {
+		message = f.Description
+	}
+	if message == "" {
+		message = fmt.Sprintf("violates %s", name)
+	}
+	return &celConstraint{
+		constraintName: name,
+		file:           path,
+		severity:       f.Severity,
+		assetTypes:     f.AssetTypes,
+		message:        message,
+		program:        program,
+	}, nil
+}
+
+func (c *celConstraint) name() string {
+	return c.constraintName
+}
+
+func (c *celConstraint) source() string {
+	return c.file
+}
+
+func (c *celConstraint) evaluate(ctx context.Context, asset caiasset.Asset, input map[string]interface{}) ([]Violation, error) {
+	if len(c.assetTypes) > 0 && !slices.Contains(c.assetTypes, asset.Type) {
+		return nil, nil
+	}
+	out, _, err := c.program.ContextEval(ctx, map[string]interface{}{"asset": input})
+	if err != nil {
+		return nil, err
+	}
+	denied, ok := out.Value().(bool)
+	if !ok {
+		return nil, fmt.Errorf("deny is %s, want bool", out.Type())
+	}
+	if !denied {
+		return nil, nil
+	}
+	return []Violation{{Message: c.message, Severity: c.severity}}, nil
+}
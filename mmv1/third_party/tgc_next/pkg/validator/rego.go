@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// regoConstraint is a Rego package with a deny rule, e.g.
+//
+//	package gcp.storage.uniform_bucket_level_access
+//
+//	deny contains msg if {
+//		input.asset_type == "storage.googleapis.com/Bucket"
+//		not input.resource.data.iamConfiguration.uniformBucketLevelAccess.enabled
+//		msg := sprintf("%s must enable uniform bucket-level access", [input.name])
+//	}
+type regoConstraint struct {
+	pkg   string
+	file  string
+	query rego.PreparedEvalQuery
+}
+
+// loadRegoConstraints compiles the modules of the files together, so that
+// constraints can import the packages of other files.
+func loadRegoConstraints(files []string) ([]constraint, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	modules := make(map[string]*ast.Module)
+	// The first file of each package defining deny.
+	denyFiles := make(map[string]string)
+	for _, path := range files {
+		b, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m, err := ast.ParseModule(path, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing constraint %s: %w", path, err)
+		}
+		modules[path] = m
+
+		pkg := strings.TrimPrefix(m.Package.Path.String(), "data.")
+		for _, r := range m.Rules {
+			if _, ok := denyFiles[pkg]; !ok && r.Head.Ref().String() == "deny" {
+				denyFiles[pkg] = path
+			}
+		}
+	}
+
+	compiler := ast.NewCompiler()
+	if compiler.Compile(modules); compiler.Failed() {
+		return nil, fmt.Errorf("error compiling Rego constraints: %w", compiler.Errors)
+	}
+
+	var pkgs []string
+	for pkg := range denyFiles {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	var constraints []constraint
+	for _, pkg := range pkgs {
+		query, err := rego.New(
+			rego.Query(fmt.Sprintf("data.%s.deny", pkg)),
+			rego.Compiler(compiler),
+		).PrepareForEval(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error preparing constraint %s: %w", pkg, err)
+		}
+		constraints = append(constraints, &regoConstraint{
+			pkg:   pkg,
+			file:  denyFiles[pkg],
+			query: query,
+		})
+	}
+	return constraints, nil
+}
+
+func (c *regoConstraint) name() string {
+	return c.pkg
+}
+
+func (c *regoConstraint) source() string {
+	return c.file
+}
+
+func (c *regoConstraint) evaluate(ctx context.Context, _ caiasset.Asset, input map[string]interface{}) ([]Violation, error) {
+	rs, err := c.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, nil
+	}
+	denied, ok := rs[0].Expressions[0].Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("deny is %T, want a set", rs[0].Expressions[0].Value)
+	}
+
+	var violations []Violation
+	for _, d := range denied {
+		switch d := d.(type) {
+		case string:
+			violations = append(violations, Violation{Message: d})
+		case map[string]interface{}:
+			msg, _ := d["msg"].(string)
+			severity, _ := d["severity"].(string)
+			if msg == "" {
+				return nil, fmt.Errorf("deny contains an object without a msg string")
+			}
+			if !validSeverity(severity) {
+				return nil, fmt.Errorf("unknown severity %q", severity)
+			}
+			violations = append(violations, Violation{Message: msg, Severity: severity})
+		default:
+			return nil, fmt.Errorf("deny contains %T, want a string or an object", d)
+		}
+	}
+	return violations, nil
+}
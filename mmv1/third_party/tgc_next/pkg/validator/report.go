@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "tgc"
+	toolUri      = "https://github.com/GoogleCloudPlatform/terraform-google-conversion"
+)
+
+// WriteText writes a line per violation.
+func WriteText(w io.Writer, violations []Violation) error {
+	for _, v := range violations {
+		address := v.Address
+		if address == "" {
+			address = v.AssetName
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s\n", address, v.Severity, v.Constraint, v.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the violations as a JSON array.
+func WriteJSON(w io.Writer, violations []Violation) error {
+	if violations == nil {
+		violations = []Violation{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(violations)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id         string            `json:"id"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the violations as a SARIF 2.1.0 log, with a rule per
+// violated constraint. Violations are located in the plan file, at the
+// Terraform address of their resource.
+func WriteSARIF(w io.Writer, planPath string, violations []Violation) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				InformationUri: toolUri,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndexes := make(map[string]int)
+	for _, v := range violations {
+		index, ok := ruleIndexes[v.Constraint]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndexes[v.Constraint] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				Id:         v.Constraint,
+				Properties: map[string]string{"source": v.Source},
+			})
+		}
+
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{Uri: planPath},
+			},
+		}
+		if v.Address != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: v.Address, Kind: "resource"}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleId:    v.Constraint,
+			RuleIndex: index,
+			Level:     v.Severity,
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", v.AssetName, v.Message)},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,208 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+// Severities of violations, matching the SARIF result levels.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// Violation is an asset violating a constraint.
+type Violation struct {
+	// The Terraform address of the resource the asset was converted from,
+	// empty if the asset has none.
+	Address    string `json:"address"`
+	Constraint string `json:"constraint"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	AssetName  string `json:"asset_name"`
+	AssetType  string `json:"asset_type"`
+	// The file the constraint was loaded from.
+	Source string `json:"source"`
+}
+
+// constraint is a policy evaluated against the assets one at a time.
+type constraint interface {
+	name() string
+	source() string
+	// evaluate returns the violations of the asset, with their message and
+	// severity set.
+	evaluate(ctx context.Context, asset caiasset.Asset, input map[string]interface{}) ([]Violation, error)
+}
+
+// Validator evaluates the Rego and CEL constraints of a directory against CAI
+// assets.
+type Validator struct {
+	constraints []constraint
+	// For logging error / status information that doesn't warrant an outright failure
+	errorLogger *zap.Logger
+}
+
+// NewValidator loads the constraints of a directory and its subdirectories:
+//   - .rego files are Rego v1 modules. Each package with a deny rule is a
+//     constraint, whose violations are the strings or {"msg", "severity"}
+//     objects in deny. Packages without one are libraries of the others.
+//   - .yaml and .yml files are CEL constraints, see celConstraintFile.
+//
+// Both get the asset as input, as converted to JSON, with its ancestry path
+// (like organizations/123/folders/456/projects/789) in ancestry_path.
+func NewValidator(dir string, errorLogger *zap.Logger) (*Validator, error) {
+	var regoFiles, celFiles []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".rego":
+			regoFiles = append(regoFiles, path)
+		case ".yaml", ".yml":
+			celFiles = append(celFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading constraints from %s: %w", dir, err)
+	}
+
+	constraints, err := loadRegoConstraints(regoFiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range celFiles {
+		c, err := loadCelConstraint(path)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, c)
+	}
+	if len(constraints) == 0 {
+		return nil, fmt.Errorf("no constraints found in %s", dir)
+	}
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].name() < constraints[j].name()
+	})
+
+	return &Validator{
+		constraints: constraints,
+		errorLogger: errorLogger,
+	}, nil
+}
+
+// Constraints returns the names of the loaded constraints.
+func (v *Validator) Constraints() []string {
+	var names []string
+	for _, c := range v.constraints {
+		names = append(names, c.name())
+	}
+	return names
+}
+
+// Validate evaluates the constraints against the assets, and returns the
+// violations sorted by Terraform address. An asset converted from several
+// Terraform resources violates a constraint once per address. An asset a
+// constraint fails to evaluate against violates it with the error severity,
+// so that a broken constraint doesn't let the assets pass.
+func (v *Validator) Validate(ctx context.Context, assets []caiasset.Asset) ([]Violation, error) {
+	var violations []Violation
+	for _, asset := range assets {
+		input, err := assetInput(asset)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range v.constraints {
+			cvs, err := c.evaluate(ctx, asset, input)
+			if err != nil {
+				// A constraint not written for the asset, e.g. one reading a
+				// field the asset doesn't have, shouldn't stop the others.
+				v.errorLogger.Error(fmt.Sprintf("error evaluating constraint %s against %s: %s", c.name(), asset.Name, err))
+				cvs = []Violation{{
+					Message:  fmt.Sprintf("error evaluating constraint: %s", err),
+					Severity: SeverityError,
+				}}
+			}
+			for _, cv := range cvs {
+				cv.Constraint = c.name()
+				cv.Source = c.source()
+				cv.AssetName = asset.Name
+				cv.AssetType = asset.Type
+				if cv.Severity == "" {
+					cv.Severity = SeverityError
+				}
+				if len(asset.TfplanAddress) == 0 {
+					violations = append(violations, cv)
+				}
+				for _, address := range asset.TfplanAddress {
+					cv.Address = address
+					violations = append(violations, cv)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		if a.Constraint != b.Constraint {
+			return a.Constraint < b.Constraint
+		}
+		return a.Message < b.Message
+	})
+	return violations, nil
+}
+
+// assetInput is the input of the constraints for an asset: the asset as
+// converted to JSON, with its ancestry path.
+func assetInput(asset caiasset.Asset) (map[string]interface{}, error) {
+	b, err := json.Marshal(asset)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding asset %s: %w", asset.Name, err)
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal(b, &input); err != nil {
+		return nil, fmt.Errorf("error decoding asset %s: %w", asset.Name, err)
+	}
+	input["ancestry_path"] = ancestryPath(asset.Ancestors)
+	return input, nil
+}
+
+// ancestryPath converts ancestors, from the asset up to its organization, to
+// an ancestry path from the organization down.
+func ancestryPath(ancestors []string) string {
+	path := slices.Clone(ancestors)
+	slices.Reverse(path)
+	return strings.Join(path, "/")
+}
+
+func validSeverity(severity string) bool {
+	switch severity {
+	case "", SeverityError, SeverityWarning, SeverityNote:
+		return true
+	}
+	return false
+}
+
+func readFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading constraint %s: %w", path, err)
+	}
+	return b, nil
+}
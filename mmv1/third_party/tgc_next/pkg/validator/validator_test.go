@@ -0,0 +1,266 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+const bucketRego = `package gcp.storage.uniform_bucket_level_access
+
+import data.gcp.lib
+
+deny contains msg if {
+	lib.is_bucket
+	not input.resource.data.iamConfiguration.uniformBucketLevelAccess.enabled
+	msg := sprintf("%s must enable uniform bucket-level access", [input.resource.data.name])
+}
+`
+
+const libRego = `package gcp.lib
+
+is_bucket if input.asset_type == "storage.googleapis.com/Bucket"
+`
+
+const folderRego = `package gcp.resourcemanager.restricted_folder
+
+deny contains {"msg": "resources are not allowed in folder 456", "severity": "warning"} if {
+	startswith(input.ancestry_path, "organizations/123/folders/456/")
+}
+`
+
+const locationCel = `name: bucket-location
+asset_types:
+- storage.googleapis.com/Bucket
+deny: asset.resource.data.location != "EU"
+message: Buckets must be located in the EU
+`
+
+func writeConstraints(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func bucketAsset(name, location string, uniform bool, addresses ...string) caiasset.Asset {
+	return caiasset.Asset{
+		Name: "//storage.googleapis.com/" + name,
+		Type: "storage.googleapis.com/Bucket",
+		Resource: &caiasset.AssetResource{
+			Data: map[string]interface{}{
+				"name":     name,
+				"location": location,
+				"iamConfiguration": map[string]interface{}{
+					"uniformBucketLevelAccess": map[string]interface{}{
+						"enabled": uniform,
+					},
+				},
+			},
+		},
+		Ancestors:     []string{"projects/789", "folders/456", "organizations/123"},
+		TfplanAddress: addresses,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := writeConstraints(t, map[string]string{
+		"storage/bucket.rego":     bucketRego,
+		"lib/lib.rego":            libRego,
+		"folder.rego":             folderRego,
+		"storage/location.yaml":   locationCel,
+		"storage/unrelated.json":  `{}`,
+		"storage/README.markdown": "Not a constraint",
+	})
+	v, err := NewValidator(dir, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantConstraints := []string{
+		"bucket-location",
+		"gcp.resourcemanager.restricted_folder",
+		"gcp.storage.uniform_bucket_level_access",
+	}
+	if diff := cmp.Diff(wantConstraints, v.Constraints()); diff != "" {
+		t.Errorf("Constraints() diff (-want +got):\n%s", diff)
+	}
+
+	assets := []caiasset.Asset{
+		bucketAsset("compliant", "EU", true, "google_storage_bucket.compliant"),
+		bucketAsset("non-compliant", "US", false, "google_storage_bucket.non_compliant"),
+	}
+	// Restrict the folder constraint to the non-compliant bucket.
+	assets[0].Ancestors = []string{"projects/1", "organizations/123"}
+
+	got, err := v.Validate(context.Background(), assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Violation{
+		{
+			Address:    "google_storage_bucket.non_compliant",
+			Constraint: "bucket-location",
+			Severity:   SeverityError,
+			Message:    "Buckets must be located in the EU",
+			AssetName:  "//storage.googleapis.com/non-compliant",
+			AssetType:  "storage.googleapis.com/Bucket",
+			Source:     filepath.Join(dir, "storage/location.yaml"),
+		},
+		{
+			Address:    "google_storage_bucket.non_compliant",
+			Constraint: "gcp.resourcemanager.restricted_folder",
+			Severity:   SeverityWarning,
+			Message:    "resources are not allowed in folder 456",
+			AssetName:  "//storage.googleapis.com/non-compliant",
+			AssetType:  "storage.googleapis.com/Bucket",
+			Source:     filepath.Join(dir, "folder.rego"),
+		},
+		{
+			Address:    "google_storage_bucket.non_compliant",
+			Constraint: "gcp.storage.uniform_bucket_level_access",
+			Severity:   SeverityError,
+			Message:    "non-compliant must enable uniform bucket-level access",
+			AssetName:  "//storage.googleapis.com/non-compliant",
+			AssetType:  "storage.googleapis.com/Bucket",
+			Source:     filepath.Join(dir, "storage/bucket.rego"),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateEvaluationError(t *testing.T) {
+	cases := map[string]map[string]string{
+		// Assets without a resource have no labels to read.
+		"cel": {
+			"labels.yaml": `name: labels
+deny: "!('env' in asset.resource.data.labels)"
+message: Resources must have an env label
+`,
+		},
+		"rego without msg": {
+			"labels.rego": `package labels
+
+deny contains {"severity": "error"} if {
+	not input.resource.data.labels.env
+}
+`,
+		},
+		"rego with an unknown severity": {
+			"labels.rego": `package labels
+
+deny contains {"msg": "resources must have an env label", "severity": "fatal"} if {
+	not input.resource.data.labels.env
+}
+`,
+		},
+	}
+
+	for name, files := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(writeConstraints(t, files), zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assets := []caiasset.Asset{
+				{Name: "//cloudresourcemanager.googleapis.com/projects/my-project", Type: "cloudresourcemanager.googleapis.com/Project"},
+			}
+
+			got, err := v.Validate(context.Background(), assets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 || got[0].Constraint != "labels" || got[0].Severity != SeverityError || got[0].AssetName != assets[0].Name {
+				t.Errorf("Validate() = %v, want a single violation of labels with the error severity", got)
+			}
+		})
+	}
+}
+
+func TestNewValidatorErrors(t *testing.T) {
+	cases := map[string]map[string]string{
+		"no constraints":   {"README.md": "Not a constraint"},
+		"invalid rego":     {"bucket.rego": "package bucket\n\ndeny["},
+		"invalid cel":      {"bucket.yaml": "deny: asset.name =="},
+		"non-bool cel":     {"bucket.yaml": "deny: size(asset)"},
+		"missing deny":     {"bucket.yaml": "name: bucket"},
+		"unknown field":    {"bucket.yaml": "deny: true\nseverty: warning"},
+		"unknown severity": {"bucket.yaml": "deny: true\nseverity: critical"},
+		"unsafe rego":      {"bucket.rego": "package bucket\n\ndeny contains msg if input.name"},
+	}
+	for name, files := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewValidator(writeConstraints(t, files), zap.NewNop()); err == nil {
+				t.Errorf("NewValidator() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	violations := []Violation{
+		{
+			Address:    "google_storage_bucket.a",
+			Constraint: "bucket-location",
+			Severity:   SeverityError,
+			Message:    "Buckets must be located in the EU",
+			AssetName:  "//storage.googleapis.com/a",
+			Source:     "policies/location.yaml",
+		},
+		{
+			Address:    "google_storage_bucket.b",
+			Constraint: "bucket-location",
+			Severity:   SeverityError,
+			Message:    "Buckets must be located in the EU",
+			AssetName:  "//storage.googleapis.com/b",
+			Source:     "policies/location.yaml",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, "plan.json", violations); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Version != sarifVersion || len(got.Runs) != 1 {
+		t.Fatalf("got version %q with %d runs, want version %q with 1 run", got.Version, len(got.Runs), sarifVersion)
+	}
+	run := got.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].Id != "bucket-location" {
+		t.Errorf("got rules %v, want bucket-location only", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(run.Results))
+	}
+	for i, r := range run.Results {
+		if r.RuleIndex != 0 || r.Level != "error" {
+			t.Errorf("result %d has rule index %d and level %q, want 0 and error", i, r.RuleIndex, r.Level)
+		}
+		location := r.Locations[0]
+		if location.PhysicalLocation.ArtifactLocation.Uri != "plan.json" || location.LogicalLocations[0].FullyQualifiedName != violations[i].Address {
+			t.Errorf("result %d is located at %v, want plan.json and %s", i, location, violations[i].Address)
+		}
+	}
+}
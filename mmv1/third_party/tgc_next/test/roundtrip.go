@@ -0,0 +1,717 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	sdkctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/provider"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai"
+	tfplan2caiconverters "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/converters"
+)
+
+// The values of the documentation configs of the samples, used as the defaults
+// of the synthetic plans.
+const (
+	roundTripProject      = "my-project-name"
+	roundTripOrganization = "123456789"
+	roundTripRegion       = "us-central1"
+	roundTripZone         = "us-central1-a"
+)
+
+// The environment variable setting the minimum fidelity, in percent, of each
+// resource converted by RoundTrip. The fidelity is only reported if unset.
+const minFidelityEnv = "TGC_ROUNDTRIP_MIN_FIDELITY"
+
+const (
+	converterTfplan2cai = "tfplan2cai"
+	converterCai2hcl    = "cai2hcl"
+)
+
+// errInvalidConfig is returned for configs that are not valid HCL, e.g. samples
+// with typos, which terraform would reject too.
+var errInvalidConfig = errors.New("invalid config")
+
+// RoundTripConfig is a Terraform configuration converted by RoundTrip.
+type RoundTripConfig struct {
+	Name   string
+	Config string
+}
+
+// RoundTripDiff is a field of a resource lost in the round trip.
+type RoundTripDiff struct {
+	Field string `json:"field"`
+	Want  any    `json:"want"`
+	Got   any    `json:"got,omitempty"`
+	// The converter losing the field, guessed from whether the CAI asset holds
+	// the value of the field. Empty for references to other resources.
+	Converter string `json:"converter,omitempty"`
+}
+
+// RoundTripReport is the fidelity of a resource converted to a CAI asset by
+// tfplan2cai and back to HCL by cai2hcl.
+type RoundTripReport struct {
+	Address string `json:"address"`
+	// The number of fields of the config that are compared, i.e. neither
+	// ignored nor unknown.
+	Compared int             `json:"compared"`
+	Dropped  []RoundTripDiff `json:"dropped,omitempty"`
+	Changed  []RoundTripDiff `json:"changed,omitempty"`
+	// The fields whose values are unknown until apply, e.g. references to
+	// variables or to computed attributes of other resources.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// Preserved returns the number of compared fields that survived the round trip.
+func (r *RoundTripReport) Preserved() int {
+	return r.Compared - len(r.Dropped) - len(r.Changed)
+}
+
+// Fidelity returns the percentage of compared fields that survived the round
+// trip.
+func (r *RoundTripReport) Fidelity() float64 {
+	return fidelity(r.Preserved(), r.Compared)
+}
+
+func (r *RoundTripReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d/%d fields preserved (%.1f%%)", r.Address, r.Preserved(), r.Compared, r.Fidelity())
+	for _, d := range r.Dropped {
+		fmt.Fprintf(&b, "\n  dropped%s: %s = %v", lostBy(d), d.Field, d.Want)
+	}
+	for _, d := range r.Changed {
+		fmt.Fprintf(&b, "\n  changed%s: %s = %v, got %v", lostBy(d), d.Field, d.Want, d.Got)
+	}
+	if len(r.Unknown) > 0 {
+		fmt.Fprintf(&b, "\n  unknown in the plan: %s", strings.Join(r.Unknown, ", "))
+	}
+	return b.String()
+}
+
+func lostBy(d RoundTripDiff) string {
+	if d.Converter == "" {
+		return ""
+	}
+	return " by " + d.Converter
+}
+
+func fidelity(preserved, compared int) float64 {
+	if compared == 0 {
+		return 100
+	}
+	return 100 * float64(preserved) / float64(compared)
+}
+
+// RoundTrip converts each config to CAI assets with tfplan2cai, using a plan
+// built from the config instead of running terraform, and converts the assets
+// back to HCL with cai2hcl. It reports the fields of the resources of
+// resourceType that the converters dropped or changed, and the fidelity of
+// the resource type over all of the configs.
+func RoundTrip(t *testing.T, configs []RoundTripConfig, ignoredFields []string, resourceType string) {
+	if _, ok := tfplan2caiconverters.ConverterMap[resourceType]; !ok {
+		t.Skipf("%s is not supported in tfplan2cai conversion", resourceType)
+	}
+
+	minFidelity := -1.0
+	if v := os.Getenv(minFidelityEnv); v != "" {
+		var err error
+		if minFidelity, err = strconv.ParseFloat(v, 64); err != nil {
+			t.Fatalf("invalid %s %q: %v", minFidelityEnv, v, err)
+		}
+	}
+
+	ignoredFieldSet := make(map[string]any, 0)
+	for _, f := range ignoredFields {
+		ignoredFieldSet[f] = struct{}{}
+	}
+
+	resourceSchemas := provider.Provider().ResourcesMap
+
+	compared, preserved, resources := 0, 0, 0
+	for _, c := range configs {
+		t.Run(c.Name, func(t *testing.T) {
+			reports, err := roundTripConfig(c.Config, ignoredFieldSet, resourceType, resourceSchemas, zaptest.NewLogger(t))
+			if errors.Is(err, errInvalidConfig) {
+				t.Skipf("%s: %v", c.Name, err)
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", c.Name, err)
+			}
+			if os.Getenv("WRITE_FILES") != "" {
+				writeJSONFile(fmt.Sprintf("%s_roundtrip_report.json", strings.ReplaceAll(t.Name(), "/", "_")), reports)
+			}
+			for _, r := range reports {
+				t.Log(r)
+				compared += r.Compared
+				preserved += r.Preserved()
+				resources++
+				if r.Fidelity() < minFidelity {
+					t.Errorf("%s: fidelity %.1f%% is below %.1f%%", r.Address, r.Fidelity(), minFidelity)
+				}
+			}
+		})
+	}
+	t.Logf("%s: round-trip fidelity %.1f%% (%d/%d fields preserved in %d resources)", resourceType, fidelity(preserved, compared), preserved, compared, resources)
+}
+
+// Converts the config and reports the fidelity of each resource of
+// resourceType in it.
+func roundTripConfig(config string, ignoredFields map[string]any, resourceType string, resourceSchemas map[string]*schema.Resource, logger *zap.Logger) (reports []*RoundTripReport, err error) {
+	// Report the panics of the converters as failures of the config, instead of
+	// ending the tests of all of the resources.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic when converting the config: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	plan, err := newSyntheticPlan([]byte(config))
+	if err != nil {
+		return nil, err
+	}
+	jsonPlan, err := plan.marshal(resourceSchemas, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := roundTripTfplan2cai(jsonPlan, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error when converting the plan into assets: %v", err)
+	}
+
+	var assetsCopy []caiasset.Asset
+	// Perform the deep copy in case the assets are transformed in cai2hcl
+	if err := DeepCopyMap(assets, &assetsCopy); err != nil {
+		return nil, err
+	}
+	hclBytes, err := cai2hcl.Convert(assetsCopy, &cai2hcl.Options{
+		ErrorLogger: logger,
+		References:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when converting the assets into config: %v", err)
+	}
+	exported, err := parseHCLBytes(hclBytes, "roundtrip.tf")
+	if err != nil {
+		return nil, err
+	}
+
+	var exportedAddresses []string
+	for address := range exported {
+		if strings.HasPrefix(address, resourceType+".") {
+			exportedAddresses = append(exportedAddresses, address)
+		}
+	}
+	sort.Strings(exportedAddresses)
+
+	for _, r := range plan.resources {
+		if r.resourceType != resourceType {
+			continue
+		}
+		want := make(map[string]any)
+		flatten(r.compared, "", want)
+
+		// Match the resource to the exported resource sharing the most values,
+		// as cai2hcl names resources after the assets.
+		best, bestMatches := -1, -1
+		for i, address := range exportedAddresses {
+			if matches := matchingFields(want, exported[address]); matches > bestMatches {
+				best, bestMatches = i, matches
+			}
+		}
+		var got map[string]any
+		if best >= 0 {
+			got = exported[exportedAddresses[best]]
+			exportedAddresses = slices.Delete(exportedAddresses, best, best+1)
+		}
+		reports = append(reports, compareRoundTrip(r.address, want, got, ignoredFields, resourceSchemas[resourceType], assetData(assets, r.address)))
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no %s resource in the config", resourceType)
+	}
+	return reports, nil
+}
+
+// Converts the plan offline. The ancestry of projects other than the default
+// project is unknown, so the conversion falls back to no ancestry for them.
+func roundTripTfplan2cai(jsonPlan []byte, logger *zap.Logger) ([]caiasset.Asset, error) {
+	options := &tfplan2cai.Options{
+		ErrorLogger:    logger,
+		Offline:        true,
+		DefaultProject: roundTripProject,
+		DefaultRegion:  roundTripRegion,
+		DefaultZone:    roundTripZone,
+		AncestryCache: map[string]string{
+			"projects/" + roundTripProject: "organizations/" + roundTripOrganization,
+		},
+	}
+	assets, err := tfplan2cai.Convert(context.Background(), jsonPlan, options)
+	if err == nil {
+		return assets, nil
+	}
+	logger.Info(fmt.Sprintf("converting without ancestry: %v", err))
+	options.NoOpAncestryManager = true
+	return tfplan2cai.Convert(context.Background(), jsonPlan, options)
+}
+
+// Returns the number of fields of want with the same value in got.
+func matchingFields(want, got map[string]any) int {
+	matches := 0
+	for key, val := range want {
+		if gotVal, ok := got[key]; ok && sameValue(val, gotVal) {
+			matches++
+		}
+	}
+	return matches
+}
+
+// Compares the fields of the config of a resource with the fields of the
+// exported config.
+func compareRoundTrip(address string, want, got, ignoredFields map[string]any, resourceSchema *schema.Resource, data []string) *RoundTripReport {
+	report := &RoundTripReport{Address: address}
+	known := make(map[string]any)
+	for key, val := range want {
+		if isIgnored(key, ignoredFields) {
+			continue
+		}
+		if _, ok := val.(unknownValue); ok {
+			report.Unknown = append(report.Unknown, key)
+			continue
+		}
+		known[key] = val
+	}
+	sort.Strings(report.Unknown)
+	report.Compared = len(known)
+
+	for _, key := range compareHCLFields(known, got, ignoredFields, resourceSchema) {
+		report.Dropped = append(report.Dropped, RoundTripDiff{
+			Field:     key,
+			Want:      known[key],
+			Converter: converterOf(known[key], data),
+		})
+	}
+
+	var keys []string
+	for key := range known {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		gotVal, ok := got[key]
+		if !ok || sameValue(known[key], gotVal) {
+			continue
+		}
+		report.Changed = append(report.Changed, RoundTripDiff{
+			Field:     key,
+			Want:      known[key],
+			Got:       gotVal,
+			Converter: converterOf(known[key], data),
+		})
+	}
+	return report
+}
+
+// Returns whether the values are the same, considering references to other
+// resources the same if they refer to the same type and attribute, as
+// cai2hcl names resources after the assets.
+func sameValue(want, got any) bool {
+	wantStr, gotStr := fmt.Sprint(want), fmt.Sprint(got)
+	if wantStr == gotStr {
+		return true
+	}
+	wantNum, wantErr := strconv.ParseFloat(wantStr, 64)
+	gotNum, gotErr := strconv.ParseFloat(gotStr, 64)
+	if wantErr == nil && gotErr == nil {
+		return wantNum == gotNum
+	}
+	wantType, wantAttr, ok := splitReference(wantStr)
+	if !ok {
+		return false
+	}
+	gotType, gotAttr, ok := splitReference(gotStr)
+	return ok && wantType == gotType && wantAttr == gotAttr
+}
+
+func splitReference(s string) (string, string, bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "google_") {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+// Guesses the converter losing a value: if the asset data holds the value, or
+// a path ending with it, cai2hcl lost it. Otherwise tfplan2cai did.
+func converterOf(val any, data []string) string {
+	s := fmt.Sprint(val)
+	if _, _, ok := splitReference(s); ok {
+		return ""
+	}
+	for _, d := range data {
+		if d == s || strings.HasSuffix(d, "/"+s) {
+			return converterCai2hcl
+		}
+	}
+	return converterTfplan2cai
+}
+
+// Returns the string forms of the values in the data of the assets converted
+// from the resource at address.
+func assetData(assets []caiasset.Asset, address string) []string {
+	var data []string
+	for _, asset := range assets {
+		if !slices.Contains(asset.TfplanAddress, address) || asset.Resource == nil {
+			continue
+		}
+		flattened := make(map[string]any)
+		flatten(asset.Resource.Data, "", flattened)
+		for _, v := range flattened {
+			data = append(data, fmt.Sprint(v))
+		}
+	}
+	return data
+}
+
+// unknownValue is the value of a field that is unknown until apply.
+type unknownValue struct{}
+
+func (unknownValue) String() string {
+	return "(known after apply)"
+}
+
+// syntheticPlan is a Terraform JSON plan creating the resources of a config,
+// built without running terraform.
+type syntheticPlan struct {
+	resources []*plannedResource
+}
+
+type plannedResource struct {
+	address      string
+	resourceType string
+	name         string
+	body         *hclsyntax.Body
+	// The values of the top-level attributes known before apply.
+	values map[string]cty.Value
+	// The planned values of the attributes and blocks.
+	after map[string]any
+	// The expressions of the attributes referencing the ids of other resources.
+	expressions map[string]any
+	// The values of the attributes and blocks in the form parsed by
+	// parseHCLBody, with unknownValue for the values unknown until apply.
+	compared map[string]any
+}
+
+// The functions available to the expressions of the configs.
+var roundTripFunctions = map[string]function.Function{
+	"concat":     stdlib.ConcatFunc,
+	"format":     stdlib.FormatFunc,
+	"join":       stdlib.JoinFunc,
+	"jsondecode": stdlib.JSONDecodeFunc,
+	"jsonencode": stdlib.JSONEncodeFunc,
+	"lower":      stdlib.LowerFunc,
+	"merge":      stdlib.MergeFunc,
+	"replace":    stdlib.ReplaceFunc,
+	"split":      stdlib.SplitFunc,
+	"tolist":     stdlib.MakeToFunc(cty.List(cty.DynamicPseudoType)),
+	"toset":      stdlib.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+	"trimspace":  stdlib.TrimSpaceFunc,
+	"upper":      stdlib.UpperFunc,
+}
+
+// The meta-arguments and blocks of resources that are not part of the plan.
+var metaArguments = map[string]bool{
+	"connection":  true,
+	"count":       true,
+	"depends_on":  true,
+	"dynamic":     true,
+	"for_each":    true,
+	"lifecycle":   true,
+	"provider":    true,
+	"provisioner": true,
+}
+
+func newSyntheticPlan(src []byte) (*syntheticPlan, error) {
+	file, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w: %s", errInvalidConfig, diags)
+	}
+
+	plan := &syntheticPlan{}
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		plan.resources = append(plan.resources, &plannedResource{
+			address:      fmt.Sprintf("%s.%s", block.Labels[0], block.Labels[1]),
+			resourceType: block.Labels[0],
+			name:         block.Labels[1],
+			body:         block.Body,
+			values:       make(map[string]cty.Value),
+		})
+	}
+
+	// Resolve the attributes referring to the known attributes of other
+	// resources, until no more attributes are known.
+	for progress := true; progress; {
+		progress = false
+		ctx := plan.evalContext()
+		for _, r := range plan.resources {
+			for name, attr := range r.body.Attributes {
+				if _, ok := r.values[name]; ok || metaArguments[name] {
+					continue
+				}
+				if val, ok := evaluate(attr.Expr, ctx); ok {
+					r.values[name] = val
+					progress = true
+				}
+			}
+		}
+	}
+
+	ctx := plan.evalContext()
+	for _, r := range plan.resources {
+		r.after, r.expressions, r.compared = plan.planBody(r.body, ctx, true)
+	}
+	return plan, nil
+}
+
+// Returns the context evaluating expressions with the known attributes of the
+// resources.
+func (p *syntheticPlan) evalContext() *hcl.EvalContext {
+	byType := make(map[string]map[string]cty.Value)
+	for _, r := range p.resources {
+		if byType[r.resourceType] == nil {
+			byType[r.resourceType] = make(map[string]cty.Value)
+		}
+		byType[r.resourceType][r.name] = cty.ObjectVal(r.values)
+	}
+	variables := make(map[string]cty.Value)
+	for resourceType, resources := range byType {
+		variables[resourceType] = cty.ObjectVal(resources)
+	}
+	return &hcl.EvalContext{
+		Variables: variables,
+		Functions: roundTripFunctions,
+	}
+}
+
+// Evaluates the expression, returning false if its value is unknown.
+func evaluate(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, bool) {
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+// Returns the address of the resource whose id the expression refers to,
+// e.g. google_compute_network.default for google_compute_network.default.id.
+func (p *syntheticPlan) idReference(expr hcl.Expression) (string, bool) {
+	traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(traversal.Traversal) != 3 {
+		return "", false
+	}
+	address := strings.TrimSuffix(getTraveralExprVal(traversal.Traversal), ".id")
+	for _, r := range p.resources {
+		if r.address == address {
+			return address, true
+		}
+	}
+	return "", false
+}
+
+// Returns the planned values of the attributes and blocks of the body, the
+// expressions referencing the ids of other resources and the values to
+// compare. The ids are only resolved in top-level attributes and attributes
+// of top-level blocks, like terraform-google-conversion does.
+func (p *syntheticPlan) planBody(body *hclsyntax.Body, ctx *hcl.EvalContext, topLevel bool) (after, expressions, compared map[string]any) {
+	after = make(map[string]any)
+	expressions = make(map[string]any)
+	compared = make(map[string]any)
+
+	for name, attr := range body.Attributes {
+		if topLevel && metaArguments[name] {
+			continue
+		}
+		if address, ok := p.idReference(attr.Expr); ok {
+			expressions[name] = map[string]any{
+				"references": []string{address + ".id", address},
+			}
+			compared[name] = getValue(attr.Expr)
+			continue
+		}
+		val, ok := evaluate(attr.Expr, ctx)
+		if !ok {
+			compared[name] = unknownValue{}
+			continue
+		}
+		planned, err := plannedValue(val)
+		if err != nil {
+			compared[name] = unknownValue{}
+			continue
+		}
+		after[name] = planned
+		compared[name] = comparedValue(val)
+	}
+
+	var blockTypes []string
+	blocks := make(map[string][]any)
+	blockExpressions := make(map[string][]any)
+	hasReferences := make(map[string]bool)
+	for _, block := range body.Blocks {
+		if topLevel && metaArguments[block.Type] {
+			continue
+		}
+		if _, ok := blocks[block.Type]; !ok {
+			blockTypes = append(blockTypes, block.Type)
+		}
+		blockAfter, blockExprs, blockCompared := p.planBody(block.Body, ctx, false)
+		if !topLevel {
+			// References in nested blocks are unknown to tfplan2cai.
+			for name := range blockExprs {
+				delete(blockAfter, name)
+				blockCompared[name] = unknownValue{}
+			}
+			blockExprs = map[string]any{}
+		}
+		blocks[block.Type] = append(blocks[block.Type], blockAfter)
+		blockExpressions[block.Type] = append(blockExpressions[block.Type], blockExprs)
+		hasReferences[block.Type] = hasReferences[block.Type] || len(blockExprs) > 0
+		insert(blockCompared, block.Type, compared)
+	}
+	for _, blockType := range blockTypes {
+		after[blockType] = blocks[blockType]
+		if hasReferences[blockType] {
+			expressions[blockType] = blockExpressions[blockType]
+		}
+	}
+	return after, expressions, compared
+}
+
+// Converts the value to its form in the plan.
+func plannedValue(val cty.Value) (any, error) {
+	b, err := ctyjson.SimpleJSONValue{Value: val}.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var planned any
+	err = json.Unmarshal(b, &planned)
+	return planned, err
+}
+
+// Conforms the planned values to the schema of the resource like terraform,
+// e.g. converting numbers to strings and setting the unset attributes to null.
+// The attributes missing from the schema, e.g. timeouts, are left out. The
+// schema uses the fork of go-cty of the plugin SDK.
+func conformToSchema(after map[string]any, res *schema.Resource) (any, error) {
+	ty := res.CoreConfigSchema().ImpliedType()
+	known := make(map[string]any)
+	for name, val := range after {
+		if ty.HasAttribute(name) {
+			known[name] = val
+		}
+	}
+	b, err := json.Marshal(known)
+	if err != nil {
+		return nil, err
+	}
+	val, err := sdkctyjson.Unmarshal(b, ty)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = sdkctyjson.Marshal(val, ty); err != nil {
+		return nil, err
+	}
+	var conformed any
+	err = json.Unmarshal(b, &conformed)
+	return conformed, err
+}
+
+// Converts the value to the form getValue returns for literal expressions.
+func comparedValue(val cty.Value) any {
+	if val.IsNull() {
+		return nil
+	}
+	ty := val.Type()
+	switch {
+	case ty.IsPrimitiveType():
+		return convertValue(val)
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		var elems []string
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elems = append(elems, fmt.Sprint(comparedValue(elem)))
+		}
+		return strings.Join(elems, ",")
+	default:
+		return map[string]any{}
+	}
+}
+
+// Returns the JSON plan creating the resources.
+func (p *syntheticPlan) marshal(resourceSchemas map[string]*schema.Resource, logger *zap.Logger) ([]byte, error) {
+	var changes, configurations []any
+	for _, r := range p.resources {
+		after := any(r.after)
+		if res, ok := resourceSchemas[r.resourceType]; ok {
+			conformed, err := conformToSchema(r.after, res)
+			if err != nil {
+				logger.Info(fmt.Sprintf("%s: planning the values as configured: %v", r.address, err))
+			} else {
+				after = conformed
+			}
+		}
+		changes = append(changes, map[string]any{
+			"address":       r.address,
+			"mode":          "managed",
+			"type":          r.resourceType,
+			"name":          r.name,
+			"provider_name": "registry.terraform.io/hashicorp/google",
+			"change": map[string]any{
+				"actions":       []string{"create"},
+				"before":        nil,
+				"after":         after,
+				"after_unknown": map[string]any{},
+			},
+		})
+		configurations = append(configurations, map[string]any{
+			"address":             r.address,
+			"mode":                "managed",
+			"type":                r.resourceType,
+			"name":                r.name,
+			"provider_config_key": "google",
+			"expressions":         r.expressions,
+		})
+	}
+	return json.Marshal(map[string]any{
+		"format_version":    "1.2",
+		"terraform_version": "1.7.0",
+		"resource_changes":  changes,
+		"configuration": map[string]any{
+			"root_module": map[string]any{
+				"resources": configurations,
+			},
+		},
+	})
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/provider"
+)
+
+const roundTripHCL = `
+variable "network_tier" {
+  default = "PREMIUM"
+}
+
+resource "google_compute_network" "custom-test" {
+  name                    = "test-network"
+  auto_create_subnetworks = false
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+resource "google_compute_subnetwork" "subnetwork" {
+  name          = "${google_compute_network.custom-test.name}-subnetwork"
+  ip_cidr_range = "10.2.0.0/16"
+  region        = "us-central1"
+  network       = google_compute_network.custom-test.id
+  description   = var.network_tier
+
+  secondary_ip_range {
+    range_name    = "tf-test-secondary-range"
+    ip_cidr_range = "192.168.10.0/24"
+  }
+}
+`
+
+func TestSyntheticPlan(t *testing.T) {
+	t.Parallel()
+	plan, err := newSyntheticPlan([]byte(roundTripHCL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.resources) != 2 {
+		t.Fatalf("got %d resources, want 2", len(plan.resources))
+	}
+	subnetwork := plan.resources[1]
+
+	wantAfter := map[string]any{
+		"name":          "test-network-subnetwork",
+		"ip_cidr_range": "10.2.0.0/16",
+		"region":        "us-central1",
+		"secondary_ip_range": []any{
+			map[string]any{
+				"range_name":    "tf-test-secondary-range",
+				"ip_cidr_range": "192.168.10.0/24",
+			},
+		},
+	}
+	if diff := cmp.Diff(wantAfter, subnetwork.after); diff != "" {
+		t.Errorf("after diff (-want +got):\n%s", diff)
+	}
+
+	wantExpressions := map[string]any{
+		"network": map[string]any{
+			"references": []string{"google_compute_network.custom-test.id", "google_compute_network.custom-test"},
+		},
+	}
+	if diff := cmp.Diff(wantExpressions, subnetwork.expressions); diff != "" {
+		t.Errorf("expressions diff (-want +got):\n%s", diff)
+	}
+
+	wantCompared := map[string]any{
+		"name":                             "test-network-subnetwork",
+		"ip_cidr_range":                    "10.2.0.0/16",
+		"region":                           "us-central1",
+		"network":                          "google_compute_network.custom-test.id",
+		"description":                      unknownValue{},
+		"secondary_ip_range.range_name":    "tf-test-secondary-range",
+		"secondary_ip_range.ip_cidr_range": "192.168.10.0/24",
+	}
+	gotCompared := make(map[string]any)
+	flatten(subnetwork.compared, "", gotCompared)
+	if diff := cmp.Diff(wantCompared, gotCompared); diff != "" {
+		t.Errorf("compared diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyntheticPlanMarshal(t *testing.T) {
+	t.Parallel()
+	plan, err := newSyntheticPlan([]byte(roundTripHCL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := plan.marshal(provider.Provider().ResourcesMap, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		ResourceChanges []struct {
+			Address string `json:"address"`
+			Change  struct {
+				Actions []string       `json:"actions"`
+				After   map[string]any `json:"after"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 2 {
+		t.Fatalf("got %d resource changes, want 2", len(got.ResourceChanges))
+	}
+	network := got.ResourceChanges[0]
+	if network.Address != "google_compute_network.custom-test" || !cmp.Equal(network.Change.Actions, []string{"create"}) {
+		t.Errorf("got %s with actions %v, want google_compute_network.custom-test with actions [create]", network.Address, network.Change.Actions)
+	}
+	// The values are conformed to the schema, setting unset attributes to null.
+	if v, ok := network.Change.After["mtu"]; !ok || v != nil {
+		t.Errorf("got mtu %v, want null", v)
+	}
+	if _, ok := network.Change.After["lifecycle"]; ok {
+		t.Errorf("got lifecycle in the planned values, want none")
+	}
+}
+
+func TestCompareRoundTrip(t *testing.T) {
+	t.Parallel()
+	want := map[string]any{
+		"name":                             "test-subnetwork",
+		"description":                      unknownValue{},
+		"ip_cidr_range":                    "10.2.0.0/16",
+		"network":                          "google_compute_network.custom-test.id",
+		"private_ip_google_access":         true,
+		"secondary_ip_range.range_name":    "tf-test-secondary-range",
+		"secondary_ip_range.ip_cidr_range": "192.168.10.0/24",
+		"log_config.flow_sampling":         0.5,
+	}
+	got := map[string]any{
+		"name":                             "test-subnetwork",
+		"ip_cidr_range":                    "10.2.0.0/16",
+		"network":                          "google_compute_network.test-network.id",
+		"secondary_ip_range.range_name":    "tf-test-secondary-range",
+		"secondary_ip_range.ip_cidr_range": "192.168.0.0/24",
+		"log_config.flow_sampling":         "0.50",
+	}
+	data := []string{"test-subnetwork", "10.2.0.0/16", "true", "192.168.10.0/24"}
+	ignoredFields := map[string]any{"log_config": struct{}{}}
+
+	report := compareRoundTrip("google_compute_subnetwork.subnetwork", want, got, ignoredFields, nil, data)
+
+	wantReport := &RoundTripReport{
+		Address:  "google_compute_subnetwork.subnetwork",
+		Compared: 6,
+		Dropped: []RoundTripDiff{
+			{Field: "private_ip_google_access", Want: true, Converter: converterCai2hcl},
+		},
+		Changed: []RoundTripDiff{
+			{Field: "secondary_ip_range.ip_cidr_range", Want: "192.168.10.0/24", Got: "192.168.0.0/24", Converter: converterCai2hcl},
+		},
+		Unknown: []string{"description"},
+	}
+	if diff := cmp.Diff(wantReport, report); diff != "" {
+		t.Errorf("compareRoundTrip() diff (-want +got):\n%s", diff)
+	}
+	if got, want := report.Fidelity(), 100*4/6.0; got != want {
+		t.Errorf("Fidelity() = %v, want %v", got, want)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		want, got any
+		same      bool
+	}{
+		{want: "a", got: "a", same: true},
+		{want: "a", got: "b", same: false},
+		{want: float64(3221225472), got: "3221225472", same: true},
+		{want: "google_compute_network.a.id", got: "google_compute_network.b.id", same: true},
+		{want: "google_compute_network.a.id", got: "google_compute_network.a.self_link", same: false},
+		{want: "google_compute_network.a.id", got: "projects/p/global/networks/a", same: false},
+	}
+	for _, c := range cases {
+		if got := sameValue(c.want, c.got); got != c.same {
+			t.Errorf("sameValue(%v, %v) = %t, want %t", c.want, c.got, got, c.same)
+		}
+	}
+}
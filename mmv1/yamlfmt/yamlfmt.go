@@ -0,0 +1,404 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yamlfmt rewrites product and resource YAML files into a canonical
+// form: keys follow the field order of the api structs they are decoded into,
+// and keys whose removal wouldn't change the loaded value are dropped.
+//
+// Files are rewritten by moving and removing whole lines of the original
+// text, so comments, blank lines and scalar styles are kept as written.
+package yamlfmt
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Format returns the canonical form of content, the contents of the YAML file
+// at path. product.yaml files are formatted as products and all other files
+// as resources.
+func Format(path string, content []byte) ([]byte, error) {
+	kind := resourceKind
+	if filepath.Base(path) == "product.yaml" {
+		kind = productKind
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return content, nil
+	}
+	root := doc.Content[0]
+
+	want, err := kind.load(content)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &formatter{lines: splitLines(content), drop: map[*yaml.Node]bool{}, entries: map[*yaml.Node][]string{}}
+	reordered := f.format(root, kind.typ)
+	if got, err := kind.load(reordered); err != nil || !reflect.DeepEqual(got, want) {
+		return nil, fmt.Errorf("reordering keys changed the meaning of the file, it may use a construct the formatter doesn't support")
+	}
+
+	// Keys the api package omits when marshalling are candidates for removal;
+	// each is only dropped if the file loads to the same value without it.
+	var candidates []*yaml.Node
+	marshalled, err := kind.marshal(want)
+	if err != nil {
+		return nil, err
+	}
+	omittedKeys(root, marshalled, &candidates)
+	for _, key := range candidates {
+		if f.hasComments(key) {
+			// The comment probably explains why the value is spelled out.
+			continue
+		}
+		f.drop = map[*yaml.Node]bool{key: true}
+		if got, err := kind.load(f.format(root, kind.typ)); err == nil && reflect.DeepEqual(got, want) {
+			f.dropped = append(f.dropped, key)
+		}
+	}
+
+	f.drop = map[*yaml.Node]bool{}
+	for _, key := range f.dropped {
+		f.drop[key] = true
+	}
+	formatted := f.format(root, kind.typ)
+	if got, err := kind.load(formatted); err != nil || !reflect.DeepEqual(got, want) {
+		// Keys that can be dropped one at a time may not all be droppable
+		// together; keep them all rather than guess.
+		return reordered, nil
+	}
+	return formatted, nil
+}
+
+type fileKind struct {
+	typ     reflect.Type
+	load    func(content []byte) (any, error)
+	marshal func(v any) (*yaml.Node, error)
+}
+
+var productKind = fileKind{
+	typ: reflect.TypeOf(api.Product{}),
+	load: func(content []byte) (any, error) {
+		p := &api.Product{}
+		if err := decodeStrict(content, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	},
+	marshal: encodeNode,
+}
+
+var resourceKind = fileKind{
+	typ: reflect.TypeOf(api.Resource{}),
+	load: func(content []byte) (any, error) {
+		r := &api.Resource{}
+		if err := decodeStrict(content, r); err != nil {
+			return nil, err
+		}
+		// Defaults are only filled in once a resource belongs to a product,
+		// and values equal to them are what the formatter drops.
+		r.SetDefault(&api.Product{})
+		return r, nil
+	},
+	marshal: encodeNode,
+}
+
+func decodeStrict(content []byte, v any) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	return decoder.Decode(v)
+}
+
+func encodeNode(v any) (*yaml.Node, error) {
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// omittedKeys appends the keys of source that have no counterpart in
+// marshalled, matching mapping entries by key and sequence entries by index.
+func omittedKeys(source, marshalled *yaml.Node, keys *[]*yaml.Node) {
+	switch {
+	case source.Kind == yaml.MappingNode && marshalled.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(source.Content); i += 2 {
+			value := mappingValue(marshalled, source.Content[i].Value)
+			if value == nil {
+				*keys = append(*keys, source.Content[i])
+				continue
+			}
+			omittedKeys(source.Content[i+1], value, keys)
+		}
+	case source.Kind == yaml.SequenceNode && marshalled.Kind == yaml.SequenceNode:
+		for i := 0; i < len(source.Content) && i < len(marshalled.Content); i++ {
+			omittedKeys(source.Content[i], marshalled.Content[i], keys)
+		}
+	}
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// formatter rebuilds a file from the lines of its original text.
+type formatter struct {
+	lines   []string
+	drop    map[*yaml.Node]bool
+	dropped []*yaml.Node
+
+	// The original lines of each mapping entry, keyed by its key node.
+	entries map[*yaml.Node][]string
+}
+
+// hasComments reports whether the mapping entry for key contains a comment.
+func (f *formatter) hasComments(key *yaml.Node) bool {
+	for _, line := range f.entries[key] {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return true
+		}
+	}
+	return key.LineComment != ""
+}
+
+// format returns the canonical text of the document whose root is root.
+func (f *formatter) format(root *yaml.Node, t reflect.Type) []byte {
+	return []byte(strings.Join(f.node(root, t, 0, len(f.lines)), ""))
+}
+
+// node returns the canonical text of n, which is held in lines [start, end).
+func (f *formatter) node(n *yaml.Node, t reflect.Type, start, end int) []string {
+	if n.Style&yaml.FlowStyle != 0 {
+		return f.lines[start:end]
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		return f.mapping(n, t, start, end)
+	case yaml.SequenceNode:
+		return f.sequence(n, t, start, end)
+	}
+	return f.lines[start:end]
+}
+
+// entry is a mapping key and its value, along with any comment lines directly
+// above the key.
+type entry struct {
+	key   *yaml.Node
+	order int
+	lines []string
+	// Blank lines that followed the entry in the original text.
+	blanks int
+}
+
+func (f *formatter) mapping(n *yaml.Node, t reflect.Type, start, end int) []string {
+	if len(n.Content) == 0 {
+		return f.lines[start:end]
+	}
+	column := n.Content[0].Column - 1
+
+	// Lines before the first key, such as a license header or comments above
+	// a sequence entry, stay in place.
+	firstLine := n.Content[0].Line - 1
+	out := slices.Clone(f.lines[start:firstLine])
+
+	var order map[string]int
+	var valueType func(key string) reflect.Type
+	switch {
+	case t != nil && t.Kind() == reflect.Struct:
+		order, valueType = structFields(t)
+	case t != nil && t.Kind() == reflect.Map:
+		valueType = func(string) reflect.Type { return t.Elem() }
+	default:
+		valueType = func(string) reflect.Type { return nil }
+	}
+
+	// The first key may share its line with a sequence entry's "- ", which
+	// has to stay on the first line whichever key ends up first.
+	linePrefix := f.lines[firstLine][:column]
+
+	var entries []*entry
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		entryStart := key.Line - 1
+		if i > 0 {
+			entryStart = f.commentStart(entryStart, column, n.Content[i-2].Line)
+		}
+		entryEnd := end
+		if i+2 < len(n.Content) {
+			next := n.Content[i+2]
+			entryEnd = f.commentStart(next.Line-1, column, key.Line)
+		}
+
+		blanks := 0
+		for entryEnd-blanks-1 > key.Line-1 && strings.TrimSpace(f.lines[entryEnd-blanks-1]) == "" {
+			blanks++
+		}
+		contentEnd := entryEnd - blanks
+		f.entries[key] = f.lines[entryStart:contentEnd]
+
+		var lines []string
+		if (value.Kind == yaml.MappingNode || value.Kind == yaml.SequenceNode) && value.Line > key.Line {
+			lines = slices.Clone(f.lines[entryStart:key.Line])
+			lines = append(lines, f.node(value, valueType(key.Value), key.Line, contentEnd)...)
+		} else {
+			lines = slices.Clone(f.lines[entryStart:contentEnd])
+		}
+		if i == 0 {
+			lines[0] = strings.Repeat(" ", column) + lines[0][column:]
+		}
+
+		e := &entry{key: key, order: len(entries), lines: lines, blanks: blanks}
+		if pos, ok := order[key.Value]; ok {
+			e.order = pos
+		} else {
+			// Unknown keys go last, in their original order.
+			e.order = len(order) + len(entries)
+		}
+		entries = append(entries, e)
+	}
+
+	// Blank lines separate positions in the mapping rather than particular
+	// keys, so they stay where they were while keys move around them.
+	var kept []*entry
+	var blanks []int
+	for _, e := range entries {
+		if f.drop[e.key] {
+			if len(blanks) > 0 {
+				blanks[len(blanks)-1] = max(blanks[len(blanks)-1], e.blanks)
+			}
+			continue
+		}
+		kept = append(kept, e)
+		blanks = append(blanks, e.blanks)
+	}
+	slices.SortStableFunc(kept, func(a, b *entry) int { return a.order - b.order })
+
+	for i, e := range kept {
+		lines := e.lines
+		if i == 0 {
+			// Comments above the new first key move above the "- " line.
+			keyLine := 0
+			for strings.HasPrefix(strings.TrimSpace(lines[keyLine]), "#") {
+				keyLine++
+			}
+			indent := linePrefix[:len(linePrefix)-len(strings.TrimLeft(linePrefix, " "))]
+			for _, comment := range lines[:keyLine] {
+				out = append(out, indent+strings.TrimLeft(comment, " "))
+			}
+			lines = slices.Clone(lines[keyLine:])
+			lines[0] = linePrefix + lines[0][column:]
+		}
+		out = append(out, lines...)
+		for j := 0; j < blanks[i]; j++ {
+			out = append(out, "\n")
+		}
+	}
+	return out
+}
+
+func (f *formatter) sequence(n *yaml.Node, t reflect.Type, start, end int) []string {
+	if len(n.Content) == 0 || t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return f.lines[start:end]
+	}
+	column := n.Content[0].Column - 3
+	out := slices.Clone(f.lines[start : n.Content[0].Line-1])
+	for i, item := range n.Content {
+		itemStart := item.Line - 1
+		if i > 0 {
+			itemStart = f.commentStart(itemStart, column, n.Content[i-1].Line)
+		}
+		itemEnd := end
+		if i+1 < len(n.Content) {
+			itemEnd = f.commentStart(n.Content[i+1].Line-1, column, item.Line)
+		}
+		if item.Kind == yaml.MappingNode && item.Style&yaml.FlowStyle == 0 {
+			out = append(out, f.node(item, t.Elem(), itemStart, itemEnd)...)
+		} else {
+			out = append(out, f.lines[itemStart:itemEnd]...)
+		}
+	}
+	return out
+}
+
+// commentStart returns the first of the comment lines indented by column
+// directly above line, without going above the line after floor.
+func (f *formatter) commentStart(line, column, floor int) int {
+	for line > floor {
+		prev := f.lines[line-1]
+		trimmed := strings.TrimLeft(prev, " ")
+		if !strings.HasPrefix(trimmed, "#") || len(prev)-len(trimmed) != column {
+			break
+		}
+		line--
+	}
+	return line
+}
+
+// structFields returns the position of each YAML key of t in field order,
+// and a function giving the Go type a key is decoded into. It follows the
+// same rules as gopkg.in/yaml.v3: untagged fields use their lowercased name
+// and ",inline" fields contribute their own keys.
+func structFields(t reflect.Type) (map[string]int, func(string) reflect.Type) {
+	order := map[string]int{}
+	types := map[string]reflect.Type{}
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("yaml")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			key, opts, _ := strings.Cut(tag, ",")
+			if strings.Contains(","+opts+",", ",inline,") {
+				add(field.Type)
+				continue
+			}
+			if key == "" {
+				key = strings.ToLower(field.Name)
+			}
+			order[key] = len(order)
+			types[key] = field.Type
+		}
+	}
+	add(t)
+	return order, func(key string) reflect.Type { return types[key] }
+}
+
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlfmt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		name  string
+		path  string
+		input string
+		want  string
+	}{
+		{
+			name: "reorders resource keys keeping comments and blank lines",
+			path: "products/foo/Thing.yaml",
+			input: `# Copyright header
+---
+base_url: 'projects/{{project}}/things'
+# The resource name.
+name: 'Thing'
+description: |
+  A thing.
+
+properties:
+  - name: 'displayName'
+    type: String
+`,
+			want: `# Copyright header
+---
+# The resource name.
+name: 'Thing'
+description: |
+  A thing.
+base_url: 'projects/{{project}}/things'
+
+properties:
+  - name: 'displayName'
+    type: String
+`,
+		},
+		{
+			name: "keeps the dash on the first key of sequence entries",
+			path: "products/foo/Thing.yaml",
+			input: `name: 'Thing'
+properties:
+  - type: String
+    # Comment on name.
+    name: 'displayName'
+  - type: Integer
+    name: 'count'
+`,
+			want: `name: 'Thing'
+properties:
+  # Comment on name.
+  - name: 'displayName'
+    type: String
+  - name: 'count'
+    type: Integer
+`,
+		},
+		{
+			name: "drops keys set to their default",
+			path: "products/foo/Thing.yaml",
+			input: `name: 'Thing'
+exclude: false
+properties:
+  - name: 'displayName'
+    type: String
+    required: false
+    output: true
+`,
+			want: `name: 'Thing'
+properties:
+  - name: 'displayName'
+    type: String
+    output: true
+`,
+		},
+		{
+			name: "keeps defaults that are commented",
+			path: "products/foo/Thing.yaml",
+			input: `name: 'Thing'
+# Explicitly included while the API is in preview.
+exclude: false
+`,
+			want: `name: 'Thing'
+# Explicitly included while the API is in preview.
+exclude: false
+`,
+		},
+		{
+			name: "formats product files as products",
+			path: "products/foo/product.yaml",
+			input: `display_name: 'Foo'
+name: 'Foo'
+versions:
+  - base_url: 'https://foo.googleapis.com/v1/'
+    name: 'ga'
+scopes:
+  - 'https://www.googleapis.com/auth/cloud-platform'
+`,
+			want: `name: 'Foo'
+display_name: 'Foo'
+scopes:
+  - 'https://www.googleapis.com/auth/cloud-platform'
+versions:
+  - name: 'ga'
+    base_url: 'https://foo.googleapis.com/v1/'
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Format(tc.path, []byte(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("Format() mismatch (-want +got):\n%s", diff)
+			}
+
+			again, err := Format(tc.path, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(got), string(again)); diff != "" {
+				t.Errorf("Format() isn't idempotent (-first +second):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatUnknownKey(t *testing.T) {
+	_, err := Format("products/foo/Thing.yaml", []byte("name: 'Thing'\nnot_a_field: true\n"))
+	if err == nil || !strings.Contains(err.Error(), "not_a_field") {
+		t.Errorf("Format() error = %v, want an error naming the unknown key", err)
+	}
+}